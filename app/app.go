@@ -62,6 +62,7 @@ var (
 		auth.FeeCollectorName:     nil,
 		staking.BondedPoolName:    {supply.Burner, supply.Staking},
 		staking.NotBondedPoolName: {supply.Burner, supply.Staking},
+		nymtypes.ModuleName:       {supply.Minter},
 	}
 )
 
@@ -140,6 +141,7 @@ func NewInitApp(
 	app.subspaces[auth.ModuleName] = app.paramsKeeper.Subspace(auth.DefaultParamspace)
 	app.subspaces[bank.ModuleName] = app.paramsKeeper.Subspace(bank.DefaultParamspace)
 	app.subspaces[staking.ModuleName] = app.paramsKeeper.Subspace(staking.DefaultParamspace)
+	app.subspaces[nymtypes.ModuleName] = app.paramsKeeper.Subspace(nymtypes.DefaultParamspace)
 
 	app.accountKeeper = auth.NewAccountKeeper(
 		app.cdc,
@@ -175,8 +177,10 @@ func NewInitApp(
 
 	app.nymKeeper = nymkeeper.NewKeeper(
 		app.bankKeeper,
+		app.supplyKeeper,
 		app.cdc,
 		keys[nymtypes.StoreKey],
+		app.subspaces[nymtypes.ModuleName],
 	)
 
 	// this line is used by starport scaffolding # 4
@@ -191,7 +195,7 @@ func NewInitApp(
 		// this line is used by starport scaffolding # 6
 	)
 
-	app.mm.SetOrderEndBlockers(staking.ModuleName)
+	app.mm.SetOrderEndBlockers(staking.ModuleName, nymtypes.ModuleName)
 
 	app.mm.SetOrderInitGenesis(
 		staking.ModuleName,
@@ -0,0 +1,124 @@
+// Copyright 2020 Nym Technologies SA
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	stdcontext "context"
+	"fmt"
+	"os/signal"
+	"strconv"
+	"syscall"
+
+	"github.com/cosmos/cosmos-sdk/client/context"
+	"github.com/cosmos/cosmos-sdk/client/flags"
+	"github.com/spf13/cobra"
+
+	directoryServer "github.com/nymtech/nym/validator/nym/directory"
+	"github.com/nymtech/nym/validator/nym/directory/mixmining"
+)
+
+func directoryCmd() *cobra.Command {
+	directoryCmd := &cobra.Command{
+		Use:   "directory",
+		Short: "Directory server subcommands",
+	}
+
+	directoryCmd.AddCommand(
+		serveCmd(),
+		migrateCmd(),
+	)
+
+	return directoryCmd
+}
+
+func serveCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "serve",
+		Short: "Run the directory server",
+		Long: `Run the directory server until it receives SIGINT or SIGTERM. It then stops accepting connections,
+lets in-flight requests finish and stops its background workers before exiting.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := directoryServer.LoadConfig(cmd.Flags())
+			if err != nil {
+				return err
+			}
+
+			directory, err := directoryServer.New(context.NewCLIContext(), cfg)
+			if err != nil {
+				return fmt.Errorf("failed to start the directory: %v", err)
+			}
+
+			ctx, stop := signal.NotifyContext(stdcontext.Background(), syscall.SIGINT, syscall.SIGTERM)
+			defer stop()
+			return directory.Run(ctx)
+		},
+	}
+	directoryServer.AddConfigFlags(cmd.Flags())
+	// the node flags of the query commands, through which the directory reads the chain
+	return flags.GetCommands(cmd)[0]
+}
+
+func migrateCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "migrate [version]",
+		Short: "Migrate the directory database schema",
+		Long: fmt.Sprintf(`Migrate the directory database schema up, or down, to the given version.
+Without a version the database is brought to the latest one (%d).`, mixmining.LatestSchemaVersion()),
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			target := mixmining.LatestSchemaVersion()
+			if len(args) == 1 {
+				version, err := strconv.ParseUint(args[0], 10, 32)
+				if err != nil {
+					return fmt.Errorf("invalid schema version %q: %v", args[0], err)
+				}
+				target = uint(version)
+			}
+
+			cfg, err := directoryServer.LoadConfig(cmd.Flags())
+			if err != nil {
+				return err
+			}
+			db, err := mixmining.OpenDb(cfg.Database)
+			if err != nil {
+				return err
+			}
+			defer db.Close()
+			current, err := db.SchemaVersion()
+			if err != nil {
+				return err
+			}
+
+			done, err := db.Migrate(target)
+			for _, migration := range done {
+				if target < current {
+					cmd.Printf("reverted migration %d: %s\n", migration.Version, migration.Description)
+				} else {
+					cmd.Printf("applied migration %d: %s\n", migration.Version, migration.Description)
+				}
+			}
+			if err != nil {
+				return err
+			}
+
+			cmd.Printf("database is at schema version %d\n", target)
+			return nil
+		},
+	}
+	cmd.Flags().String(directoryServer.FlagConfig, "", "Directory config file (toml, yaml or json)")
+	cmd.Flags().String(directoryServer.FlagDatabase, "", "DSN of the directory database, defaults to ~/.nym/mixmining.db")
+	return cmd
+}
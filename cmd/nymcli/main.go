@@ -74,6 +74,7 @@ func main() {
 		txCmd(cdc),
 		flags.LineBreak,
 		lcd.ServeCommand(cdc, registerRoutes),
+		directoryCmd(),
 		flags.LineBreak,
 		keys.Commands(),
 		flags.LineBreak,
@@ -0,0 +1,208 @@
+// Copyright 2020 Nym Technologies SA
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/cosmos/cosmos-sdk/crypto/keys"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/mitchellh/mapstructure"
+	"github.com/nymtech/nym/validator/nym/directory/mixmining"
+	"github.com/nymtech/nym/validator/nym/x/nym/types"
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+)
+
+// DefaultListenAddress is where the directory listens unless configured otherwise
+const DefaultListenAddress = ":8081"
+
+// EnvPrefix prefixes the environment variables overriding the config, a key like
+// service.layers is overridden by NYM_DIRECTORY_SERVICE_LAYERS.
+const EnvPrefix = "NYM_DIRECTORY"
+
+// EnvConfig names the config file when the FlagConfig flag isn't given
+const EnvConfig = EnvPrefix + "_CONFIG"
+
+// Flags overriding the most common settings
+const (
+	FlagConfig        = "directory-config"
+	FlagListenAddress = "directory-listen-address"
+	FlagDatabase      = "directory-database"
+)
+
+// Config of the directory server. It is read from a toml, yaml or json file using the mapstructure keys below.
+type Config struct {
+	ListenAddress string `mapstructure:"listen_address"`
+	// ShutdownTimeout is how long in-flight requests get to finish when the directory is stopped
+	ShutdownTimeout time.Duration `mapstructure:"shutdown_timeout"`
+	// Database is the DSN of the directory database, see mixmining.NewDb for the accepted forms
+	Database     string                       `mapstructure:"database"`
+	Registration mixmining.RegistrationConfig `mapstructure:"registration"`
+	RateLimits   mixmining.RateLimits         `mapstructure:"rate_limits"`
+	Auth         mixmining.AuthConfig         `mapstructure:"auth"`
+	Service      mixmining.ServiceConfig      `mapstructure:"service"`
+	Bridge       BridgeConfig                 `mapstructure:"bridge"`
+}
+
+// BridgeConfig configures the mirroring of registrations onto the chain, enabled by setting Key
+type BridgeConfig struct {
+	// Key is the name of the keyring key that signs and pays the bonds of the mirrored nodes
+	Key            string `mapstructure:"key"`
+	KeyringBackend string `mapstructure:"keyring_backend"`
+	// Home is the directory holding the keyring, defaults to the nymcli home
+	Home string `mapstructure:"home"`
+	// ChainID defaults to the configured nymcli chain id
+	ChainID string `mapstructure:"chain_id"`
+	Fees    string `mapstructure:"fees"`
+	// Bond is escrowed for every mirrored node
+	Bond string `mapstructure:"bond"`
+}
+
+// DefaultConfig returns the configuration used for anything not set
+func DefaultConfig() Config {
+	return Config{
+		ListenAddress:   DefaultListenAddress,
+		ShutdownTimeout: DefaultShutdownTimeout,
+		Registration:    mixmining.DefaultRegistrationConfig(),
+		RateLimits:      mixmining.DefaultRateLimits(),
+		Auth:            mixmining.DefaultAuthConfig(),
+		Service:         mixmining.DefaultServiceConfig(),
+		Bridge: BridgeConfig{
+			KeyringBackend: keys.BackendOS,
+			Bond:           types.DefaultMinimumMixnodeBond.String(),
+		},
+	}
+}
+
+// Validate checks the configuration before anything gets started with it
+func (cfg Config) Validate() error {
+	if _, _, err := net.SplitHostPort(cfg.ListenAddress); err != nil {
+		return fmt.Errorf("invalid listen address %q: %v", cfg.ListenAddress, err)
+	}
+	if cfg.ShutdownTimeout < 0 {
+		return fmt.Errorf("shutdown timeout can't be negative, got %v", cfg.ShutdownTimeout)
+	}
+	if err := cfg.Registration.Validate(); err != nil {
+		return err
+	}
+	if err := cfg.RateLimits.Validate(); err != nil {
+		return err
+	}
+	if err := cfg.Auth.Validate(); err != nil {
+		return err
+	}
+	if err := cfg.Service.Validate(); err != nil {
+		return err
+	}
+	return cfg.Bridge.Validate()
+}
+
+// Validate checks the bridge settings, which are only used once a key is set
+func (cfg BridgeConfig) Validate() error {
+	if cfg.Key == "" {
+		return nil
+	}
+	if cfg.KeyringBackend == "" {
+		return errors.New("no bridge keyring backend configured")
+	}
+	if _, err := sdk.ParseCoins(cfg.Fees); err != nil {
+		return fmt.Errorf("invalid bridge fees %q: %v", cfg.Fees, err)
+	}
+	bond, err := sdk.ParseCoin(cfg.Bond)
+	if err != nil {
+		return fmt.Errorf("invalid bridge bond %q: %v", cfg.Bond, err)
+	}
+	if !bond.IsPositive() {
+		return fmt.Errorf("bridge bond must be positive, got %s", bond)
+	}
+	return nil
+}
+
+// withChainParams replaces the registration rules and the reputation threshold by the chain's nym params, so the
+// directory doesn't accept nodes the chain would then reject when they get mirrored
+func (cfg Config) withChainParams(params types.Params) Config {
+	cfg.Registration.SystemVersion = params.SystemVersion
+	cfg.Registration.MaximumMixnodes = int(params.MaximumMixnodes)
+	cfg.Registration.MaximumGateways = int(params.MaximumGateways)
+	cfg.Service.ReputationThreshold = params.ReputationThreshold
+	return cfg
+}
+
+// AddConfigFlags registers the flags LoadConfig understands
+func AddConfigFlags(flags *pflag.FlagSet) {
+	flags.String(FlagConfig, "", "Directory config file (toml, yaml or json)")
+	flags.String(FlagListenAddress, DefaultListenAddress, "Address the directory listens on")
+	flags.String(FlagDatabase, "", "DSN of the directory database, defaults to ~/.nym/mixmining.db")
+}
+
+// LoadConfig builds the directory configuration from, in increasing priority, the defaults, the config file
+// named by the FlagConfig flag or EnvConfig, NYM_DIRECTORY_* environment variables and the flags.
+// The flags may be nil.
+func LoadConfig(flags *pflag.FlagSet) (Config, error) {
+	v := viper.New()
+
+	var defaults map[string]interface{}
+	if err := mapstructure.Decode(DefaultConfig(), &defaults); err != nil {
+		return Config{}, err
+	}
+	for key, value := range defaults {
+		v.SetDefault(key, value)
+	}
+
+	configFile := os.Getenv(EnvConfig)
+	if flags != nil {
+		if flagConfigFile, err := flags.GetString(FlagConfig); err == nil && flagConfigFile != "" {
+			configFile = flagConfigFile
+		}
+	}
+	if configFile != "" {
+		v.SetConfigFile(configFile)
+		if err := v.ReadInConfig(); err != nil {
+			return Config{}, fmt.Errorf("failed to read directory config: %v", err)
+		}
+	}
+
+	v.SetEnvPrefix(EnvPrefix)
+	v.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
+	v.AutomaticEnv()
+
+	if flags != nil {
+		for flag, key := range map[string]string{
+			FlagListenAddress: "listen_address",
+			FlagDatabase:      "database",
+		} {
+			if f := flags.Lookup(flag); f != nil {
+				if err := v.BindPFlag(key, f); err != nil {
+					return Config{}, err
+				}
+			}
+		}
+	}
+
+	var cfg Config
+	if err := v.Unmarshal(&cfg); err != nil {
+		return Config{}, fmt.Errorf("failed to parse directory config: %v", err)
+	}
+	if err := cfg.Validate(); err != nil {
+		return Config{}, fmt.Errorf("invalid directory config: %v", err)
+	}
+	return cfg, nil
+}
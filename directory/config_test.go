@@ -0,0 +1,201 @@
+// Copyright 2020 Nym Technologies SA
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"io/ioutil"
+	"os"
+	"time"
+
+	"github.com/nymtech/nym/validator/nym/directory/mixmining"
+	"github.com/nymtech/nym/validator/nym/x/nym/types"
+	. "github.com/onsi/ginkgo"
+	"github.com/spf13/pflag"
+	"github.com/stretchr/testify/assert"
+)
+
+const monitorKey = "BnLYqQjb8K6TmW5oFdNZrUTocGxa3rgzBvapQrf8XUbF"
+const otherMonitorKey = "FioFa8nMmPpQnYi7JyojoTuwGLeyNS8BF4ChPr29zUML"
+
+var _ = Describe("Loading the directory config", func() {
+	var flags *pflag.FlagSet
+
+	writeConfig := func(content string) string {
+		file, err := ioutil.TempFile("", "directory-*.toml")
+		assert.Nil(GinkgoT(), err)
+		_, err = file.WriteString(content)
+		assert.Nil(GinkgoT(), err)
+		assert.Nil(GinkgoT(), file.Close())
+		return file.Name()
+	}
+
+	BeforeEach(func() {
+		flags = pflag.NewFlagSet("test", pflag.ContinueOnError)
+		AddConfigFlags(flags)
+	})
+	AfterEach(func() {
+		os.Unsetenv(EnvConfig)
+		os.Unsetenv("NYM_DIRECTORY_SERVICE_LAYERS")
+		os.Unsetenv("NYM_DIRECTORY_DATABASE")
+		os.Unsetenv("NYM_DIRECTORY_AUTH_MONITORS")
+		os.Unsetenv("NYM_DIRECTORY_SERVICE_REPUTATION_POLICY")
+	})
+
+	Context("With nothing configured", func() {
+		It("should use the defaults", func() {
+			cfg, err := LoadConfig(flags)
+			assert.Nil(GinkgoT(), err)
+			assert.Equal(GinkgoT(), DefaultConfig(), cfg)
+		})
+	})
+
+	Context("With a config file", func() {
+		It("should override the defaults with it", func() {
+			file := writeConfig(`
+listen_address = "127.0.0.1:9000"
+
+[service]
+layers = 5
+topology_cache_ttl = "1m"
+
+[rate_limits.topology]
+rate = 0.5
+burst = 10
+`)
+			assert.Nil(GinkgoT(), flags.Set(FlagConfig, file))
+
+			cfg, err := LoadConfig(flags)
+			assert.Nil(GinkgoT(), err)
+			assert.Equal(GinkgoT(), "127.0.0.1:9000", cfg.ListenAddress)
+			assert.Equal(GinkgoT(), uint(5), cfg.Service.Layers)
+			assert.Equal(GinkgoT(), time.Minute, cfg.Service.TopologyCacheTTL)
+			assert.Equal(GinkgoT(), 0.5, cfg.RateLimits.Topology.Rate)
+			assert.Equal(GinkgoT(), 10, cfg.RateLimits.Topology.Burst)
+			// untouched settings keep their defaults
+			assert.Equal(GinkgoT(), DefaultConfig().Registration, cfg.Registration)
+			assert.Equal(GinkgoT(), DefaultConfig().Service.ReputationThreshold, cfg.Service.ReputationThreshold)
+		})
+
+		It("should also find it through the environment", func() {
+			os.Setenv(EnvConfig, writeConfig("[service]\nlayers = 5\n"))
+
+			cfg, err := LoadConfig(nil)
+			assert.Nil(GinkgoT(), err)
+			assert.Equal(GinkgoT(), uint(5), cfg.Service.Layers)
+		})
+
+		It("should select the reputation policy", func() {
+			assert.Nil(GinkgoT(), flags.Set(FlagConfig, writeConfig(`
+[service.reputation]
+policy = "ewma"
+ewma_weight = 0.2
+decay_half_life = "168h"
+`)))
+
+			cfg, err := LoadConfig(flags)
+			assert.Nil(GinkgoT(), err)
+			assert.Equal(GinkgoT(), "ewma", cfg.Service.Reputation.Policy)
+			assert.Equal(GinkgoT(), 0.2, cfg.Service.Reputation.EWMAWeight)
+			assert.Equal(GinkgoT(), 168*time.Hour, cfg.Service.Reputation.DecayHalfLife)
+			assert.Equal(GinkgoT(), mixmining.EWMAReputationMax, cfg.Service.Reputation.EWMAMax)
+		})
+	})
+
+	Context("With environment variables", func() {
+		It("should prefer them over the config file", func() {
+			assert.Nil(GinkgoT(), flags.Set(FlagConfig, writeConfig("[service]\nlayers = 5\n")))
+			os.Setenv("NYM_DIRECTORY_SERVICE_LAYERS", "4")
+
+			cfg, err := LoadConfig(flags)
+			assert.Nil(GinkgoT(), err)
+			assert.Equal(GinkgoT(), uint(4), cfg.Service.Layers)
+		})
+
+		It("should read lists of keys from them", func() {
+			os.Setenv("NYM_DIRECTORY_AUTH_MONITORS", monitorKey+","+otherMonitorKey)
+
+			cfg, err := LoadConfig(flags)
+			assert.Nil(GinkgoT(), err)
+			assert.Equal(GinkgoT(), []string{monitorKey, otherMonitorKey}, cfg.Auth.Monitors)
+		})
+	})
+
+	Context("With flags", func() {
+		It("should prefer them over everything else", func() {
+			os.Setenv("NYM_DIRECTORY_DATABASE", "/tmp/env.db")
+			assert.Nil(GinkgoT(), flags.Set(FlagDatabase, "/tmp/flag.db"))
+			assert.Nil(GinkgoT(), flags.Set(FlagListenAddress, ":9001"))
+
+			cfg, err := LoadConfig(flags)
+			assert.Nil(GinkgoT(), err)
+			assert.Equal(GinkgoT(), "/tmp/flag.db", cfg.Database)
+			assert.Equal(GinkgoT(), ":9001", cfg.ListenAddress)
+		})
+	})
+
+	Context("With invalid settings", func() {
+		It("should reject a mixnet without layers", func() {
+			os.Setenv("NYM_DIRECTORY_SERVICE_LAYERS", "0")
+
+			_, err := LoadConfig(flags)
+			assert.NotNil(GinkgoT(), err)
+		})
+
+		It("should reject an unknown reputation policy", func() {
+			os.Setenv("NYM_DIRECTORY_SERVICE_REPUTATION_POLICY", "lenient")
+
+			_, err := LoadConfig(flags)
+			assert.NotNil(GinkgoT(), err)
+		})
+
+		It("should reject a listen address without a port", func() {
+			assert.Nil(GinkgoT(), flags.Set(FlagListenAddress, "localhost"))
+
+			_, err := LoadConfig(flags)
+			assert.NotNil(GinkgoT(), err)
+		})
+
+		It("should reject monitor keys that aren't ed25519 keys", func() {
+			os.Setenv("NYM_DIRECTORY_AUTH_MONITORS", "notakey")
+
+			_, err := LoadConfig(flags)
+			assert.NotNil(GinkgoT(), err)
+		})
+
+		It("should reject an invalid bridge bond once the bridge is enabled", func() {
+			cfg := DefaultConfig()
+			cfg.Bridge.Bond = "lots"
+			assert.Nil(GinkgoT(), cfg.Validate())
+
+			cfg.Bridge.Key = "directory"
+			assert.NotNil(GinkgoT(), cfg.Validate())
+		})
+	})
+})
+
+var _ = Describe("Applying the chain params", func() {
+	It("should replace the registration rules and the reputation threshold", func() {
+		params := types.DefaultParams()
+		params.SystemVersion = "1.0.0"
+		params.MaximumMixnodes = 10
+		params.MaximumGateways = 5
+		params.ReputationThreshold = 42
+
+		cfg := DefaultConfig().withChainParams(params)
+		assert.Equal(GinkgoT(), mixmining.RegistrationConfig{SystemVersion: "1.0.0", MaximumMixnodes: 10, MaximumGateways: 5}, cfg.Registration)
+		assert.Equal(GinkgoT(), int64(42), cfg.Service.ReputationThreshold)
+		assert.Nil(GinkgoT(), cfg.Validate())
+	})
+})
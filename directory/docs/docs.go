@@ -1,6 +1,6 @@
 // GENERATED BY THE COMMAND ABOVE; DO NOT EDIT
 // This file was generated by swaggo/swag at
-// 2020-12-02 10:57:49.0208651 +0000 GMT m=+0.422064401
+// 2026-10-16 19:26:11.272363395 +0000 UTC m=+0.071988641
 
 package docs
 
@@ -72,6 +72,27 @@ var doc = `{
                         "schema": {
                             "$ref": "#/definitions/models.MixStatus"
                         }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Base58 identity key of the monitor",
+                        "name": "X-Nym-Identity",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Unix nanoseconds the request was signed at",
+                        "name": "X-Nym-Timestamp",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Base58 ed25519 signature of the request",
+                        "name": "X-Nym-Signature",
+                        "in": "header",
+                        "required": true
                     }
                 ],
                 "responses": {
@@ -82,6 +103,12 @@ var doc = `{
                             "$ref": "#/definitions/models.Error"
                         }
                     },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.Error"
+                        }
+                    },
                     "403": {
                         "description": "Forbidden",
                         "schema": {
@@ -126,6 +153,27 @@ var doc = `{
                         "schema": {
                             "$ref": "#/definitions/models.BatchMixStatus"
                         }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Base58 identity key of the monitor",
+                        "name": "X-Nym-Identity",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Unix nanoseconds the request was signed at",
+                        "name": "X-Nym-Timestamp",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Base58 ed25519 signature of the request",
+                        "name": "X-Nym-Signature",
+                        "in": "header",
+                        "required": true
                     }
                 ],
                 "responses": {
@@ -136,6 +184,12 @@ var doc = `{
                             "$ref": "#/definitions/models.Error"
                         }
                     },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.Error"
+                        }
+                    },
                     "403": {
                         "description": "Forbidden",
                         "schema": {
@@ -159,7 +213,7 @@ var doc = `{
         },
         "/api/mixmining/fullreport": {
             "get": {
-                "description": "Provides summary uptime statistics for last 5 minutes, day, week, and month",
+                "description": "Provides summary uptime statistics for the last 5 minutes, hour, day, 30 days and 90 days. The uptime of a period during which the node wasn't tested is -1.",
                 "consumes": [
                     "application/json"
                 ],
@@ -250,7 +304,7 @@ var doc = `{
         },
         "/api/mixmining/node/{pubkey}/report": {
             "get": {
-                "description": "Provides summary uptime statistics for last 5 minutes, day, week, and month",
+                "description": "Provides summary uptime statistics for the last 5 minutes, hour, day, 30 days and 90 days. The uptime of a period during which the node wasn't tested is -1.",
                 "consumes": [
                     "application/json"
                 ],
@@ -294,9 +348,114 @@ var doc = `{
                 }
             }
         },
+        "/api/mixmining/node/{pubkey}/reputation/history": {
+            "get": {
+                "description": "Lists the 1000 most recent changes of the reputation of a node, newest first, with the reason of each of them: a monitor report, an admin override or the decay of reputations. The ledger is append-only, changes are never removed.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "mixmining"
+                ],
+                "summary": "Lists the changes of the reputation of a node",
+                "operationId": "getReputationHistory",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Node Pubkey",
+                        "name": "pubkey",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.ReputationChange"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/mixmining/node/{pubkey}/uptime": {
+            "get": {
+                "description": "Splits the requested range into buckets of 5 minutes, 1 hour or 1 day, aligned on multiples of their size since the unix epoch, and computes the uptime percentage of the node in each of them. Buckets during which the node wasn't tested have an uptime of -1. Hourly and daily buckets older than the statuses, which are kept for a week, are taken from the hourly and daily rollups of their statuses.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "mixmining"
+                ],
+                "summary": "Charts the uptime of a mixnode over time",
+                "operationId": "getUptimeSeries",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Mixnode Pubkey",
+                        "name": "pubkey",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Start of the range in unix nanoseconds, defaults to a day before to",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "End of the range in unix nanoseconds, defaults to now",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "4 or 6, defaults to 4",
+                        "name": "ipVersion",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "5m, 1h or 1d, defaults to 1h",
+                        "name": "bucket",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.UptimeSeries"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Error"
+                        }
+                    }
+                }
+            }
+        },
         "/api/mixmining/register/gateway": {
             "post": {
-                "description": "On Nym nodes startup they register their presence indicating they should be alive and get added to the set of active nodes in the topology.",
+                "description": "On Nym nodes startup they register their presence indicating they should be alive and get added to the set of active nodes in the topology. Unlike mixnodes, gateways aren't assigned a layer.",
                 "consumes": [
                     "application/json"
                 ],
@@ -350,7 +509,7 @@ var doc = `{
         },
         "/api/mixmining/register/mix": {
             "post": {
-                "description": "On Nym nodes startup they register their presence indicating they should be alive and get added to the set of active nodes in the topology.",
+                "description": "On Nym nodes startup they register their presence indicating they should be alive and get added to the set of active nodes in the topology. The directory assigns the mixnode its layer, which is returned in the response.",
                 "consumes": [
                     "application/json"
                 ],
@@ -433,6 +592,12 @@ var doc = `{
                             "$ref": "#/definitions/models.Error"
                         }
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.Error"
+                        }
+                    },
                     "404": {
                         "description": "Not Found",
                         "schema": {
@@ -476,6 +641,27 @@ var doc = `{
                         "name": "reputation",
                         "in": "query",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Base58 identity key of the admin",
+                        "name": "X-Nym-Identity",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Unix nanoseconds the request was signed at",
+                        "name": "X-Nym-Timestamp",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Base58 ed25519 signature of the request",
+                        "name": "X-Nym-Signature",
+                        "in": "header",
+                        "required": true
                     }
                 ],
                 "responses": {
@@ -486,6 +672,12 @@ var doc = `{
                             "$ref": "#/definitions/models.Error"
                         }
                     },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.Error"
+                        }
+                    },
                     "403": {
                         "description": "Forbidden",
                         "schema": {
@@ -561,6 +753,95 @@ var doc = `{
                 }
             }
         },
+        "/api/mixmining/topology/layers": {
+            "get": {
+                "description": "Dry run of the layer rebalance: lists the mixnodes that would change layer to even out the number of mixnodes and their reputation across layers, along with the layers' load before and after.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "mixmining"
+                ],
+                "summary": "Shows how mixnodes would be moved between layers by a rebalance",
+                "operationId": "getLayerRebalance",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/types.LayerRebalance"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/mixmining/topology/layers/rebalance": {
+            "post": {
+                "description": "Moves mixnodes between layers to even out the number of mixnodes and their reputation across layers. Rebalancing also happens periodically unless the chain bridge is enabled, this triggers it immediately. The moves are not mirrored onto the chain, which owns the layers of the mixnodes it knows.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "mixmining"
+                ],
+                "summary": "Rebalances the mixnet layers",
+                "operationId": "rebalanceLayers",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Base58 identity key of the admin",
+                        "name": "X-Nym-Identity",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Unix nanoseconds the request was signed at",
+                        "name": "X-Nym-Timestamp",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Base58 ed25519 signature of the request",
+                        "name": "X-Nym-Signature",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/types.LayerRebalance"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.Error"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Error"
+                        }
+                    }
+                }
+            }
+        },
         "/api/mixmining/topology/removed": {
             "get": {
                 "description": "On Nym nodes startup they register their presence indicating they should be alive.",
@@ -587,6 +868,36 @@ var doc = `{
                     }
                 }
             }
+        },
+        "/api/mixmining/workers": {
+            "get": {
+                "description": "Lists the background workers of the directory (validators refresh, reports refresh, statuses rollup and purge, layer rebalance unless the chain bridge is enabled and, if enabled, reputation decay, the chain bridge and its reconciliation) with when they last ran, how long it took and the error it failed with, if any.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "mixmining"
+                ],
+                "summary": "Shows how the directory background workers are doing",
+                "operationId": "getWorkerStatuses",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.WorkerStatus"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Error"
+                        }
+                    }
+                }
+            }
         }
     },
     "definitions": {
@@ -649,7 +960,6 @@ var doc = `{
             "type": "object",
             "required": [
                 "identityKey",
-                "layer",
                 "mixHost",
                 "sphinxKey",
                 "version"
@@ -662,6 +972,7 @@ var doc = `{
                     "type": "string"
                 },
                 "layer": {
+                    "description": "Layer is assigned by the directory. It is no longer required on registration, whatever the node announces being ignored",
                     "type": "integer"
                 },
                 "location": {
@@ -740,7 +1051,6 @@ var doc = `{
             "type": "object",
             "required": [
                 "identityKey",
-                "layer",
                 "mixHost",
                 "sphinxKey",
                 "version"
@@ -753,6 +1063,7 @@ var doc = `{
                     "type": "string"
                 },
                 "layer": {
+                    "description": "Layer is assigned by the directory. It is no longer required on registration, whatever the node announces being ignored",
                     "type": "integer"
                 },
                 "location": {
@@ -775,6 +1086,36 @@ var doc = `{
                 }
             }
         },
+        "models.ReputationChange": {
+            "type": "object",
+            "required": [
+                "delta",
+                "pubKey",
+                "reason",
+                "reputation",
+                "timestamp"
+            ],
+            "properties": {
+                "delta": {
+                    "description": "Delta is how much the reputation changed by",
+                    "type": "integer"
+                },
+                "pubKey": {
+                    "type": "string"
+                },
+                "reason": {
+                    "description": "Reason is either monitor_report, admin_override or decay",
+                    "type": "string"
+                },
+                "reputation": {
+                    "description": "Reputation is the reputation of the node after the change",
+                    "type": "integer"
+                },
+                "timestamp": {
+                    "type": "integer"
+                }
+            }
+        },
         "models.Topology": {
             "type": "object",
             "required": [
@@ -798,6 +1139,148 @@ var doc = `{
                     "type": "string"
                 }
             }
+        },
+        "models.UptimeBucket": {
+            "type": "object",
+            "required": [
+                "end",
+                "reports",
+                "start",
+                "uptime"
+            ],
+            "properties": {
+                "end": {
+                    "description": "End of the bucket, excluded, in unix nanoseconds",
+                    "type": "integer"
+                },
+                "reports": {
+                    "description": "Reports is the number of statuses the uptime is computed from",
+                    "type": "integer"
+                },
+                "start": {
+                    "description": "Start of the bucket, included, in unix nanoseconds",
+                    "type": "integer"
+                },
+                "uptime": {
+                    "description": "Uptime percentage over the bucket, -1 if the node wasn't tested during it",
+                    "type": "integer"
+                }
+            }
+        },
+        "models.UptimeSeries": {
+            "type": "object",
+            "required": [
+                "bucket",
+                "buckets",
+                "ipVersion",
+                "pubKey"
+            ],
+            "properties": {
+                "bucket": {
+                    "type": "string"
+                },
+                "buckets": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.UptimeBucket"
+                    }
+                },
+                "ipVersion": {
+                    "type": "string"
+                },
+                "pubKey": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.WorkerStatus": {
+            "type": "object",
+            "required": [
+                "interval",
+                "lastDuration",
+                "lastRun",
+                "name",
+                "running",
+                "runs"
+            ],
+            "properties": {
+                "interval": {
+                    "description": "Interval between two runs in nanoseconds, 0 for workers that run whenever they have work queued",
+                    "type": "integer"
+                },
+                "lastDuration": {
+                    "description": "LastDuration of the last run, in nanoseconds",
+                    "type": "integer"
+                },
+                "lastError": {
+                    "description": "LastError is the error the last run failed with, empty if it succeeded",
+                    "type": "string"
+                },
+                "lastRun": {
+                    "description": "LastRun is the timestamp the last run started at, 0 if the worker hasn't run yet",
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "running": {
+                    "type": "boolean"
+                },
+                "runs": {
+                    "type": "integer"
+                }
+            }
+        },
+        "types.LayerLoad": {
+            "type": "object",
+            "properties": {
+                "layer": {
+                    "type": "integer"
+                },
+                "nodes": {
+                    "type": "integer"
+                },
+                "weight": {
+                    "type": "string"
+                }
+            }
+        },
+        "types.LayerMove": {
+            "type": "object",
+            "properties": {
+                "from": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "to": {
+                    "type": "integer"
+                }
+            }
+        },
+        "types.LayerRebalance": {
+            "type": "object",
+            "properties": {
+                "after": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/types.LayerLoad"
+                    }
+                },
+                "before": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/types.LayerLoad"
+                    }
+                },
+                "moves": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/types.LayerMove"
+                    }
+                }
+            }
         }
     }
 }`
@@ -0,0 +1,210 @@
+// Copyright 2020 Nym Technologies SA
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mixmining
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/BorisBorshevsky/timemock"
+	"github.com/btcsuite/btcutil/base58"
+	"github.com/gin-gonic/gin"
+)
+
+// Signed requests carry the base58 encoded ed25519 identity key of the signer, the time the request was signed
+// at in unix nanoseconds, and the base58 encoded signature of the message built by RequestSigningMessage.
+const (
+	HeaderIdentity  = "X-Nym-Identity"
+	HeaderTimestamp = "X-Nym-Timestamp"
+	HeaderSignature = "X-Nym-Signature"
+)
+
+// MaxClockSkew is how far the timestamp of a signed request can be from the directory clock,
+// it bounds how long the directory has to remember the requests it received to reject replays
+const MaxClockSkew = time.Minute
+
+// MaxSignedBodySize bounds the body of signed requests, which is read before its signature can be checked,
+// once the signer is known to be allowed.
+// It leaves room for a batch of statuses of every mixnode.
+const MaxSignedBodySize = 1 << 20
+
+// identityContextKey is the key of the authenticated identity in the gin context
+const identityContextKey = "identity"
+
+// AuthConfig lists the keys allowed to call the restricted endpoints
+type AuthConfig struct {
+	// Monitors are the base58 identity keys of the network monitors allowed to submit mix statuses
+	Monitors []string `mapstructure:"monitors"`
+	// Admins are the base58 identity keys allowed to override reputation and rebalance the layers
+	Admins       []string      `mapstructure:"admins"`
+	MaxClockSkew time.Duration `mapstructure:"max_clock_skew"`
+}
+
+// DefaultAuthConfig returns a configuration with no key allowed, so the restricted endpoints are closed
+func DefaultAuthConfig() AuthConfig {
+	return AuthConfig{MaxClockSkew: MaxClockSkew}
+}
+
+// Validate checks all the allowed keys are ed25519 public keys
+func (cfg AuthConfig) Validate() error {
+	for _, key := range append(append([]string{}, cfg.Monitors...), cfg.Admins...) {
+		if _, err := decodeIdentity(key); err != nil {
+			return err
+		}
+	}
+	if cfg.MaxClockSkew <= 0 {
+		return fmt.Errorf("max clock skew must be positive, got %v", cfg.MaxClockSkew)
+	}
+	return nil
+}
+
+// RequestSigningMessage is the message signed to authenticate a request
+func RequestSigningMessage(method string, uri string, timestamp int64, body []byte) []byte {
+	message := fmt.Sprintf("%s\n%s\n%d\n", method, uri, timestamp)
+	return append([]byte(message), body...)
+}
+
+// SignRequest authenticates the request with the given key, body must be the request body
+func SignRequest(req *http.Request, body []byte, key ed25519.PrivateKey) {
+	timestamp := timemock.Now().UnixNano()
+	signature := ed25519.Sign(key, RequestSigningMessage(req.Method, req.URL.RequestURI(), timestamp, body))
+	req.Header.Set(HeaderIdentity, base58.Encode(key.Public().(ed25519.PublicKey)))
+	req.Header.Set(HeaderTimestamp, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(HeaderSignature, base58.Encode(signature))
+}
+
+func decodeIdentity(key string) (ed25519.PublicKey, error) {
+	decoded := base58.Decode(key)
+	if len(decoded) != ed25519.PublicKeySize {
+		return nil, fmt.Errorf("identity %q is not a base58 encoded ed25519 public key", key)
+	}
+	return decoded, nil
+}
+
+// replayGuard remembers the signatures seen within the clock skew window, so a captured request can't be sent again
+type replayGuard struct {
+	sync.Mutex
+	maxClockSkew time.Duration
+	// seen maps the identity, timestamp and signature of the accepted requests to the time they expire at
+	seen map[string]time.Time
+}
+
+func newReplayGuard(maxClockSkew time.Duration) *replayGuard {
+	return &replayGuard{maxClockSkew: maxClockSkew, seen: make(map[string]time.Time)}
+}
+
+// check records the request and fails if it was already seen
+func (guard *replayGuard) check(identity string, timestamp int64, signature string) error {
+	guard.Lock()
+	defer guard.Unlock()
+
+	now := timemock.Now()
+	for key, expiry := range guard.seen {
+		if now.After(expiry) {
+			delete(guard.seen, key)
+		}
+	}
+
+	key := fmt.Sprintf("%s\n%d\n%s", identity, timestamp, signature)
+	if _, seen := guard.seen[key]; seen {
+		return errors.New("the request was already received")
+	}
+	// past that time the timestamp is rejected as too old anyway
+	guard.seen[key] = time.Unix(0, timestamp).Add(guard.maxClockSkew)
+	return nil
+}
+
+// errForbidden is returned for requests signed by a key that isn't allowed
+var errForbidden = errors.New("forbidden")
+
+// verifyRequest checks the request is signed by one of the allowed keys and returns the identity that signed it.
+// The body is only read once the identity is known to be allowed.
+func verifyRequest(w http.ResponseWriter, req *http.Request, allowed map[string]bool, guard *replayGuard) (string, error) {
+	identity := req.Header.Get(HeaderIdentity)
+	if identity == "" {
+		return "", errors.New("the request is not signed")
+	}
+	key, err := decodeIdentity(identity)
+	if err != nil {
+		return "", err
+	}
+	identity = base58.Encode(key)
+	if !allowed[identity] {
+		return "", errForbidden
+	}
+
+	timestamp, err := strconv.ParseInt(req.Header.Get(HeaderTimestamp), 10, 64)
+	if err != nil {
+		return "", fmt.Errorf("invalid request timestamp: %v", err)
+	}
+	skew := time.Duration(timemock.Now().UnixNano() - timestamp)
+	if skew > guard.maxClockSkew || skew < -guard.maxClockSkew {
+		return "", errors.New("the request timestamp is too far from the directory time")
+	}
+
+	var body []byte
+	if req.Body != nil {
+		if body, err = ioutil.ReadAll(http.MaxBytesReader(w, req.Body, MaxSignedBodySize)); err != nil {
+			return "", err
+		}
+		req.Body = ioutil.NopCloser(bytes.NewReader(body))
+	}
+	signature := base58.Decode(req.Header.Get(HeaderSignature))
+	if !ed25519.Verify(key, RequestSigningMessage(req.Method, req.URL.RequestURI(), timestamp, body), signature) {
+		return "", errors.New("invalid request signature")
+	}
+	if err := guard.check(identity, timestamp, base58.Encode(signature)); err != nil {
+		return "", err
+	}
+	return identity, nil
+}
+
+// authenticate only lets through requests signed by one of the allowed keys, recording who signed them
+func authenticate(allowed []string, maxClockSkew time.Duration) gin.HandlerFunc {
+	allowedSet := make(map[string]bool, len(allowed))
+	for _, key := range allowed {
+		// keys are normalized so that they compare equal however they were written
+		if decoded, err := decodeIdentity(key); err == nil {
+			allowedSet[base58.Encode(decoded)] = true
+		}
+	}
+	guard := newReplayGuard(maxClockSkew)
+
+	return func(ctx *gin.Context) {
+		identity, err := verifyRequest(ctx.Writer, ctx.Request, allowedSet, guard)
+		var tooLarge *http.MaxBytesError
+		if errors.Is(err, errForbidden) {
+			ctx.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			return
+		}
+		if errors.As(err, &tooLarge) {
+			ctx.AbortWithStatusJSON(http.StatusRequestEntityTooLarge, gin.H{"error": err.Error()})
+			return
+		}
+		if err != nil {
+			ctx.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+			return
+		}
+		ctx.Set(identityContextKey, identity)
+		ctx.Next()
+	}
+}
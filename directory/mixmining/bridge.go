@@ -0,0 +1,337 @@
+// Copyright 2020 Nym Technologies SA
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mixmining
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+
+	"github.com/cosmos/cosmos-sdk/client/context"
+	"github.com/cosmos/cosmos-sdk/client/flags"
+	"github.com/cosmos/cosmos-sdk/crypto/keys"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/x/auth/client/utils"
+	authtypes "github.com/cosmos/cosmos-sdk/x/auth/types"
+	"github.com/nymtech/nym/validator/nym/directory/models"
+	"github.com/nymtech/nym/validator/nym/x/nym/types"
+)
+
+// chainQueryPageSize is the number of nodes requested per page when listing the bridge's nodes on chain
+const chainQueryPageSize = 100
+
+// IChainBridge mirrors directory registrations and unregistrations onto the chain
+type IChainBridge interface {
+	RegisterMix(info models.MixRegistrationInfo) error
+	RegisterGateway(info models.GatewayRegistrationInfo) error
+	UnregisterNode(identityKey string) error
+	Reconcile(topology models.Topology) error
+}
+
+// ChainBridgeConfig holds what the bridge needs to sign and broadcast transactions
+type ChainBridgeConfig struct {
+	// KeyName is the name of the key, in the keyring, that signs and pays the bonds of the mirrored nodes
+	KeyName        string
+	KeyringBackend string
+	// Home is the directory holding the keyring
+	Home    string
+	ChainID string
+	Fees    string
+	// Bond is escrowed for every mirrored node
+	Bond sdk.Coin
+}
+
+// ChainBridge is an IChainBridge broadcasting signed nym transactions through a validator.
+// All nodes it creates are owned by the configured key, so it never touches nodes registered on chain directly.
+type ChainBridge struct {
+	cliCtx context.CLIContext
+	txBldr authtypes.TxBuilder
+	bond   sdk.Coin
+
+	// transactions are built from the on-chain account sequence, so they must be broadcast one at a time
+	sync.Mutex
+}
+
+// NewChainBridge constructor
+func NewChainBridge(cliCtx context.CLIContext, cfg ChainBridgeConfig) (*ChainBridge, error) {
+	if cfg.KeyName == "" {
+		return nil, errors.New("no bridge key configured")
+	}
+	if cfg.ChainID == "" {
+		return nil, errors.New("no chain id configured")
+	}
+	if !cfg.Bond.IsValid() || !cfg.Bond.IsPositive() {
+		return nil, fmt.Errorf("invalid bridge bond %s", cfg.Bond)
+	}
+
+	keybase, err := keys.NewKeyring(sdk.KeyringServiceName(), cfg.KeyringBackend, cfg.Home, bufio.NewReader(os.Stdin))
+	if err != nil {
+		return nil, err
+	}
+	info, err := keybase.Get(cfg.KeyName)
+	if err != nil {
+		return nil, err
+	}
+
+	txBldr := authtypes.NewTxBuilder(utils.GetTxEncoder(cliCtx.Codec), 0, 0, flags.DefaultGasLimit, flags.DefaultGasAdjustment, false, cfg.ChainID, "", nil, nil).
+		WithKeybase(keybase)
+	if cfg.Fees != "" {
+		txBldr = txBldr.WithFees(cfg.Fees)
+	}
+
+	return &ChainBridge{
+		cliCtx: cliCtx.
+			WithChainID(cfg.ChainID).
+			WithFromName(info.GetName()).
+			WithFromAddress(info.GetAddress()).
+			WithBroadcastMode(flags.BroadcastBlock),
+		txBldr: txBldr,
+		bond:   cfg.Bond,
+	}, nil
+}
+
+// RegisterMix creates the mixnode on chain, or updates it if the bridge already created it.
+// Creating a node someone else registered on chain is rejected by the chain as a duplicate identity.
+// The chain owns the layers: a mixnode is created on the layer assigned by the directory, but updates keep
+// whatever layer the chain moved it to.
+func (bridge *ChainBridge) RegisterMix(info models.MixRegistrationInfo) error {
+	mixnodes, err := bridge.listMixnodes()
+	if err != nil {
+		return err
+	}
+	existing, ok := mixnodes[info.IdentityKey]
+	if !ok {
+		return bridge.createMix(info)
+	}
+	return bridge.updateMix(existing, info)
+}
+
+func (bridge *ChainBridge) createMix(info models.MixRegistrationInfo) error {
+	return bridge.broadcast(types.NewMsgCreateMixnode(bridge.cliCtx.FromAddress, info.IdentityKey, info.SphinxKey,
+		int32(info.Layer), info.Version, info.MixHost, info.Location, bridge.bond))
+}
+
+func (bridge *ChainBridge) updateMix(existing types.Mixnode, info models.MixRegistrationInfo) error {
+	return bridge.broadcast(types.NewMsgSetMixnode(bridge.cliCtx.FromAddress, existing.ID, info.IdentityKey, info.SphinxKey,
+		existing.Layer, info.Version, info.MixHost, info.Location))
+}
+
+// RegisterGateway creates the gateway on chain, or updates it if the bridge already created it
+func (bridge *ChainBridge) RegisterGateway(info models.GatewayRegistrationInfo) error {
+	gateways, err := bridge.listGateways()
+	if err != nil {
+		return err
+	}
+	existing, ok := gateways[info.IdentityKey]
+	if !ok {
+		return bridge.createGateway(info)
+	}
+	return bridge.updateGateway(existing.ID, info)
+}
+
+func (bridge *ChainBridge) createGateway(info models.GatewayRegistrationInfo) error {
+	return bridge.broadcast(types.NewMsgCreateGateway(bridge.cliCtx.FromAddress, info.IdentityKey, info.SphinxKey,
+		info.ClientsHost, info.MixHost, info.Location, bridge.bond))
+}
+
+func (bridge *ChainBridge) updateGateway(id string, info models.GatewayRegistrationInfo) error {
+	return bridge.broadcast(types.NewMsgSetGateway(bridge.cliCtx.FromAddress, id, info.IdentityKey, info.SphinxKey,
+		info.ClientsHost, info.MixHost, info.Location))
+}
+
+// UnregisterNode deletes the mixnode or gateway with the given identity key from the chain,
+// provided it was created by the bridge
+func (bridge *ChainBridge) UnregisterNode(identityKey string) error {
+	mixnodes, err := bridge.listMixnodes()
+	if err != nil {
+		return err
+	}
+	if mixnode, ok := mixnodes[identityKey]; ok {
+		return bridge.broadcast(types.NewMsgDeleteMixnode(mixnode.ID, bridge.cliCtx.FromAddress))
+	}
+
+	gateways, err := bridge.listGateways()
+	if err != nil {
+		return err
+	}
+	if gateway, ok := gateways[identityKey]; ok {
+		return bridge.broadcast(types.NewMsgDeleteGateway(gateway.ID, bridge.cliCtx.FromAddress))
+	}
+	return nil
+}
+
+// Reconcile makes the nodes owned by the bridge on chain match the given directory topology:
+// missing nodes are created, changed ones updated and the ones no longer in the directory deleted.
+// It keeps going on errors so a single bad node doesn't stop the rest from converging.
+func (bridge *ChainBridge) Reconcile(topology models.Topology) error {
+	chainMixnodes, err := bridge.listMixnodes()
+	if err != nil {
+		return err
+	}
+	chainGateways, err := bridge.listGateways()
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, mix := range topology.MixNodes {
+		chainMixnode, ok := chainMixnodes[mix.IdentityKey]
+		delete(chainMixnodes, mix.IdentityKey)
+
+		var err error
+		if !ok {
+			err = bridge.createMix(mix.MixRegistrationInfo)
+		} else if !mixnodeInSync(chainMixnode, mix.MixRegistrationInfo) {
+			err = bridge.updateMix(chainMixnode, mix.MixRegistrationInfo)
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("mixnode %s: %v", mix.IdentityKey, err))
+		}
+	}
+	for _, gateway := range topology.Gateways {
+		chainGateway, ok := chainGateways[gateway.IdentityKey]
+		delete(chainGateways, gateway.IdentityKey)
+
+		var err error
+		if !ok {
+			err = bridge.createGateway(gateway.GatewayRegistrationInfo)
+		} else if !gatewayInSync(chainGateway, gateway.GatewayRegistrationInfo) {
+			err = bridge.updateGateway(chainGateway.ID, gateway.GatewayRegistrationInfo)
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("gateway %s: %v", gateway.IdentityKey, err))
+		}
+	}
+
+	// whatever is left on chain is no longer registered with the directory
+	for identityKey, mixnode := range chainMixnodes {
+		if err := bridge.broadcast(types.NewMsgDeleteMixnode(mixnode.ID, bridge.cliCtx.FromAddress)); err != nil {
+			errs = append(errs, fmt.Errorf("mixnode %s: %v", identityKey, err))
+		}
+	}
+	for identityKey, gateway := range chainGateways {
+		if err := bridge.broadcast(types.NewMsgDeleteGateway(gateway.ID, bridge.cliCtx.FromAddress)); err != nil {
+			errs = append(errs, fmt.Errorf("gateway %s: %v", identityKey, err))
+		}
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("failed to reconcile %d node(s), first error: %v", len(errs), errs[0])
+	}
+	return nil
+}
+
+// mixnodeInSync ignores the layer, which is owned by the chain
+func mixnodeInSync(mixnode types.Mixnode, info models.MixRegistrationInfo) bool {
+	return mixnode.SphinxKey == info.SphinxKey &&
+		mixnode.Version == info.Version &&
+		mixnode.Host == info.MixHost &&
+		mixnode.Location == info.Location
+}
+
+func gatewayInSync(gateway types.Gateway, info models.GatewayRegistrationInfo) bool {
+	return gateway.SphinxKey == info.SphinxKey &&
+		gateway.ClientListener == info.ClientsHost &&
+		gateway.MixnetListener == info.MixHost &&
+		gateway.Location == info.Location
+}
+
+// broadcast signs the message with the bridge key and waits for it to be included in a block
+func (bridge *ChainBridge) broadcast(msg sdk.Msg) error {
+	if err := msg.ValidateBasic(); err != nil {
+		return err
+	}
+
+	bridge.Lock()
+	defer bridge.Unlock()
+
+	txBldr, err := utils.PrepareTxBuilder(bridge.txBldr, bridge.cliCtx)
+	if err != nil {
+		return err
+	}
+	// keyring backed keybases ignore the passphrase
+	txBytes, err := txBldr.BuildAndSign(bridge.cliCtx.FromName, "", []sdk.Msg{msg})
+	if err != nil {
+		return err
+	}
+	res, err := bridge.cliCtx.BroadcastTx(txBytes)
+	if err != nil {
+		return err
+	}
+	if res.Code != 0 {
+		return fmt.Errorf("%s transaction failed: %s", msg.Type(), res.RawLog)
+	}
+	return nil
+}
+
+// listMixnodes returns the mixnodes created on chain by the bridge, keyed by identity key
+func (bridge *ChainBridge) listMixnodes() (map[string]types.Mixnode, error) {
+	mixnodes := make(map[string]types.Mixnode)
+	for page := 1; ; page++ {
+		params := types.NewQueryMixnodesParams(page, chainQueryPageSize, 0, "", "", bridge.cliCtx.FromAddress)
+		var out []types.Mixnode
+		if err := bridge.query(types.QueryListMixnode, params, &out); err != nil {
+			return nil, err
+		}
+		for _, mixnode := range out {
+			mixnodes[mixnode.PubKey] = mixnode
+		}
+		if len(out) < chainQueryPageSize {
+			return mixnodes, nil
+		}
+	}
+}
+
+// listGateways returns the gateways created on chain by the bridge, keyed by identity key
+func (bridge *ChainBridge) listGateways() (map[string]types.Gateway, error) {
+	gateways := make(map[string]types.Gateway)
+	for page := 1; ; page++ {
+		params := types.NewQueryGatewaysParams(page, chainQueryPageSize, "", bridge.cliCtx.FromAddress)
+		var out []types.Gateway
+		if err := bridge.query(types.QueryListGateway, params, &out); err != nil {
+			return nil, err
+		}
+		for _, gateway := range out {
+			gateways[gateway.IdentityKey] = gateway
+		}
+		if len(out) < chainQueryPageSize {
+			return gateways, nil
+		}
+	}
+}
+
+// Params returns the nym module parameters of the chain
+func (bridge *ChainBridge) Params() (types.Params, error) {
+	var params types.Params
+	res, _, err := bridge.cliCtx.QueryWithData(fmt.Sprintf("custom/%s/%s", types.QuerierRoute, types.QueryParams), nil)
+	if err != nil {
+		return params, err
+	}
+	return params, bridge.cliCtx.Codec.UnmarshalJSON(res, &params)
+}
+
+func (bridge *ChainBridge) query(route string, params interface{}, out interface{}) error {
+	bz, err := bridge.cliCtx.Codec.MarshalJSON(params)
+	if err != nil {
+		return err
+	}
+	res, _, err := bridge.cliCtx.QueryWithData(fmt.Sprintf("custom/%s/%s", types.QuerierRoute, route), bz)
+	if err != nil {
+		return err
+	}
+	return bridge.cliCtx.Codec.UnmarshalJSON(res, out)
+}
@@ -15,6 +15,7 @@
 package mixmining
 
 import (
+	"errors"
 	"fmt"
 	"github.com/gin-gonic/gin"
 	"github.com/nymtech/nym/validator/nym/directory/models"
@@ -22,6 +23,9 @@ import (
 	"os"
 	"strconv"
 	"sync"
+	"time"
+
+	"github.com/BorisBorshevsky/timemock"
 	"github.com/didip/tollbooth"
 	"github.com/didip/tollbooth_gin"
 )
@@ -38,6 +42,84 @@ type Config struct {
 	GenericSanitizer GenericSanitizer // originally introduced for what was in mix registration
 	Sanitizer        Sanitizer        // mix reports
 	Service          IService
+	Registration     RegistrationConfig
+	RateLimits       RateLimits
+	Auth             AuthConfig
+}
+
+// RegistrationConfig decides which nodes are allowed to register
+type RegistrationConfig struct {
+	// SystemVersion is the only node version accepted, nodes running any other one are purged on startup
+	SystemVersion   string `mapstructure:"system_version"`
+	MaximumMixnodes int    `mapstructure:"maximum_mixnodes"`
+	MaximumGateways int    `mapstructure:"maximum_gateways"`
+}
+
+// DefaultRegistrationConfig returns the registration rules of the current network
+func DefaultRegistrationConfig() RegistrationConfig {
+	return RegistrationConfig{
+		SystemVersion:   SystemVersion,
+		MaximumMixnodes: MaximumMixnodes,
+		MaximumGateways: MaximumGateways,
+	}
+}
+
+// Validate checks the registration rules make sense
+func (cfg RegistrationConfig) Validate() error {
+	if cfg.SystemVersion == "" {
+		return errors.New("no system version configured")
+	}
+	if cfg.MaximumMixnodes <= 0 {
+		return fmt.Errorf("maximum mixnodes must be positive, got %d", cfg.MaximumMixnodes)
+	}
+	if cfg.MaximumGateways <= 0 {
+		return fmt.Errorf("maximum gateways must be positive, got %d", cfg.MaximumGateways)
+	}
+	return nil
+}
+
+// RateLimit allows every client Rate requests per second, in bursts of up to Burst requests
+type RateLimit struct {
+	Rate  float64 `mapstructure:"rate"`
+	Burst int     `mapstructure:"burst"`
+}
+
+// RateLimits of the controller routes
+type RateLimits struct {
+	// Default applies to every route without a more specific limit
+	Default      RateLimit `mapstructure:"default"`
+	Registration RateLimit `mapstructure:"registration"`
+	Topology     RateLimit `mapstructure:"topology"`
+}
+
+// DefaultRateLimits returns the rate limits of the public directory
+func DefaultRateLimits() RateLimits {
+	return RateLimits{
+		// 1 request per second
+		Default: RateLimit{Rate: 1, Burst: 1},
+		// 2 requests per minute (meaning in a 1min you can register and unregister)
+		Registration: RateLimit{Rate: 2.0 / 60.0, Burst: 2},
+		// 1 topology request per 10s
+		Topology: RateLimit{Rate: 1.0 / 10.0, Burst: 3},
+	}
+}
+
+// Validate checks every route can be reached at all
+func (limits RateLimits) Validate() error {
+	for name, limit := range map[string]RateLimit{
+		"default":      limits.Default,
+		"registration": limits.Registration,
+		"topology":     limits.Topology,
+	} {
+		if limit.Rate <= 0 || limit.Burst <= 0 {
+			return fmt.Errorf("%s rate limit needs a positive rate and burst, got %v and %d", name, limit.Rate, limit.Burst)
+		}
+	}
+	return nil
+}
+
+func (limit RateLimit) handler() gin.HandlerFunc {
+	return tollbooth_gin.LimitHandler(tollbooth.NewLimiter(limit.Rate, nil).SetBurst(limit.Burst))
 }
 
 // controller is the mixmining controller
@@ -46,6 +128,9 @@ type controller struct {
 	sanitizer        Sanitizer
 	genericSanitizer GenericSanitizer
 	batchSanitizer   BatchSanitizer
+	registration     RegistrationConfig
+	rateLimits       RateLimits
+	auth             AuthConfig
 
 	mixCount     int
 	gatewayCount int
@@ -68,26 +153,27 @@ func New(cfg Config) Controller {
 	initialMixCount := cfg.Service.MixCount()
 	initialGatewayCount := cfg.Service.GatewayCount()
 
-	// move all non 0.9.2 nodes to "removed" set
-	cfg.Service.StartupPurge()
+	// move all nodes not running the system version to "removed" set
+	cfg.Service.StartupPurge(cfg.Registration.SystemVersion)
 
-	return &controller{cfg.Service, cfg.Sanitizer, cfg.GenericSanitizer, cfg.BatchSanitizer, initialMixCount, initialGatewayCount, sync.Mutex{}}
+	return &controller{cfg.Service, cfg.Sanitizer, cfg.GenericSanitizer, cfg.BatchSanitizer, cfg.Registration, cfg.RateLimits, cfg.Auth, initialMixCount, initialGatewayCount, sync.Mutex{}}
 }
 
 func (controller *controller) RegisterRoutes(router *gin.Engine) {
-	// use that limiter if no other is specified (1 request per second)
-	lmt := tollbooth_gin.LimitHandler(tollbooth.NewLimiter(1, nil))
-
-	// allows 2 requests per minute (meaning in a 1min you can register and unregister)
-	registrationLmt := tollbooth_gin.LimitHandler(tollbooth.NewLimiter(2.0 / 60.0, nil).SetBurst(2))
+	// use that limiter if no other is specified
+	lmt := controller.rateLimits.Default.handler()
+	registrationLmt := controller.rateLimits.Registration.handler()
+	topologyLmt := controller.rateLimits.Topology.handler()
 
-	// allows 1 topology request per 10s
-	topologyLmt := tollbooth_gin.LimitHandler(tollbooth.NewLimiter(1.0 / 10.0, nil).SetBurst(3))
+	// monitor and admin requests have to be signed by one of the allowed keys
+	monitorAuth := authenticate(controller.auth.Monitors, controller.auth.MaxClockSkew)
+	adminAuth := authenticate(controller.auth.Admins, controller.auth.MaxClockSkew)
 
-	router.POST("/api/mixmining", lmt, controller.CreateMixStatus)
-	router.POST("/api/mixmining/batch", lmt, controller.BatchCreateMixStatus)
+	router.POST("/api/mixmining", lmt, monitorAuth, controller.CreateMixStatus)
+	router.POST("/api/mixmining/batch", lmt, monitorAuth, controller.BatchCreateMixStatus)
 	router.GET("/api/mixmining/node/:pubkey/history", lmt, controller.ListMeasurements)
 	router.GET("/api/mixmining/node/:pubkey/report", lmt, controller.GetMixStatusReport)
+	router.GET("/api/mixmining/node/:pubkey/uptime", lmt, controller.GetUptimeSeries)
 	router.GET("/api/mixmining/fullreport", lmt, controller.BatchGetMixStatusReport)
 
 	router.POST("/api/mixmining/register/mix", registrationLmt, controller.RegisterMixPresence)
@@ -95,9 +181,13 @@ func (controller *controller) RegisterRoutes(router *gin.Engine) {
 	router.DELETE("/api/mixmining/register/:id", registrationLmt, controller.UnregisterPresence)
 	router.GET("/api/mixmining/topology", topologyLmt,  controller.GetTopology)
 	router.GET("/api/mixmining/topology/active", topologyLmt, controller.GetActiveTopology)
-	router.PATCH("/api/mixmining/reputation/:id", lmt, controller.ChangeReputation)
+	router.PATCH("/api/mixmining/reputation/:id", lmt, adminAuth, controller.ChangeReputation)
+	router.GET("/api/mixmining/node/:pubkey/reputation/history", lmt, controller.GetReputationHistory)
+	router.GET("/api/mixmining/topology/layers", lmt, controller.GetLayerRebalance)
+	router.POST("/api/mixmining/topology/layers/rebalance", lmt, adminAuth, controller.RebalanceLayers)
 
 	router.GET("/api/mixmining/topology/removed", topologyLmt, controller.GetRemovedTopology)
+	router.GET("/api/mixmining/workers", lmt, controller.GetWorkerStatuses)
 }
 
 // ListMeasurements lists mixnode statuses
@@ -128,24 +218,23 @@ func (controller *controller) ListMeasurements(c *gin.Context) {
 // @Tags mixmining
 // @Param   object      body   models.MixStatus     true  "object"
 // @Success 201
+// @Param X-Nym-Identity header string true "Base58 identity key of the monitor"
+// @Param X-Nym-Timestamp header integer true "Unix nanoseconds the request was signed at"
+// @Param X-Nym-Signature header string true "Base58 ed25519 signature of the request"
 // @Failure 400 {object} models.Error
+// @Failure 401 {object} models.Error
 // @Failure 403 {object} models.Error
 // @Failure 404 {object} models.Error
 // @Failure 500 {object} models.Error
 // @Router /api/mixmining [post]
 func (controller *controller) CreateMixStatus(c *gin.Context) {
-	remoteIP := c.ClientIP()
-	if !(remoteIP == "127.0.0.1" || remoteIP == "::1" || c.Request.RemoteAddr == "127.0.0.1" || c.Request.RemoteAddr == "::1") {
-		c.JSON(http.StatusForbidden, gin.H{"error": "forbidden"})
-		return
-	}
 	var status models.MixStatus
 	if err := c.ShouldBindJSON(&status); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 	sanitized := controller.sanitizer.Sanitize(status)
-	persisted := controller.service.CreateMixStatus(sanitized)
+	persisted := controller.service.CreateMixStatus(sanitized, c.GetString(identityContextKey))
 	controller.service.SaveStatusReport(persisted)
 
 	// we don't know how number of active nodes changed - update it
@@ -157,7 +246,7 @@ func (controller *controller) CreateMixStatus(c *gin.Context) {
 
 // GetMixStatusReport ...
 // @Summary Retrieves a summary report of historical mix status
-// @Description Provides summary uptime statistics for last 5 minutes, day, week, and month
+// @Description Provides summary uptime statistics for the last 5 minutes, hour, day, 30 days and 90 days. The uptime of a period during which the node wasn't tested is -1.
 // @ID getMixStatusReport
 // @Accept  json
 // @Produce  json
@@ -186,17 +275,16 @@ func (controller *controller) GetMixStatusReport(c *gin.Context) {
 // @Tags mixmining
 // @Param   object      body   models.BatchMixStatus     true  "object"
 // @Success 201
+// @Param X-Nym-Identity header string true "Base58 identity key of the monitor"
+// @Param X-Nym-Timestamp header integer true "Unix nanoseconds the request was signed at"
+// @Param X-Nym-Signature header string true "Base58 ed25519 signature of the request"
 // @Failure 400 {object} models.Error
+// @Failure 401 {object} models.Error
 // @Failure 403 {object} models.Error
 // @Failure 404 {object} models.Error
 // @Failure 500 {object} models.Error
 // @Router /api/mixmining/batch [post]
 func (controller *controller) BatchCreateMixStatus(c *gin.Context) {
-	remoteIP := c.ClientIP()
-	if !(remoteIP == "127.0.0.1" || remoteIP == "::1" || c.Request.RemoteAddr == "127.0.0.1" || c.Request.RemoteAddr == "::1") {
-		c.JSON(http.StatusForbidden, gin.H{"error": "forbidden"})
-		return
-	}
 	var status models.BatchMixStatus
 	if err := c.ShouldBindJSON(&status); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
@@ -204,7 +292,7 @@ func (controller *controller) BatchCreateMixStatus(c *gin.Context) {
 	}
 	sanitized := controller.batchSanitizer.Sanitize(status)
 
-	persisted := controller.service.BatchCreateMixStatus(sanitized)
+	persisted := controller.service.BatchCreateMixStatus(sanitized, c.GetString(identityContextKey))
 	controller.service.SaveBatchStatusReport(persisted)
 
 	// we don't know how number of active nodes changed - update it
@@ -216,7 +304,7 @@ func (controller *controller) BatchCreateMixStatus(c *gin.Context) {
 
 // BatchGetMixStatusReport ...
 // @Summary Retrieves a summary report of historical mix status
-// @Description Provides summary uptime statistics for last 5 minutes, day, week, and month
+// @Description Provides summary uptime statistics for the last 5 minutes, hour, day, 30 days and 90 days. The uptime of a period during which the node wasn't tested is -1.
 // @ID batchGetMixStatusReport
 // @Accept  json
 // @Produce  json
@@ -233,7 +321,7 @@ func (controller *controller) BatchGetMixStatusReport(c *gin.Context) {
 
 // RegisterMixPresence ...
 // @Summary Lets a mixnode tell the directory server it's coming online
-// @Description On Nym nodes startup they register their presence indicating they should be alive and get added to the set of active nodes in the topology.
+// @Description On Nym nodes startup they register their presence indicating they should be alive and get added to the set of active nodes in the topology. The directory assigns the mixnode its layer, which is returned in the response.
 // @ID registerMixPresence
 // @Accept  json
 // @Produce  json
@@ -246,7 +334,7 @@ func (controller *controller) BatchGetMixStatusReport(c *gin.Context) {
 // @Failure 500 {object} models.Error
 // @Router /api/mixmining/register/mix [post]
 func (controller *controller) RegisterMixPresence(ctx *gin.Context) {
-	if controller.mixCount >= MaximumMixnodes {
+	if controller.mixCount >= controller.registration.MaximumMixnodes {
 		ctx.JSON(http.StatusConflict, gin.H{"error": "mixnet is already at capacity"})
 		return
 	}
@@ -264,15 +352,15 @@ func (controller *controller) RegisterMixPresence(ctx *gin.Context) {
 		return
 	}
 
-	if presence.Version != SystemVersion {
-		ctx.JSON(http.StatusConflict, gin.H{"error": fmt.Sprintf("running non %v version", SystemVersion)})
+	if presence.Version != controller.registration.SystemVersion {
+		ctx.JSON(http.StatusConflict, gin.H{"error": fmt.Sprintf("running non %v version", controller.registration.SystemVersion)})
 		return
 	}
 
 	// make sure there's still space left after all the checks
 	// (another node might have finished registration in the meantime)
 	// it is done before locking as it's a very cheap check
-	if controller.mixCount >= MaximumMixnodes {
+	if controller.mixCount >= controller.registration.MaximumMixnodes {
 		ctx.JSON(http.StatusConflict, gin.H{"error": "mixnet is already at capacity"})
 		return
 	}
@@ -281,20 +369,20 @@ func (controller *controller) RegisterMixPresence(ctx *gin.Context) {
 	defer controller.registrationLock.Unlock()
 
 	// perform the same check after locking because we don't know how long it took to acquire the lock
-	if controller.mixCount >= MaximumMixnodes {
+	if controller.mixCount >= controller.registration.MaximumMixnodes {
 		ctx.JSON(http.StatusConflict, gin.H{"error": "mixnet is already at capacity"})
 		return
 	}
 
-	controller.service.RegisterMix(presence)
+	layer := controller.service.RegisterMix(presence)
 	controller.mixCount = controller.service.MixCount()
 
-	ctx.JSON(http.StatusOK, gin.H{"ok": true})
+	ctx.JSON(http.StatusOK, gin.H{"ok": true, "layer": layer})
 }
 
 // RegisterGatewayPresence ...
 // @Summary Lets a gateway tell the directory server it's coming online
-// @Description On Nym nodes startup they register their presence indicating they should be alive and get added to the set of active nodes in the topology.
+// @Description On Nym nodes startup they register their presence indicating they should be alive and get added to the set of active nodes in the topology. Unlike mixnodes, gateways aren't assigned a layer.
 // @ID registerGatewayPresence
 // @Accept  json
 // @Produce  json
@@ -307,7 +395,7 @@ func (controller *controller) RegisterMixPresence(ctx *gin.Context) {
 // @Failure 500 {object} models.Error
 // @Router /api/mixmining/register/gateway [post]
 func (controller *controller) RegisterGatewayPresence(ctx *gin.Context) {
-	if controller.gatewayCount >= MaximumGateways {
+	if controller.gatewayCount >= controller.registration.MaximumGateways {
 		ctx.JSON(http.StatusConflict, gin.H{"error": "mixnet is already at capacity"})
 		return
 	}
@@ -325,8 +413,8 @@ func (controller *controller) RegisterGatewayPresence(ctx *gin.Context) {
 		return
 	}
 
-	if presence.Version != SystemVersion {
-		ctx.JSON(http.StatusConflict, gin.H{"error": fmt.Sprintf("running non %v version", SystemVersion)})
+	if presence.Version != controller.registration.SystemVersion {
+		ctx.JSON(http.StatusConflict, gin.H{"error": fmt.Sprintf("running non %v version", controller.registration.SystemVersion)})
 		return
 	}
 
@@ -405,21 +493,19 @@ func (controller *controller) GetActiveTopology(ctx *gin.Context) {
 // @Tags mixmining
 // @Param id path string true "Node Identity"
 // @Param reputation query integer true "New Reputation"
+// @Param X-Nym-Identity header string true "Base58 identity key of the admin"
+// @Param X-Nym-Timestamp header integer true "Unix nanoseconds the request was signed at"
+// @Param X-Nym-Signature header string true "Base58 ed25519 signature of the request"
 // @Success 200
 // @Failure 400 {object} models.Error
+// @Failure 401 {object} models.Error
 // @Failure 403 {object} models.Error
 // @Failure 404 {object} models.Error
 // @Failure 500 {object} models.Error
 // @Router /api/mixmining/reputation/{id} [patch]
-// NOTE: it's only accessible from localhost and its only purpose is to jumpstart the network quickly (so you could
+// NOTE: it's only accessible to the configured admins and its only purpose is to jumpstart the network quickly (so you could
 // manually set few nodes above threshold reputation rather than to wait for enough reports to come in)
 func (controller *controller) ChangeReputation(ctx *gin.Context) {
-	remoteIP := ctx.ClientIP()
-	if !(remoteIP == "127.0.0.1" || remoteIP == "::1" || ctx.Request.RemoteAddr == "127.0.0.1" || ctx.Request.RemoteAddr == "::1") {
-		ctx.JSON(http.StatusForbidden, gin.H{"error": "forbidden"})
-		return
-	}
-
 	id := ctx.Param("id")
 	newRepStr := ctx.Request.URL.Query().Get("reputation")
 	controller.genericSanitizer.Sanitize(&id)
@@ -438,6 +524,21 @@ func (controller *controller) ChangeReputation(ctx *gin.Context) {
 	}
 }
 
+// GetReputationHistory ...
+// @Summary Lists the changes of the reputation of a node
+// @Description Lists the 1000 most recent changes of the reputation of a node, newest first, with the reason of each of them: a monitor report, an admin override or the decay of reputations. The ledger is append-only, changes are never removed.
+// @ID getReputationHistory
+// @Produce  json
+// @Tags mixmining
+// @Param pubkey path string true "Node Pubkey"
+// @Success 200 {array} models.ReputationChange
+// @Failure 500 {object} models.Error
+// @Router /api/mixmining/node/{pubkey}/reputation/history [get]
+func (controller *controller) GetReputationHistory(ctx *gin.Context) {
+	pubkey := ctx.Param("pubkey")
+	ctx.JSON(http.StatusOK, controller.service.ReputationHistory(pubkey))
+}
+
 // GetRemovedTopology ...
 // @Summary Lists Nym mixnodes and gateways on the network that got removed due to bad service provided.
 // @Description On Nym nodes startup they register their presence indicating they should be alive.
@@ -451,3 +552,90 @@ func (controller *controller) ChangeReputation(ctx *gin.Context) {
 func (controller *controller) GetRemovedTopology(ctx *gin.Context) {
 	ctx.JSON(http.StatusOK, controller.service.GetRemovedTopology())
 }
+
+// GetLayerRebalance ...
+// @Summary Shows how mixnodes would be moved between layers by a rebalance
+// @Description Dry run of the layer rebalance: lists the mixnodes that would change layer to even out the number of mixnodes and their reputation across layers, along with the layers' load before and after.
+// @ID getLayerRebalance
+// @Produce  json
+// @Tags mixmining
+// @Success 200 {object} types.LayerRebalance
+// @Failure 500 {object} models.Error
+// @Router /api/mixmining/topology/layers [get]
+func (controller *controller) GetLayerRebalance(ctx *gin.Context) {
+	ctx.JSON(http.StatusOK, controller.service.ProposeLayerRebalance())
+}
+
+// RebalanceLayers ...
+// @Summary Rebalances the mixnet layers
+// @Description Moves mixnodes between layers to even out the number of mixnodes and their reputation across layers. Rebalancing also happens periodically unless the chain bridge is enabled, this triggers it immediately. The moves are not mirrored onto the chain, which owns the layers of the mixnodes it knows.
+// @ID rebalanceLayers
+// @Produce  json
+// @Tags mixmining
+// @Param X-Nym-Identity header string true "Base58 identity key of the admin"
+// @Param X-Nym-Timestamp header integer true "Unix nanoseconds the request was signed at"
+// @Param X-Nym-Signature header string true "Base58 ed25519 signature of the request"
+// @Success 200 {object} types.LayerRebalance
+// @Failure 401 {object} models.Error
+// @Failure 403 {object} models.Error
+// @Failure 500 {object} models.Error
+// @Router /api/mixmining/topology/layers/rebalance [post]
+func (controller *controller) RebalanceLayers(ctx *gin.Context) {
+	ctx.JSON(http.StatusOK, controller.service.RebalanceLayers())
+}
+
+// GetWorkerStatuses ...
+// @Summary Shows how the directory background workers are doing
+// @Description Lists the background workers of the directory (validators refresh, reports refresh, statuses rollup and purge, layer rebalance unless the chain bridge is enabled and, if enabled, reputation decay, the chain bridge and its reconciliation) with when they last ran, how long it took and the error it failed with, if any.
+// @ID getWorkerStatuses
+// @Produce  json
+// @Tags mixmining
+// @Success 200 {array} models.WorkerStatus
+// @Failure 500 {object} models.Error
+// @Router /api/mixmining/workers [get]
+func (controller *controller) GetWorkerStatuses(ctx *gin.Context) {
+	ctx.JSON(http.StatusOK, controller.service.WorkerStatuses())
+}
+
+// GetUptimeSeries ...
+// @Summary Charts the uptime of a mixnode over time
+// @Description Splits the requested range into buckets of 5 minutes, 1 hour or 1 day, aligned on multiples of their size since the unix epoch, and computes the uptime percentage of the node in each of them. Buckets during which the node wasn't tested have an uptime of -1. Hourly and daily buckets older than the statuses, which are kept for a week, are taken from the hourly and daily rollups of their statuses.
+// @ID getUptimeSeries
+// @Produce  json
+// @Tags mixmining
+// @Param pubkey path string true "Mixnode Pubkey"
+// @Param from query integer false "Start of the range in unix nanoseconds, defaults to a day before to"
+// @Param to query integer false "End of the range in unix nanoseconds, defaults to now"
+// @Param ipVersion query string false "4 or 6, defaults to 4"
+// @Param bucket query string false "5m, 1h or 1d, defaults to 1h"
+// @Success 200 {object} models.UptimeSeries
+// @Failure 400 {object} models.Error
+// @Failure 500 {object} models.Error
+// @Router /api/mixmining/node/{pubkey}/uptime [get]
+func (controller *controller) GetUptimeSeries(ctx *gin.Context) {
+	pubkey := ctx.Param("pubkey")
+	ipVersion := ctx.DefaultQuery("ipVersion", "4")
+	bucket := ctx.DefaultQuery("bucket", "1h")
+
+	var err error
+	to := timemock.Now().UnixNano()
+	if toStr := ctx.Query("to"); toStr != "" {
+		if to, err = strconv.ParseInt(toStr, 10, 64); err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid to: " + err.Error()})
+			return
+		}
+	}
+	from := to - int64(time.Hour*24)
+	if fromStr := ctx.Query("from"); fromStr != "" {
+		if from, err = strconv.ParseInt(fromStr, 10, 64); err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid from: " + err.Error()})
+			return
+		}
+	}
+	if err := validateUptimeSeries(ipVersion, from, to, bucket); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, controller.service.GetUptimeSeries(pubkey, ipVersion, from, to, bucket))
+}
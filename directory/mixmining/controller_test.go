@@ -16,12 +16,15 @@ package mixmining
 
 import (
 	"bytes"
+	"crypto/ed25519"
 	"encoding/json"
 	"errors"
 	"net/http"
 	"net/http/httptest"
 	"strconv"
 
+	"github.com/BorisBorshevsky/timemock"
+	"github.com/btcsuite/btcutil/base58"
 	"github.com/nymtech/nym/validator/nym/directory/models"
 
 	"github.com/gin-gonic/gin"
@@ -29,16 +32,101 @@ import (
 	"github.com/nymtech/nym/validator/nym/directory/mixmining/mocks"
 	. "github.com/onsi/ginkgo"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 )
 
+var monitorKey = ed25519.NewKeyFromSeed(bytes.Repeat([]byte{1}, ed25519.SeedSize))
+var monitorIdentity = base58.Encode(monitorKey.Public().(ed25519.PublicKey))
+var adminKey = ed25519.NewKeyFromSeed(bytes.Repeat([]byte{2}, ed25519.SeedSize))
+var strangerKey = ed25519.NewKeyFromSeed(bytes.Repeat([]byte{3}, ed25519.SeedSize))
+
 var _ = Describe("Controller", func() {
 	Describe("creating a mix status", func() {
-		Context("from a host other than localhost", func() {
+		Context("without a signature", func() {
 			It("should fail", func() {
 				router, _, _, _, _ := SetupRouter()
-				badJSON, _ := json.Marshal(fixtures.XSSMixStatus())
-				resp := performNonLocalRequest(router, "POST", "/api/mixmining", badJSON)
-				assert.Equal(GinkgoT(), 403, resp.Result().StatusCode)
+				goodJSON, _ := json.Marshal(fixtures.GoodMixStatus())
+				resp := performLocalHostRequest(router, "POST", "/api/mixmining", goodJSON)
+				assert.Equal(GinkgoT(), http.StatusUnauthorized, resp.Code)
+			})
+		})
+
+		Context("signed by a key that isn't a monitor", func() {
+			It("should fail", func() {
+				router, _, _, _, _ := SetupRouter()
+				goodJSON, _ := json.Marshal(fixtures.GoodMixStatus())
+				resp := performSignedRequest(router, "POST", "/api/mixmining", goodJSON, adminKey)
+				assert.Equal(GinkgoT(), http.StatusForbidden, resp.Code)
+			})
+		})
+
+		Context("whose body was tampered with after signing", func() {
+			It("should fail", func() {
+				router, _, _, _, _ := SetupRouter()
+				goodJSON, _ := json.Marshal(fixtures.GoodMixStatus())
+				req, _ := http.NewRequest("POST", "/api/mixmining", bytes.NewBuffer([]byte("{}")))
+				SignRequest(req, goodJSON, monitorKey)
+				resp := httptest.NewRecorder()
+				router.ServeHTTP(resp, req)
+				assert.Equal(GinkgoT(), http.StatusUnauthorized, resp.Code)
+			})
+		})
+
+		Context("with a body too large to be checked", func() {
+			It("should fail without reading all of it", func() {
+				router, mockService, _, _, _ := SetupRouter()
+				resp := performSignedRequest(router, "POST", "/api/mixmining", bytes.Repeat([]byte{' '}, MaxSignedBodySize+1), monitorKey)
+				assert.Equal(GinkgoT(), http.StatusRequestEntityTooLarge, resp.Code)
+				mockService.AssertNotCalled(GinkgoT(), "CreateMixStatus", mock.Anything, mock.Anything)
+			})
+		})
+
+		Context("signed by a key that isn't a monitor with a body too large to be checked", func() {
+			It("should be forbidden without reading the body", func() {
+				router, mockService, _, _, _ := SetupRouter()
+				resp := performSignedRequest(router, "POST", "/api/mixmining", bytes.Repeat([]byte{' '}, MaxSignedBodySize+1), adminKey)
+				assert.Equal(GinkgoT(), http.StatusForbidden, resp.Code)
+				mockService.AssertNotCalled(GinkgoT(), "CreateMixStatus", mock.Anything, mock.Anything)
+			})
+		})
+
+		Context("replayed after being accepted", func() {
+			It("should fail", func() {
+				router, mockService, mockSanitizer, _, _ := SetupRouter()
+				status := fixtures.GoodMixStatus()
+				mockSanitizer.On("Sanitize", status).Return(status)
+				mockService.On("CreateMixStatus", status, monitorIdentity).Return(fixtures.GoodPersistedMixStatus())
+				mockService.On("SaveStatusReport", fixtures.GoodPersistedMixStatus()).Return(models.MixStatusReport{})
+
+				goodJSON, _ := json.Marshal(status)
+				signed, _ := http.NewRequest("POST", "/api/mixmining", bytes.NewBuffer(goodJSON))
+				SignRequest(signed, goodJSON, monitorKey)
+				resp := httptest.NewRecorder()
+				router.ServeHTTP(resp, signed)
+				assert.Equal(GinkgoT(), http.StatusCreated, resp.Code)
+
+				replayed, _ := http.NewRequest("POST", "/api/mixmining", bytes.NewBuffer(goodJSON))
+				replayed.Header = signed.Header.Clone()
+				resp = httptest.NewRecorder()
+				router.ServeHTTP(resp, replayed)
+				assert.Equal(GinkgoT(), http.StatusUnauthorized, resp.Code)
+				mockService.AssertNumberOfCalls(GinkgoT(), "CreateMixStatus", 1)
+			})
+		})
+
+		Context("signed too long ago", func() {
+			It("should fail", func() {
+				router, _, _, _, _ := SetupRouter()
+				goodJSON, _ := json.Marshal(fixtures.GoodMixStatus())
+				signedAt := timemock.Now().Add(-2 * MaxClockSkew).UnixNano()
+				signature := ed25519.Sign(monitorKey, RequestSigningMessage("POST", "/api/mixmining", signedAt, goodJSON))
+				req, _ := http.NewRequest("POST", "/api/mixmining", bytes.NewBuffer(goodJSON))
+				req.Header.Set(HeaderIdentity, monitorIdentity)
+				req.Header.Set(HeaderTimestamp, strconv.FormatInt(signedAt, 10))
+				req.Header.Set(HeaderSignature, base58.Encode(signature))
+				resp := httptest.NewRecorder()
+				router.ServeHTTP(resp, req)
+				assert.Equal(GinkgoT(), http.StatusUnauthorized, resp.Code)
 			})
 		})
 
@@ -53,11 +141,11 @@ var _ = Describe("Controller", func() {
 				savedStatus.Up = &boolfalse
 
 				mockSanitizer.On("Sanitize", status).Return(status)
-				mockService.On("CreateMixStatus", status).Return(savedStatus)
+				mockService.On("CreateMixStatus", status, monitorIdentity).Return(savedStatus)
 				mockService.On("SaveStatusReport", savedStatus).Return(models.MixStatusReport{})
 
 				falseJSON, _ := json.Marshal(status)
-				resp := performLocalHostRequest(router, "POST", "/api/mixmining", falseJSON)
+				resp := performSignedRequest(router, "POST", "/api/mixmining", falseJSON, monitorKey)
 				assert.Equal(GinkgoT(), 201, resp.Code)
 
 			})
@@ -68,17 +156,17 @@ var _ = Describe("Controller", func() {
 				router, mockService, mockSanitizer, _, _ := SetupRouter()
 
 				mockSanitizer.On("Sanitize", fixtures.XSSMixStatus()).Return(fixtures.GoodMixStatus())
-				mockService.On("CreateMixStatus", fixtures.GoodMixStatus()).Return(fixtures.GoodPersistedMixStatus())
+				mockService.On("CreateMixStatus", fixtures.GoodMixStatus(), monitorIdentity).Return(fixtures.GoodPersistedMixStatus())
 				mockService.On("SaveStatusReport", fixtures.GoodPersistedMixStatus()).Return(models.MixStatusReport{})
 				badJSON, _ := json.Marshal(fixtures.XSSMixStatus())
 
-				resp := performLocalHostRequest(router, "POST", "/api/mixmining", badJSON)
+				resp := performSignedRequest(router, "POST", "/api/mixmining", badJSON, monitorKey)
 				var response map[string]string
 				json.Unmarshal([]byte(resp.Body.String()), &response)
 
 				assert.Equal(GinkgoT(), 201, resp.Code)
 				mockSanitizer.AssertCalled(GinkgoT(), "Sanitize", fixtures.XSSMixStatus())
-				mockService.AssertCalled(GinkgoT(), "CreateMixStatus", fixtures.GoodMixStatus())
+				mockService.AssertCalled(GinkgoT(), "CreateMixStatus", fixtures.GoodMixStatus(), monitorIdentity)
 			})
 		})
 	})
@@ -132,12 +220,12 @@ var _ = Describe("Controller", func() {
 	})
 
 	Describe("Creating batch mix status", func() {
-		Context("from a host other than localhost", func() {
+		Context("signed by a key that isn't a monitor", func() {
 			It("should fail", func() {
 				router, _, _, _, _ := SetupRouter()
 				goodJSON, _ := json.Marshal(fixtures.GoodBatchMixStatus())
-				resp := performNonLocalRequest(router, "POST", "/api/mixmining/batch", goodJSON)
-				assert.Equal(GinkgoT(), 403, resp.Result().StatusCode)
+				resp := performSignedRequest(router, "POST", "/api/mixmining/batch", goodJSON, strangerKey)
+				assert.Equal(GinkgoT(), http.StatusForbidden, resp.Code)
 			})
 		})
 
@@ -153,11 +241,11 @@ var _ = Describe("Controller", func() {
 					savedStatus[0].Up = &boolfalse
 
 					mockBatchSanitizer.On("Sanitize", singleStatusBatch).Return(singleStatusBatch)
-					mockService.On("BatchCreateMixStatus", singleStatusBatch).Return(savedStatus)
+					mockService.On("BatchCreateMixStatus", singleStatusBatch, monitorIdentity).Return(savedStatus)
 					mockService.On("SaveBatchStatusReport", savedStatus).Return(models.BatchMixStatusReport{Report: []models.MixStatusReport{}})
 
 					falseJSON, _ := json.Marshal(singleStatusBatch)
-					resp := performLocalHostRequest(router, "POST", "/api/mixmining/batch", falseJSON)
+					resp := performSignedRequest(router, "POST", "/api/mixmining/batch", falseJSON, monitorKey)
 
 					assert.Equal(GinkgoT(), 201, resp.Code)
 				})
@@ -171,17 +259,17 @@ var _ = Describe("Controller", func() {
 					savedStatus := []models.PersistedMixStatus{{MixStatus: fixtures.GoodMixStatus(), Timestamp: 1234}}
 
 					mockBatchSanitizer.On("Sanitize", singleXSSStatusBatch).Return(singleStatusBatch)
-					mockService.On("BatchCreateMixStatus", singleStatusBatch).Return(savedStatus)
+					mockService.On("BatchCreateMixStatus", singleStatusBatch, monitorIdentity).Return(savedStatus)
 					mockService.On("SaveBatchStatusReport", savedStatus).Return(models.BatchMixStatusReport{Report: []models.MixStatusReport{}})
 					badJSON, _ := json.Marshal(singleXSSStatusBatch)
 
-					resp := performLocalHostRequest(router, "POST", "/api/mixmining/batch", badJSON)
+					resp := performSignedRequest(router, "POST", "/api/mixmining/batch", badJSON, monitorKey)
 					var response map[string]string
 					json.Unmarshal([]byte(resp.Body.String()), &response)
 
 					assert.Equal(GinkgoT(), 201, resp.Code)
 					mockBatchSanitizer.AssertCalled(GinkgoT(), "Sanitize", singleXSSStatusBatch)
-					mockService.AssertCalled(GinkgoT(), "BatchCreateMixStatus", singleStatusBatch)
+					mockService.AssertCalled(GinkgoT(), "BatchCreateMixStatus", singleStatusBatch, monitorIdentity)
 				})
 			})
 		})
@@ -192,17 +280,17 @@ var _ = Describe("Controller", func() {
 					router, mockService,_, _, mockBatchSanitizer := SetupRouter()
 
 					mockBatchSanitizer.On("Sanitize", fixtures.XSSBatchMixStatus()).Return(fixtures.GoodBatchMixStatus())
-					mockService.On("BatchCreateMixStatus", fixtures.GoodBatchMixStatus()).Return(fixtures.GoodPersistedBatchMixStatus())
+					mockService.On("BatchCreateMixStatus", fixtures.GoodBatchMixStatus(), monitorIdentity).Return(fixtures.GoodPersistedBatchMixStatus())
 					mockService.On("SaveBatchStatusReport", fixtures.GoodPersistedBatchMixStatus()).Return(models.BatchMixStatusReport{Report: []models.MixStatusReport{}})
 					badJSON, _ := json.Marshal(fixtures.XSSBatchMixStatus())
 
-					resp := performLocalHostRequest(router, "POST", "/api/mixmining/batch", badJSON)
+					resp := performSignedRequest(router, "POST", "/api/mixmining/batch", badJSON, monitorKey)
 					var response map[string]string
 					json.Unmarshal([]byte(resp.Body.String()), &response)
 
 					assert.Equal(GinkgoT(), 201, resp.Code)
 					mockBatchSanitizer.AssertCalled(GinkgoT(), "Sanitize", fixtures.XSSBatchMixStatus())
-					mockService.AssertCalled(GinkgoT(), "BatchCreateMixStatus", fixtures.GoodBatchMixStatus())
+					mockService.AssertCalled(GinkgoT(), "BatchCreateMixStatus", fixtures.GoodBatchMixStatus(), monitorIdentity)
 				})
 			})
 		})
@@ -243,7 +331,7 @@ var _ = Describe("Controller", func() {
 			router, mockService, _, mockGenericSanitizer, _ := SetupRouter()
 
 			mockGenericSanitizer.On("Sanitize", &info)
-			mockService.On("RegisterMix", info)
+			mockService.On("RegisterMix", info).Return(uint(1))
 			mockService.On("CheckForDuplicateIP", info.MixHost).Return(false)
 
 			JSONReq, _ := json.Marshal(info)
@@ -310,6 +398,14 @@ var _ = Describe("Controller", func() {
 	})
 
 	Describe("Changing reputation", func() {
+		Context("If not signed by an admin", func() {
+			It("Should be forbidden", func() {
+				router, _, _, _, _ := SetupRouter()
+				resp := performSignedRequest(router, "PATCH", "/api/mixmining/reputation/foomp?reputation=42", nil, monitorKey)
+				assert.Equal(GinkgoT(), http.StatusForbidden, resp.Code)
+			})
+		})
+
 		Context("If node exists", func() {
 			It("Should return success", func() {
 				nodeIdentity := "foomp"
@@ -322,7 +418,7 @@ var _ = Describe("Controller", func() {
 
 				mockService.On("SetReputation", nodeIdentity, newRep).Return(true)
 
-				resp := performLocalHostRequest(router, "PATCH", "/api/mixmining/reputation/"+nodeIdentity+"?reputation="+repStr, nil)
+				resp := performSignedRequest(router, "PATCH", "/api/mixmining/reputation/"+nodeIdentity+"?reputation="+repStr, nil, adminKey)
 				assert.Equal(GinkgoT(), http.StatusOK, resp.Code)
 
 				mockGenericSanitizer.AssertCalled(GinkgoT(), "Sanitize", &nodeIdentity)
@@ -343,7 +439,7 @@ var _ = Describe("Controller", func() {
 
 				mockService.On("SetReputation", nodeIdentity, newRep).Return(false)
 
-				resp := performLocalHostRequest(router, "PATCH", "/api/mixmining/reputation/"+nodeIdentity+"?reputation="+repStr, nil)
+				resp := performSignedRequest(router, "PATCH", "/api/mixmining/reputation/"+nodeIdentity+"?reputation="+repStr, nil, adminKey)
 				assert.Equal(GinkgoT(), http.StatusNotFound, resp.Code)
 
 				mockGenericSanitizer.AssertCalled(GinkgoT(), "Sanitize", &nodeIdentity)
@@ -412,6 +508,87 @@ var _ = Describe("Controller", func() {
 			mockService.AssertCalled(GinkgoT(), "GetActiveTopology")
 		})
 	})
+
+	Describe("Charting uptime", func() {
+		It("Delegates the call to the service", func() {
+			expectedSeries := models.UptimeSeries{
+				PubKey:    "key1",
+				IPVersion: "6",
+				Bucket:    "5m",
+				Buckets:   []models.UptimeBucket{{Start: 0, End: 300000000000, Uptime: 80, Reports: 5}},
+			}
+
+			router, mockService, _, _, _ := SetupRouter()
+
+			mockService.On("GetUptimeSeries", "key1", "6", int64(1), int64(300000000000), "5m").Return(expectedSeries)
+
+			resp := performRequest(router, "GET", "/api/mixmining/node/key1/uptime?from=1&to=300000000000&ipVersion=6&bucket=5m", nil)
+			var response models.UptimeSeries
+			if err := json.Unmarshal([]byte(resp.Body.String()), &response); err != nil {
+				panic(err)
+			}
+
+			assert.Equal(GinkgoT(), http.StatusOK, resp.Code)
+			assert.Equal(GinkgoT(), expectedSeries, response)
+		})
+
+		It("Rejects invalid ranges and buckets", func() {
+			router, mockService, _, _, _ := SetupRouter()
+
+			for _, query := range []string{"from=foo", "to=bar", "from=2&to=1", "bucket=1w", "ipVersion=5",
+				"from=-9223372036854775808&to=9223372036854775807", "from=0&to=9223372036854775807"} {
+				resp := performRequest(router, "GET", "/api/mixmining/node/key1/uptime?"+query, nil)
+				assert.Equal(GinkgoT(), http.StatusBadRequest, resp.Code, query)
+			}
+			mockService.AssertNotCalled(GinkgoT(), "GetUptimeSeries", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		})
+	})
+
+	Describe("Getting the reputation history of a node", func() {
+		It("Delegates the call to the service", func() {
+			expectedChanges := []models.ReputationChange{
+				{PubKey: "foo", Delta: -2, Reputation: 98, Reason: models.ReputationChangeMonitorReport, Timestamp: 43},
+				{PubKey: "foo", Delta: 100, Reputation: 100, Reason: models.ReputationChangeAdminOverride, Timestamp: 42},
+			}
+
+			router, mockService, _, _, _ := SetupRouter()
+
+			mockService.On("ReputationHistory", "foo").Return(expectedChanges)
+
+			resp := performRequest(router, "GET", "/api/mixmining/node/foo/reputation/history", nil)
+			var response []models.ReputationChange
+			if err := json.Unmarshal([]byte(resp.Body.String()), &response); err != nil {
+				panic(err)
+			}
+
+			assert.Equal(GinkgoT(), http.StatusOK, resp.Code)
+			assert.Equal(GinkgoT(), expectedChanges, response)
+			mockService.AssertCalled(GinkgoT(), "ReputationHistory", "foo")
+		})
+	})
+
+	Describe("Getting worker statuses", func() {
+		It("Delegates the call to the service", func() {
+			expectedStatuses := []models.WorkerStatus{
+				{Name: "validators", Interval: int64(ValidatorsRefreshInterval), Runs: 2, LastRun: 42, LastDuration: 3},
+				{Name: "statuses_purge", Interval: int64(StatusesPurgeInterval), Running: true, LastError: "failed"},
+			}
+
+			router, mockService, _, _, _ := SetupRouter()
+
+			mockService.On("WorkerStatuses").Return(expectedStatuses)
+
+			resp := performRequest(router, "GET", "/api/mixmining/workers", nil)
+			var response []models.WorkerStatus
+			if err := json.Unmarshal([]byte(resp.Body.String()), &response); err != nil {
+				panic(err)
+			}
+
+			assert.Equal(GinkgoT(), http.StatusOK, resp.Code)
+			assert.Equal(GinkgoT(), expectedStatuses, response)
+			mockService.AssertCalled(GinkgoT(), "WorkerStatuses")
+		})
+	})
 })
 
 func SetupRouter() (*gin.Engine, *mocks.IService, *mocks.Sanitizer, *mocks.GenericSanitizer, *mocks.BatchSanitizer) {
@@ -423,13 +600,20 @@ func SetupRouter() (*gin.Engine, *mocks.IService, *mocks.Sanitizer, *mocks.Gener
 	// on startup there will be no nodes
 	mockService.On("MixCount").Return(0)
 	mockService.On("GatewayCount").Return(0)
-	mockService.On("StartupPurge")
+	mockService.On("StartupPurge", SystemVersion)
 
 	cfg := Config{
 		BatchSanitizer: mockBatchSanitizer,
 		GenericSanitizer: mockGenericSanitizer,
 		Sanitizer:      mockSanitizer,
 		Service:        mockService,
+		Registration:   DefaultRegistrationConfig(),
+		RateLimits:     DefaultRateLimits(),
+		Auth: AuthConfig{
+			Monitors:     []string{monitorIdentity},
+			Admins:       []string{base58.Encode(adminKey.Public().(ed25519.PublicKey))},
+			MaxClockSkew: MaxClockSkew,
+		},
 	}
 	gin.SetMode(gin.TestMode)
 	router := gin.Default()
@@ -446,10 +630,11 @@ func performLocalHostRequest(r http.Handler, method, path string, body []byte) *
 	return w
 }
 
-func performNonLocalRequest(r http.Handler, method, path string, body []byte) *httptest.ResponseRecorder {
+func performSignedRequest(r http.Handler, method, path string, body []byte, key ed25519.PrivateKey) *httptest.ResponseRecorder {
 	buf := bytes.NewBuffer(body)
 	req, _ := http.NewRequest(method, path, buf)
 	req.RemoteAddr = "1.1.1.1:12345"
+	SignRequest(req, body, key)
 	w := httptest.NewRecorder()
 	r.ServeHTTP(w, req)
 	return w
@@ -17,7 +17,6 @@ package mixmining
 import (
 	"fmt"
 	"gorm.io/gorm/clause"
-	"io/ioutil"
 	"log"
 	"net"
 	"os"
@@ -26,6 +25,7 @@ import (
 	"strings"
 
 	"github.com/nymtech/nym/validator/nym/directory/models"
+	"gorm.io/driver/postgres"
 	"gorm.io/driver/sqlite"
 	"gorm.io/gorm"
 )
@@ -37,7 +37,7 @@ type IDb interface {
 	ListMixStatus(pubkey string, limit int) []models.PersistedMixStatus
 	ListMixStatusDateRange(pubkey string, ipVersion string, start int64, end int64) []models.PersistedMixStatus
 	LoadReport(pubkey string) models.MixStatusReport
-	LoadNonStaleReports() models.BatchMixStatusReport
+	LoadNonStaleReports(removalUptime int) models.BatchMixStatusReport
 	BatchLoadReports(pubkeys []string) models.BatchMixStatusReport
 	SaveMixStatusReport(models.MixStatusReport)
 	SaveBatchMixStatusReport(models.BatchMixStatusReport)
@@ -46,9 +46,12 @@ type IDb interface {
 	RegisterMix(mix models.RegisteredMix)
 	RegisterGateway(gateway models.RegisteredGateway)
 	UnregisterNode(id string) bool
-	UpdateReputation(id string, repIncrease int64) bool
-	BatchUpdateReputation(reputationChangeMap map[string]int64)
-	SetReputation(id string, newRep int64) bool
+	UpdateReputation(id string, repIncrease int64, reason string) bool
+	BatchUpdateReputation(reputationChangeMap map[string]int64, reason string)
+	BatchLoadReputations(pubkeys []string) map[string]int64
+	SetReputation(id string, newRep int64, reason string) bool
+	ListReputationChanges(pubkey string, limit int) []models.ReputationChange
+	SetMixLayer(id string, layer uint) bool
 	Topology() models.Topology
 	ActiveTopology(reputationThreshold int64) models.Topology
 
@@ -59,6 +62,11 @@ type IDb interface {
 	GetNMostRecentMixStatuses(pubkey string, ipVersion string, n int) []models.PersistedMixStatus
 	ListMixStatusSinceWithLimit(pubkey string, ipVersion string, since int64, limit int) []models.PersistedMixStatus
 	RemoveOldStatuses(before int64)
+	RollUpStatuses(since int64) error
+	RemoveOldRollups(hourlyBefore int64, dailyBefore int64)
+	ListHourlyUptimes(pubkey string, ipVersion string, start int64, end int64) []models.UptimeRollup
+	ListDailyUptimes(pubkey string, ipVersion string, start int64, end int64) []models.UptimeRollup
+	SumDailyUptimes(since int64, pubkeys []string) []models.UptimeRollup
 	GetNodeMixHost(pubkey string) string
 }
 
@@ -67,52 +75,80 @@ type Db struct {
 	orm *gorm.DB
 }
 
-// NewDb constructor
-func NewDb(isTest bool) *Db {
-	database, err := gorm.Open(sqlite.Open(dbPath(isTest)), &gorm.Config{})
+// MemoryDSN selects a sqlite database that only lives as long as the Db holding it
+const MemoryDSN = ":memory:"
+
+const (
+	sqliteScheme     = "sqlite://"
+	postgresScheme   = "postgres://"
+	postgresqlScheme = "postgresql://"
+)
+
+// NewDb constructor. The dsn selects the storage backend: a postgres:// (or postgresql://) url
+// connects to PostgreSQL, MemoryDSN keeps everything in memory and anything else is the path
+// of a sqlite file, optionally prefixed with sqlite://. An empty dsn uses ~/.nym/mixmining.db.
+// Pending schema migrations are applied before the Db is returned.
+func NewDb(dsn string) *Db {
+	db, err := OpenDb(dsn)
 	if err != nil {
 		panic("Failed to connect to orm!")
 	}
 
-	// mix status migration
-	if err := database.AutoMigrate(&models.PersistedMixStatus{}); err != nil {
-		log.Fatal(err)
-	}
-	if err := database.AutoMigrate(&models.MixStatusReport{}); err != nil {
-		log.Fatal(err)
-	}
-
-	// registered nodes migration
-	if err := database.AutoMigrate(&models.RegisteredMix{}); err != nil {
-		log.Fatal(err)
+	applied, err := db.Migrate(LatestSchemaVersion())
+	for _, migration := range applied {
+		log.Printf("applied database migration %d: %s", migration.Version, migration.Description)
 	}
-	if err := database.AutoMigrate(&models.RegisteredGateway{}); err != nil {
+	if err != nil {
 		log.Fatal(err)
 	}
+	return db
+}
 
-	// removed nodes migration
-	if err := database.AutoMigrate(&models.RemovedMix{}); err != nil {
-		log.Fatal(err)
+// OpenDb connects to the database selected by the dsn, see NewDb, without migrating its schema
+func OpenDb(dsn string) (*Db, error) {
+	dialector, inMemory := openDialector(dsn)
+	database, err := gorm.Open(dialector, &gorm.Config{})
+	if err != nil {
+		return nil, err
 	}
-	if err := database.AutoMigrate(&models.RemovedGateway{}); err != nil {
-		log.Fatal(err)
+	if inMemory {
+		// every new connection to an in-memory sqlite database gets an empty one of its own
+		sqlDb, err := database.DB()
+		if err != nil {
+			return nil, err
+		}
+		sqlDb.SetMaxOpenConns(1)
 	}
 
 	d := Db{
 		database,
 	}
-	return &d
+	return &d, nil
 }
 
-func dbPath(isTest bool) string {
-	if isTest {
-		db, err := ioutil.TempFile("", "test_mixmining.db")
-		if err != nil {
-			panic(err)
-		}
-		return db.Name()
+// Close releases the connections to the database
+func (db *Db) Close() error {
+	sqlDb, err := db.orm.DB()
+	if err != nil {
+		return err
+	}
+	return sqlDb.Close()
+}
+
+// openDialector picks the gorm dialector for the dsn and reports whether the database is in-memory
+func openDialector(dsn string) (gorm.Dialector, bool) {
+	switch {
+	case strings.HasPrefix(dsn, postgresScheme), strings.HasPrefix(dsn, postgresqlScheme):
+		return postgres.Open(dsn), false
+	case dsn == "":
+		return sqlite.Open(dbPath()), false
 	}
 
+	file := strings.TrimPrefix(dsn, sqliteScheme)
+	return sqlite.Open(file), file == MemoryDSN
+}
+
+func dbPath() string {
 	usr, err := user.Current()
 	if err != nil {
 		log.Fatal(err)
@@ -158,8 +194,8 @@ func (db *Db) ListMixStatusDateRange(pubkey string, ipVersion string, start int6
 func (db *Db) ListMixStatusSinceWithLimit(pubkey string, ipVersion string, since int64, limit int) []models.PersistedMixStatus {
 	var statuses []models.PersistedMixStatus
 	// resultant query:
-	// SELECT * FROM (SELECT * FROM persisted_mix_statuses p WHERE p.pub_key = ? AND p.ip_version = ? AND p.timestamp >= ? LIMIT > ? ) ORDER BY timestamp desc;
-	if err := db.orm.Table("(?)", db.orm.Model(&models.PersistedMixStatus{}).Where("pub_key = ?", pubkey).Where("ip_version = ?", ipVersion).Where("timestamp >= ?", since).Limit(limit)).Order("timestamp desc").Find(&statuses).Error; err != nil {
+	// SELECT * FROM (SELECT * FROM persisted_mix_statuses p WHERE p.pub_key = ? AND p.ip_version = ? AND p.timestamp >= ? LIMIT > ? ) AS statuses ORDER BY timestamp desc;
+	if err := db.orm.Table("(?) AS statuses", db.orm.Model(&models.PersistedMixStatus{}).Where("pub_key = ?", pubkey).Where("ip_version = ?", ipVersion).Where("timestamp >= ?", since).Limit(limit)).Order("timestamp desc").Find(&statuses).Error; err != nil {
 		return make([]models.PersistedMixStatus, 0)
 	}
 	return statuses
@@ -209,12 +245,12 @@ func (db *Db) LoadReport(pubkey string) models.MixStatusReport {
 }
 
 // LoadNonStaleReports retrieves a models.BatchMixStatusReport, such that each mixnode
-// in the retrieved report must have been online for over 50% of time in the last day.
+// in the retrieved report must have been online for at least removalUptime percent of time in the last day.
 // If a report isn't found, it crudely generates a new instance and returns that instead.
-func (db *Db) LoadNonStaleReports() models.BatchMixStatusReport {
+func (db *Db) LoadNonStaleReports(removalUptime int) models.BatchMixStatusReport {
 	var reports []models.MixStatusReport
 
-	if retrieve := db.orm.Where("last_day_ip_v4 >= 50").Or("last_day_ip_v6 >= 50").Find(&reports); retrieve.Error != nil {
+	if retrieve := db.orm.Where("last_day_ip_v4 >= ?", removalUptime).Or("last_day_ip_v6 >= ?", removalUptime).Find(&reports); retrieve.Error != nil {
 		fmt.Printf("ERROR while retrieving multiple mix status report %+v", retrieve.Error)
 		return models.BatchMixStatusReport{Report: make([]models.MixStatusReport, 0)}
 	}
@@ -315,87 +351,58 @@ func (db *Db) UnregisterNode(id string) bool {
 	return false
 }
 
-func (db *Db) SetReputation(id string, newRep int64) bool {
-	res := db.orm.Model(&models.RegisteredMix{}).Where("identity_key = ?", id).Update("reputation", newRep)
-	if res.Error != nil {
-		return false
-	}
-	if res.RowsAffected > 0 {
-		return true
-	}
+// SetReputation sets the reputation of a node, recording the change for the given reason in the reputation ledger
+func (db *Db) SetReputation(id string, newRep int64, reason string) bool {
+	return db.changeReputation(id, reason, func(node *gorm.DB) *gorm.DB {
+		return node.Update("reputation", newRep)
+	})
+}
 
-	res = db.orm.Model(&models.RegisteredGateway{}).Where("identity_key = ?", id).Update("reputation", newRep)
-	if res.Error != nil {
-		return false
-	}
-	if res.RowsAffected > 0 {
-		return true
-	} else {
-		return false
-	}
+func (db *Db) SetMixLayer(id string, layer uint) bool {
+	res := db.orm.Model(&models.RegisteredMix{}).Where("identity_key = ?", id).Update("layer", layer)
+	return res.Error == nil && res.RowsAffected > 0
 }
 
-func (db *Db) BatchUpdateReputation(reputationChangeMap map[string]int64) {
+// BatchUpdateReputation changes the reputation of multiple nodes, recording the changes for the given reason in
+// the reputation ledger
+func (db *Db) BatchUpdateReputation(reputationChangeMap map[string]int64, reason string) {
 	for id, repChange := range reputationChangeMap {
-		// ensuring reputation will not go negative (haha, this can probably be solved in a simpler way inside SQL, but hey, it works)
-		if repChange < 0 {
-			res := db.orm.Model(&models.RegisteredMix{}).Where("identity_key = ? AND reputation >= ?", id, -repChange).Update("reputation", gorm.Expr("reputation + ?", repChange))
-			// TODO: rollback on fail here??
-			if res.RowsAffected == 0 {
-				db.orm.Model(&models.RegisteredGateway{}).Where("identity_key = ? AND reputation >= ?", id, -repChange).Update("reputation", gorm.Expr("reputation + ?", repChange))
-			}
-		} else {
-			res := db.orm.Model(&models.RegisteredMix{}).Where("identity_key = ?", id).Update("reputation", gorm.Expr("reputation + ?", repChange))
-			// TODO: rollback on fail here??
-			if res.RowsAffected == 0 {
-				db.orm.Model(&models.RegisteredGateway{}).Where("identity_key = ?", id).Update("reputation", gorm.Expr("reputation + ?", repChange))
-			}
-		}
+		db.UpdateReputation(id, repChange, reason)
 	}
 }
 
-func (db *Db) UpdateReputation(id string, repIncrease int64) bool {
-	// ensuring reputation will not go negative (haha, this can probably be solved in a simpler way inside SQL, but hey, it works)
-	if repIncrease < 0 {
-		res := db.orm.Model(&models.RegisteredMix{}).Where("identity_key = ? AND reputation >= ?", id, -repIncrease).Update("reputation", gorm.Expr("reputation + ?", repIncrease))
-		if res.Error != nil {
-			return false
-		}
-		if res.RowsAffected > 0 {
-			return true
-		}
-
-		res = db.orm.Model(&models.RegisteredGateway{}).Where("identity_key = ? AND reputation >= ?", id, -repIncrease).Update("reputation", gorm.Expr("reputation + ?", repIncrease))
-		if res.Error != nil {
-			return false
-		}
-
-		if res.RowsAffected > 0 {
-			return true
-		} else {
-			return false
-		}
-	} else {
-		res := db.orm.Model(&models.RegisteredMix{}).Where("identity_key = ?", id).Update("reputation", gorm.Expr("reputation + ?", repIncrease))
+// BatchLoadReputations retrieves the reputations of the registered mixes and gateways with the provided public keys.
+// Nodes which aren't registered are left out.
+func (db *Db) BatchLoadReputations(pubkeys []string) map[string]int64 {
+	type nodeReputation struct {
+		IdentityKey string
+		Reputation  int64
+	}
 
-		if res.Error != nil {
-			return false
+	reputations := make(map[string]int64, len(pubkeys))
+	for _, model := range []interface{}{&models.RegisteredMix{}, &models.RegisteredGateway{}} {
+		var nodes []nodeReputation
+		if err := db.orm.Model(model).Select("identity_key, reputation").Where("identity_key IN ?", pubkeys).Scan(&nodes).Error; err != nil {
+			fmt.Printf("ERROR while retrieving reputations %+v", err)
+			continue
 		}
-		if res.RowsAffected > 0 {
-			return true
-		}
-
-		res = db.orm.Model(&models.RegisteredGateway{}).Where("identity_key = ?", id).Update("reputation", gorm.Expr("reputation + ?", repIncrease))
-		if res.Error != nil {
-			return false
+		for _, node := range nodes {
+			reputations[node.IdentityKey] = node.Reputation
 		}
+	}
+	return reputations
+}
 
-		if res.RowsAffected > 0 {
-			return true
-		} else {
-			return false
+// UpdateReputation changes the reputation of a node, recording the change for the given reason in the reputation
+// ledger
+func (db *Db) UpdateReputation(id string, repIncrease int64, reason string) bool {
+	return db.changeReputation(id, reason, func(node *gorm.DB) *gorm.DB {
+		// ensuring reputation will not go negative (haha, this can probably be solved in a simpler way inside SQL, but hey, it works)
+		if repIncrease < 0 {
+			node = node.Where("reputation >= ?", -repIncrease)
 		}
-	}
+		return node.Update("reputation", gorm.Expr("reputation + ?", repIncrease))
+	})
 }
 
 func (db *Db) Topology() models.Topology {
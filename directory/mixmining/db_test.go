@@ -19,14 +19,89 @@ import (
 	"github.com/nymtech/nym/validator/nym/directory/models"
 	. "github.com/onsi/ginkgo"
 	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"os"
 	"time"
 )
 
+// testPostgresDSN names the environment variable pointing the db specs at a disposable PostgreSQL database
+const testPostgresDSN = "NYM_DIRECTORY_TEST_POSTGRES_DSN"
+
+// dbBackends open an empty Db on each of the supported storage backends
+var dbBackends = []struct {
+	name string
+	open func() *Db
+}{
+	{"a sqlite file", func() *Db {
+		file, err := ioutil.TempFile("", "test_mixmining-*.db")
+		if err != nil {
+			panic(err)
+		}
+		if err := file.Close(); err != nil {
+			panic(err)
+		}
+		db := NewDb(file.Name())
+		testDbFiles[db] = file.Name()
+		return db
+	}},
+	{"an in-memory sqlite database", func() *Db {
+		return NewDb(MemoryDSN)
+	}},
+	{"postgres", openTestPostgres},
+}
+
+// testDbFiles are the sqlite files opened by the current spec, by their Db
+var testDbFiles = make(map[*Db]string)
+
+// removeTestDbFiles closes and removes the sqlite files opened by the current spec
+func removeTestDbFiles() {
+	for db, file := range testDbFiles {
+		if err := db.Close(); err != nil {
+			panic(err)
+		}
+		if err := os.Remove(file); err != nil {
+			panic(err)
+		}
+		delete(testDbFiles, db)
+	}
+}
+
+var testPostgres *Db
+
+// openTestPostgres connects to the database once and wipes it before every spec
+func openTestPostgres() *Db {
+	dsn := os.Getenv(testPostgresDSN)
+	if dsn == "" {
+		Skip(testPostgresDSN + " is not set")
+	}
+	if testPostgres == nil {
+		testPostgres = NewDb(dsn)
+	}
+	// undo whatever the migration specs left behind
+	testPostgres.orm.Where("version > ?", LatestSchemaVersion()).Delete(&models.SchemaVersion{})
+	if _, err := testPostgres.Migrate(LatestSchemaVersion()); err != nil {
+		panic(err)
+	}
+	testPostgres.orm.Exec("TRUNCATE persisted_mix_statuses, mix_status_reports, registered_mixes, registered_gateways, removed_mixes, removed_gateways, hourly_uptimes, daily_uptimes, reputation_changes")
+	return testPostgres
+}
+
 var _ = Describe("The mixmining db", func() {
+	AfterEach(removeTestDbFiles)
+
+	for _, backend := range dbBackends {
+		backend := backend
+		Context("backed by "+backend.name, func() {
+			dbSpecs(backend.open)
+		})
+	}
+})
+
+func dbSpecs(newTestDb func() *Db) {
 	Describe("Constructing a NewDb", func() {
 		Context("a new db", func() {
 			It("should have no mixmining statuses", func() {
-				db := NewDb(true)
+				db := newTestDb()
 				db.orm.Exec("DELETE FROM persisted_mix_statuses")
 				assert.Len(GinkgoT(), db.ListMixStatus("foo", 5), 0)
 			})
@@ -36,7 +111,7 @@ var _ = Describe("The mixmining db", func() {
 	Describe("adding and retrieving measurements", func() {
 		Context("a new db", func() {
 			It("should add measurements to the db, with a timestamp, and be able to retrieve them afterwards", func() {
-				db := NewDb(true)
+				db := newTestDb()
 				db.orm.Exec("DELETE FROM persisted_mix_statuses")
 				status := fixtures.GoodPersistedMixStatus()
 
@@ -59,14 +134,14 @@ var _ = Describe("The mixmining db", func() {
 	Describe("listing mix statuses within a date range", func() {
 		Context("for an empty db", func() {
 			It("should return an empty slice", func() {
-				db := NewDb(true)
+				db := newTestDb()
 				db.orm.Exec("DELETE FROM persisted_mix_statuses")
 				assert.Len(GinkgoT(), db.ListMixStatusDateRange("foo", "6", 1, 1), 0)
 			})
 		})
 		Context("when one status exists in the range and one outside", func() {
 			It("should return only the status within the range", func() {
-				db := NewDb(true)
+				db := newTestDb()
 				db.orm.Exec("DELETE FROM persisted_mix_statuses")
 				data := fixtures.GoodMixStatus()
 				statusInRange := models.PersistedMixStatus{
@@ -87,7 +162,7 @@ var _ = Describe("The mixmining db", func() {
 		})
 		Context("when one Ipv4 status exists in the range and one outside, with an IPv6 status also in range, when searching for IPv4", func() {
 			It("should return only the status within the range", func() {
-				db := NewDb(true)
+				db := newTestDb()
 				db.orm.Exec("DELETE FROM persisted_mix_statuses")
 				ip4data := fixtures.GoodMixStatus()
 				ip4data.IPVersion = "4"
@@ -120,7 +195,7 @@ var _ = Describe("The mixmining db", func() {
 	Describe("listing mix statuses with a limit", func() {
 		Context("for an empty db", func() {
 			It("should return an empty slice", func() {
-				db := NewDb(true)
+				db := newTestDb()
 				defer db.orm.Exec("DELETE FROM persisted_mix_statuses")
 				assert.Len(GinkgoT(), db.ListMixStatus("foo", 5), 0)
 			})
@@ -130,7 +205,7 @@ var _ = Describe("The mixmining db", func() {
 	Describe("saving a mix status report", func() {
 		Context("for an empty db", func() {
 			It("should save and reload the report", func() {
-				db := NewDb(true)
+				db := newTestDb()
 				db.orm.Exec("DELETE FROM mix_status_reports")
 				newReport := models.MixStatusReport{
 					PubKey:           "key",
@@ -150,7 +225,7 @@ var _ = Describe("The mixmining db", func() {
 		})
 		Context("when saving a second time", func() {
 			It("should re-save the original report, and not make a second copy", func() {
-				db := NewDb(true)
+				db := newTestDb()
 				db.orm.Exec("DELETE FROM mix_status_reports")
 
 				newReport := models.MixStatusReport{
@@ -185,10 +260,29 @@ var _ = Describe("The mixmining db", func() {
 		})
 	})
 
+	Describe("loading the non stale reports", func() {
+		It("should only load the reports of nodes up for at least the removal uptime over the last day", func() {
+			db := newTestDb()
+			db.orm.Exec("DELETE FROM mix_status_reports")
+			db.SaveBatchMixStatusReport(models.BatchMixStatusReport{Report: []models.MixStatusReport{
+				{PubKey: "v4", LastDayIPV4: 70, LastDayIPV6: UnknownUptime},
+				{PubKey: "v6", LastDayIPV4: 10, LastDayIPV6: 80},
+				{PubKey: "stale", LastDayIPV4: 60, LastDayIPV6: 60},
+			}})
+
+			var pubkeys []string
+			for _, report := range db.LoadNonStaleReports(70).Report {
+				pubkeys = append(pubkeys, report.PubKey)
+			}
+			assert.ElementsMatch(GinkgoT(), []string{"v4", "v6"}, pubkeys)
+			assert.Len(GinkgoT(), db.LoadNonStaleReports(50).Report, 3)
+		})
+	})
+
 	Describe("Registering mix node", func() {
 		Context("For the first time", func() {
 			It("should add the entry, with timestamp and initial reputation, to database", func() {
-				db := NewDb(true)
+				db := newTestDb()
 				all := db.allRegisteredMixes()
 				assert.Len(GinkgoT(), all, 0)
 
@@ -209,7 +303,7 @@ var _ = Describe("The mixmining db", func() {
 		})
 		Context("For second time", func() {
 			It("should overwrite the existing entry without making a new one", func() {
-				db := NewDb(true)
+				db := newTestDb()
 				all := db.allRegisteredMixes()
 				assert.Len(GinkgoT(), all, 0)
 
@@ -238,7 +332,7 @@ var _ = Describe("The mixmining db", func() {
 
 		Context("Multiple with different identity", func() {
 			It("Should not overwrite each other", func() {
-				db := NewDb(true)
+				db := newTestDb()
 				all := db.allRegisteredMixes()
 				assert.Len(GinkgoT(), all, 0)
 
@@ -257,7 +351,7 @@ var _ = Describe("The mixmining db", func() {
 	Describe("Removing mix node", func() {
 		Context("If it exists", func() {
 			It("Should get rid of it", func() {
-				db := NewDb(true)
+				db := newTestDb()
 				all := db.allRegisteredMixes()
 				assert.Len(GinkgoT(), all, 0)
 
@@ -273,7 +367,7 @@ var _ = Describe("The mixmining db", func() {
 
 		Context("If it doesn't exist", func() {
 			It("Shouldn't do anything", func() {
-				db := NewDb(true)
+				db := newTestDb()
 				all := db.allRegisteredMixes()
 				assert.Len(GinkgoT(), all, 0)
 
@@ -289,7 +383,7 @@ var _ = Describe("The mixmining db", func() {
 	Describe("Registering gateway", func() {
 		Context("For the first time", func() {
 			It("should add the entry, with timestamp and initial reputation, to database", func() {
-				db := NewDb(true)
+				db := newTestDb()
 				all := db.allRegisteredGateways()
 				assert.Len(GinkgoT(), all, 0)
 
@@ -310,7 +404,7 @@ var _ = Describe("The mixmining db", func() {
 		})
 		Context("For second time", func() {
 			It("should overwrite the existing entry without making a new one", func() {
-				db := NewDb(true)
+				db := newTestDb()
 				all := db.allRegisteredGateways()
 				assert.Len(GinkgoT(), all, 0)
 
@@ -339,7 +433,7 @@ var _ = Describe("The mixmining db", func() {
 
 		Context("Multiple with different identity", func() {
 			It("Should not overwrite each other", func() {
-				db := NewDb(true)
+				db := newTestDb()
 				all := db.allRegisteredGateways()
 				assert.Len(GinkgoT(), all, 0)
 
@@ -358,7 +452,7 @@ var _ = Describe("The mixmining db", func() {
 	Describe("Removing gateway node", func() {
 		Context("If it exists", func() {
 			It("Should get rid of it", func() {
-				db := NewDb(true)
+				db := newTestDb()
 				all := db.allRegisteredGateways()
 				assert.Len(GinkgoT(), all, 0)
 
@@ -376,7 +470,7 @@ var _ = Describe("The mixmining db", func() {
 	Describe("Setting reputation", func() {
 		Context("For existing node", func() {
 			It("Sets it to defined value", func() {
-				db := NewDb(true)
+				db := newTestDb()
 				all := db.allRegisteredMixes()
 				assert.Len(GinkgoT(), all, 0)
 
@@ -385,7 +479,7 @@ var _ = Describe("The mixmining db", func() {
 				all = db.allRegisteredMixes()
 				assert.Equal(GinkgoT(), all[0].Reputation, int64(0))
 
-				wasChanged := db.SetReputation(mix.IdentityKey, 42)
+				wasChanged := db.SetReputation(mix.IdentityKey, 42, models.ReputationChangeAdminOverride)
 				assert.True(GinkgoT(), wasChanged)
 
 				all = db.allRegisteredMixes()
@@ -395,11 +489,120 @@ var _ = Describe("The mixmining db", func() {
 
 		Context("For non-existent node", func() {
 			It("Does nothing", func() {
-				db := NewDb(true)
+				db := newTestDb()
 				all := db.allRegisteredMixes()
 				assert.Len(GinkgoT(), all, 0)
 
-				wasChanged := db.SetReputation("foomp", 42)
+				wasChanged := db.SetReputation("foomp", 42, models.ReputationChangeAdminOverride)
+				assert.False(GinkgoT(), wasChanged)
+			})
+		})
+	})
+
+	Describe("Loading reputations", func() {
+		It("Returns those of the registered mixes and gateways asked for", func() {
+			db := newTestDb()
+			mix := fixtures.GoodRegisteredMix()
+			db.RegisterMix(mix)
+			db.SetReputation(mix.IdentityKey, 42, models.ReputationChangeAdminOverride)
+			gateway := fixtures.GoodRegisteredGateway()
+			db.RegisterGateway(gateway)
+			db.SetReputation(gateway.IdentityKey, 7, models.ReputationChangeAdminOverride)
+
+			reputations := db.BatchLoadReputations([]string{mix.IdentityKey, gateway.IdentityKey, "foomp"})
+			assert.Equal(GinkgoT(), map[string]int64{mix.IdentityKey: 42, gateway.IdentityKey: 7}, reputations)
+		})
+	})
+
+	Describe("Recording reputation changes", func() {
+		change := func(pubkey string, delta int64, reputation int64, reason string) models.ReputationChange {
+			return models.ReputationChange{
+				PubKey:     pubkey,
+				Delta:      delta,
+				Reputation: reputation,
+				Reason:     reason,
+			}
+		}
+		// history lists the changes of a node without their ids and timestamps
+		history := func(db *Db, pubkey string) []models.ReputationChange {
+			changes := db.ListReputationChanges(pubkey, 10)
+			for i := range changes {
+				changes[i].ID = 0
+				changes[i].Timestamp = 0
+			}
+			return changes
+		}
+
+		It("records every change, most recent first", func() {
+			db := newTestDb()
+			mix := fixtures.GoodRegisteredMix()
+			db.RegisterMix(mix)
+			gateway := fixtures.GoodRegisteredGateway()
+			db.RegisterGateway(gateway)
+
+			db.SetReputation(mix.IdentityKey, 42, models.ReputationChangeAdminOverride)
+			db.UpdateReputation(mix.IdentityKey, -2, models.ReputationChangeMonitorReport)
+			db.BatchUpdateReputation(map[string]int64{mix.IdentityKey: -20, gateway.IdentityKey: 3}, models.ReputationChangeDecay)
+
+			assert.Equal(GinkgoT(), []models.ReputationChange{
+				change(mix.IdentityKey, -20, 20, models.ReputationChangeDecay),
+				change(mix.IdentityKey, -2, 40, models.ReputationChangeMonitorReport),
+				change(mix.IdentityKey, 42, 42, models.ReputationChangeAdminOverride),
+			}, history(db, mix.IdentityKey))
+			assert.Equal(GinkgoT(), []models.ReputationChange{
+				change(gateway.IdentityKey, 3, 3, models.ReputationChangeDecay),
+			}, history(db, gateway.IdentityKey))
+		})
+
+		It("records nothing when the reputation doesn't change", func() {
+			db := newTestDb()
+			mix := fixtures.GoodRegisteredMix()
+			db.RegisterMix(mix)
+
+			db.SetReputation(mix.IdentityKey, 0, models.ReputationChangeAdminOverride)
+			// the reputation can't go negative
+			assert.False(GinkgoT(), db.UpdateReputation(mix.IdentityKey, -2, models.ReputationChangeMonitorReport))
+			db.SetReputation("foomp", 42, models.ReputationChangeAdminOverride)
+
+			assert.Len(GinkgoT(), history(db, mix.IdentityKey), 0)
+			assert.Len(GinkgoT(), history(db, "foomp"), 0)
+		})
+
+		It("lists at most the asked number of changes", func() {
+			db := newTestDb()
+			mix := fixtures.GoodRegisteredMix()
+			db.RegisterMix(mix)
+			for i := int64(1); i <= 3; i++ {
+				db.SetReputation(mix.IdentityKey, i, models.ReputationChangeAdminOverride)
+			}
+
+			changes := db.ListReputationChanges(mix.IdentityKey, 2)
+			assert.Len(GinkgoT(), changes, 2)
+			assert.Equal(GinkgoT(), int64(3), changes[0].Reputation)
+			assert.Equal(GinkgoT(), int64(2), changes[1].Reputation)
+		})
+	})
+
+	Describe("Setting mix layer", func() {
+		Context("For existing mix", func() {
+			It("Moves it to the given layer", func() {
+				db := newTestDb()
+				mix := fixtures.GoodRegisteredMix()
+				db.RegisterMix(mix)
+
+				wasChanged := db.SetMixLayer(mix.IdentityKey, 3)
+				assert.True(GinkgoT(), wasChanged)
+
+				all := db.allRegisteredMixes()
+				assert.Equal(GinkgoT(), uint(3), all[0].Layer)
+			})
+		})
+
+		Context("For non-existent mix", func() {
+			It("Does nothing", func() {
+				db := newTestDb()
+
+				wasChanged := db.SetMixLayer("foomp", 3)
 				assert.False(GinkgoT(), wasChanged)
 			})
 		})
@@ -408,7 +611,7 @@ var _ = Describe("The mixmining db", func() {
 	Describe("Getting topology", func() {
 		Context("With no registered nodes", func() {
 			It("Returns empty slices", func() {
-				db := NewDb(true)
+				db := newTestDb()
 				allMix := db.allRegisteredMixes()
 				assert.Len(GinkgoT(), allMix, 0)
 
@@ -422,7 +625,7 @@ var _ = Describe("The mixmining db", func() {
 		})
 		Context("With registered nodes", func() {
 			It("Returns all registered mixnodes and gateways", func() {
-				db := NewDb(true)
+				db := newTestDb()
 				allMix := db.allRegisteredMixes()
 				assert.Len(GinkgoT(), allMix, 0)
 
@@ -453,7 +656,7 @@ var _ = Describe("The mixmining db", func() {
 	Describe("Getting active topology", func () {
 		Context("With registered nodes but below reputation threshold", func() {
 			It("Returns empty slices", func() {
-				db := NewDb(true)
+				db := newTestDb()
 				allMix := db.allRegisteredMixes()
 				assert.Len(GinkgoT(), allMix, 0)
 
@@ -466,8 +669,8 @@ var _ = Describe("The mixmining db", func() {
 				db.RegisterMix(mix1)
 				db.RegisterGateway(gate1)
 
-				db.SetReputation(mix1.IdentityKey, ReputationThreshold - 1)
-				db.SetReputation(gate1.IdentityKey, ReputationThreshold - 1)
+				db.SetReputation(mix1.IdentityKey, ReputationThreshold - 1, models.ReputationChangeAdminOverride)
+				db.SetReputation(gate1.IdentityKey, ReputationThreshold - 1, models.ReputationChangeAdminOverride)
 
 				topology := db.ActiveTopology(ReputationThreshold)
 				assert.Len(GinkgoT(), topology.MixNodes, 0)
@@ -477,7 +680,7 @@ var _ = Describe("The mixmining db", func() {
 
 		Context("With registered nodes, some above reputation threshold", func() {
 			It("Returns only the nodes above the reputation threshold", func() {
-				db := NewDb(true)
+				db := newTestDb()
 				allMix := db.allRegisteredMixes()
 				assert.Len(GinkgoT(), allMix, 0)
 
@@ -498,10 +701,10 @@ var _ = Describe("The mixmining db", func() {
 				db.RegisterGateway(gate1)
 				db.RegisterGateway(gate2)
 
-				db.SetReputation(mix1.IdentityKey, ReputationThreshold - 1)
-				db.SetReputation(gate1.IdentityKey, ReputationThreshold - 1)
-				db.SetReputation(mix2.IdentityKey, ReputationThreshold)
-				db.SetReputation(gate2.IdentityKey, ReputationThreshold)
+				db.SetReputation(mix1.IdentityKey, ReputationThreshold - 1, models.ReputationChangeAdminOverride)
+				db.SetReputation(gate1.IdentityKey, ReputationThreshold - 1, models.ReputationChangeAdminOverride)
+				db.SetReputation(mix2.IdentityKey, ReputationThreshold, models.ReputationChangeAdminOverride)
+				db.SetReputation(gate2.IdentityKey, ReputationThreshold, models.ReputationChangeAdminOverride)
 
 				topology := db.ActiveTopology(ReputationThreshold)
 				// this is just so the comparison is easier
@@ -524,7 +727,7 @@ var _ = Describe("The mixmining db", func() {
 		It("works for ipv4", func() {
 			ip1 := "1.2.3.4:1789"
 			ip2 := "1.2.3.4:1790"
-			db := NewDb(true)
+			db := newTestDb()
 			mix1 := fixtures.GoodRegisteredMix()
 			mix1.MixHost = ip1
 
@@ -543,7 +746,7 @@ var _ = Describe("The mixmining db", func() {
 			ipv6Compressed1 := "[2001:db8:a0b:12f0::1]:1789"
 			ipv6Compressed2 := "[2001:db8:a0b:12f0::1]:1790"
 
-			db := NewDb(true)
+			db := newTestDb()
 			mix1 := fixtures.GoodRegisteredMix()
 			mix1.MixHost = ipv6Normal1
 
@@ -570,7 +773,7 @@ var _ = Describe("The mixmining db", func() {
 			name1 := "foomp.com:1789"
 			name2 := "foomp.com:1790"
 
-			db := NewDb(true)
+			db := newTestDb()
 			mix1 := fixtures.GoodRegisteredMix()
 			mix1.MixHost = name1
 
@@ -587,7 +790,7 @@ var _ = Describe("The mixmining db", func() {
 			ip1 := "1.2.3.4:1789"
 			ip2 := "1.2.3.4:1790"
 
-			db := NewDb(true)
+			db := newTestDb()
 			gate1 := fixtures.GoodRegisteredGateway()
 			gate1.MixHost = ip1
 
@@ -600,4 +803,120 @@ var _ = Describe("The mixmining db", func() {
 			assert.True(GinkgoT(), db.IpExists(ip2))
 		})
 	})
-})
+
+	Describe("Rolling up statuses", func() {
+		hour := int64(time.Hour)
+		days := int64(day)
+		statusAt := func(pubkey string, ipVersion string, timestamp int64, up bool) models.PersistedMixStatus {
+			return models.PersistedMixStatus{
+				MixStatus: models.MixStatus{PubKey: pubkey, IPVersion: ipVersion, Up: &up},
+				Timestamp: timestamp,
+			}
+		}
+		newRollupDb := func() *Db {
+			db := newTestDb()
+			db.orm.Exec("DELETE FROM persisted_mix_statuses")
+			db.orm.Exec("DELETE FROM hourly_uptimes")
+			db.orm.Exec("DELETE FROM daily_uptimes")
+			db.AddMixStatus(statusAt("key", "4", 10*days+hour, true))
+			db.AddMixStatus(statusAt("key", "4", 10*days+hour+1, false))
+			db.AddMixStatus(statusAt("key", "4", 10*days+2*hour, true))
+			db.AddMixStatus(statusAt("key", "6", 10*days+hour, false))
+			db.AddMixStatus(statusAt("key", "4", 11*days, true))
+			db.AddMixStatus(statusAt("other", "4", 11*days, true))
+			return db
+		}
+
+		It("aggregates them per node, ip version and hour", func() {
+			db := newRollupDb()
+			assert.Nil(GinkgoT(), db.RollUpStatuses(10*days))
+
+			assert.Equal(GinkgoT(), []models.UptimeRollup{
+				{PubKey: "key", IPVersion: "4", Start: 10*days + hour, Reports: 2, Up: 1},
+				{PubKey: "key", IPVersion: "4", Start: 10*days + 2*hour, Reports: 1, Up: 1},
+				{PubKey: "key", IPVersion: "4", Start: 11 * days, Reports: 1, Up: 1},
+			}, db.ListHourlyUptimes("key", "4", 0, 11*days))
+			assert.Equal(GinkgoT(), []models.UptimeRollup{
+				{PubKey: "key", IPVersion: "6", Start: 10*days + hour, Reports: 1, Up: 0},
+			}, db.ListHourlyUptimes("key", "6", 0, 11*days))
+		})
+
+		It("aggregates them per node, ip version and day", func() {
+			db := newRollupDb()
+			assert.Nil(GinkgoT(), db.RollUpStatuses(10*days))
+
+			assert.Equal(GinkgoT(), []models.UptimeRollup{
+				{PubKey: "key", IPVersion: "4", Start: 10 * days, Reports: 3, Up: 2},
+				{PubKey: "key", IPVersion: "4", Start: 11 * days, Reports: 1, Up: 1},
+			}, db.ListDailyUptimes("key", "4", 0, 11*days))
+			assert.Equal(GinkgoT(), []models.UptimeRollup{
+				{PubKey: "key", IPVersion: "4", Start: 11 * days, Reports: 1, Up: 1},
+			}, db.ListDailyUptimes("key", "4", 11*days, 12*days))
+		})
+
+		It("replaces the rollups of the periods rolled up again", func() {
+			db := newRollupDb()
+			assert.Nil(GinkgoT(), db.RollUpStatuses(10*days))
+			db.AddMixStatus(statusAt("key", "4", 11*days+1, false))
+			assert.Nil(GinkgoT(), db.RollUpStatuses(11*days))
+
+			assert.Equal(GinkgoT(), []models.UptimeRollup{
+				{PubKey: "key", IPVersion: "4", Start: 10 * days, Reports: 3, Up: 2},
+				{PubKey: "key", IPVersion: "4", Start: 11 * days, Reports: 2, Up: 1},
+			}, db.ListDailyUptimes("key", "4", 0, 11*days))
+		})
+
+		It("keeps the rollups of purged statuses", func() {
+			db := newRollupDb()
+			assert.Nil(GinkgoT(), db.RollUpStatuses(10*days))
+			db.RemoveOldStatuses(11 * days)
+			assert.Nil(GinkgoT(), db.RollUpStatuses(11*days))
+
+			assert.Len(GinkgoT(), db.ListDailyUptimes("key", "4", 0, 11*days), 2)
+		})
+
+		It("doesn't replace rollups with ones built from partly purged statuses, as after a restart", func() {
+			db := newRollupDb()
+			assert.Nil(GinkgoT(), db.RollUpStatuses(10*days))
+			db.RemoveOldStatuses(10*days + 2*hour)
+			// a restarted service doesn't know what it last rolled up, and starts over from the oldest statuses
+			assert.Nil(GinkgoT(), db.RollUpStatuses(10*days))
+
+			assert.Equal(GinkgoT(), []models.UptimeRollup{
+				{PubKey: "key", IPVersion: "4", Start: 10*days + hour, Reports: 2, Up: 1},
+				{PubKey: "key", IPVersion: "4", Start: 10*days + 2*hour, Reports: 1, Up: 1},
+				{PubKey: "key", IPVersion: "4", Start: 11 * days, Reports: 1, Up: 1},
+			}, db.ListHourlyUptimes("key", "4", 0, 11*days))
+			assert.Equal(GinkgoT(), []models.UptimeRollup{
+				{PubKey: "key", IPVersion: "4", Start: 10 * days, Reports: 3, Up: 2},
+				{PubKey: "key", IPVersion: "4", Start: 11 * days, Reports: 1, Up: 1},
+			}, db.ListDailyUptimes("key", "4", 0, 11*days))
+			assert.Equal(GinkgoT(), []models.UptimeRollup{
+				{PubKey: "key", IPVersion: "6", Start: 10 * days, Reports: 1, Up: 0},
+			}, db.ListDailyUptimes("key", "6", 0, 11*days))
+		})
+
+		It("sums up the daily uptimes since a given day", func() {
+			db := newRollupDb()
+			assert.Nil(GinkgoT(), db.RollUpStatuses(10*days))
+
+			assert.ElementsMatch(GinkgoT(), []models.UptimeRollup{
+				{PubKey: "key", IPVersion: "4", Start: 10 * days, Reports: 4, Up: 3},
+				{PubKey: "key", IPVersion: "6", Start: 10 * days, Reports: 1, Up: 0},
+			}, db.SumDailyUptimes(10*days, []string{"key"}))
+			assert.ElementsMatch(GinkgoT(), []models.UptimeRollup{
+				{PubKey: "key", IPVersion: "4", Start: 11 * days, Reports: 1, Up: 1},
+				{PubKey: "other", IPVersion: "4", Start: 11 * days, Reports: 1, Up: 1},
+			}, db.SumDailyUptimes(11*days, nil))
+		})
+
+		It("removes the rollups past their retention", func() {
+			db := newRollupDb()
+			assert.Nil(GinkgoT(), db.RollUpStatuses(10*days))
+			db.RemoveOldRollups(11*days, 10*days)
+
+			assert.Len(GinkgoT(), db.ListHourlyUptimes("key", "4", 0, 11*days), 1)
+			assert.Len(GinkgoT(), db.ListDailyUptimes("key", "4", 0, 11*days), 2)
+		})
+	})
+}
@@ -0,0 +1,84 @@
+// Copyright 2020 Nym Technologies SA
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mixmining
+
+import (
+	"fmt"
+
+	"github.com/BorisBorshevsky/timemock"
+	"github.com/nymtech/nym/validator/nym/directory/models"
+	"gorm.io/gorm"
+)
+
+// changeReputation applies the update to the registered mix or gateway with the given identity, and records the
+// change it made to its reputation in the reputation ledger. It reports whether the node got updated.
+func (db *Db) changeReputation(id string, reason string, update func(node *gorm.DB) *gorm.DB) bool {
+	updated := false
+	err := db.orm.Transaction(func(tx *gorm.DB) error {
+		for _, model := range []interface{}{&models.RegisteredMix{}, &models.RegisteredGateway{}} {
+			var before []int64
+			if err := tx.Model(model).Where("identity_key = ?", id).Pluck("reputation", &before).Error; err != nil {
+				return err
+			}
+			if len(before) == 0 {
+				continue
+			}
+
+			res := update(tx.Model(model).Where("identity_key = ?", id))
+			if res.Error != nil || res.RowsAffected == 0 {
+				return res.Error
+			}
+			updated = true
+
+			var after []int64
+			if err := tx.Model(model).Where("identity_key = ?", id).Pluck("reputation", &after).Error; err != nil {
+				return err
+			}
+			if len(after) == 0 || after[0] == before[0] {
+				return nil
+			}
+			return tx.Create(&models.ReputationChange{
+				PubKey:     id,
+				Delta:      after[0] - before[0],
+				Reputation: after[0],
+				Reason:     reason,
+				Timestamp:  timemock.Now().UnixNano(),
+			}).Error
+		}
+		return nil
+	})
+	if err != nil {
+		fmt.Printf("failed to change the reputation of %s - %v\n", id, err)
+		return false
+	}
+	return updated
+}
+
+// ListReputationChanges lists the most recent changes of the reputation of a node, with the maximum of `limit` results
+func (db *Db) ListReputationChanges(pubkey string, limit int) []models.ReputationChange {
+	var changes []models.ReputationChange
+	if err := db.orm.Order("timestamp desc").Order("id desc").Limit(limit).Where("pub_key = ?", pubkey).Find(&changes).Error; err != nil {
+		return make([]models.ReputationChange, 0)
+	}
+	return changes
+}
+
+// ReputationHistoryLimit is the number of changes listed by the reputation history of a node
+const ReputationHistoryLimit = 1000
+
+// ReputationHistory lists the most recent changes of the reputation of a node
+func (service *Service) ReputationHistory(pubkey string) []models.ReputationChange {
+	return service.db.ListReputationChanges(pubkey, ReputationHistoryLimit)
+}
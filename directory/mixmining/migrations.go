@@ -0,0 +1,314 @@
+// Copyright 2020 Nym Technologies SA
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mixmining
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/nymtech/nym/validator/nym/directory/models"
+	"gorm.io/gorm"
+)
+
+// Migration is a numbered, reversible change of the directory database schema.
+// Up and Down run inside the transaction recording the change in the schema_version table.
+type Migration struct {
+	Version     uint
+	Description string
+	Up          func(tx *gorm.DB) error
+	Down        func(tx *gorm.DB) error
+}
+
+// migrations lists every schema change in order, versions start at 1 and have no gaps.
+// A released migration must never change: append a new one instead.
+var migrations = []Migration{
+	{
+		Version:     1,
+		Description: "create the status, report, registered and removed node tables",
+		Up:          createInitialSchema,
+		Down:        dropInitialSchema,
+	},
+	{
+		Version:     2,
+		Description: "record the monitor that reported each mix status",
+		Up:          addStatusMonitor,
+		Down:        dropStatusMonitor,
+	},
+	{
+		Version:     3,
+		Description: "create the hourly and daily uptime rollup tables",
+		Up:          createUptimeRollups,
+		Down:        dropUptimeRollups,
+	},
+	{
+		Version:     4,
+		Description: "create the reputation ledger",
+		Up:          createReputationLedger,
+		Down:        dropReputationLedger,
+	},
+}
+
+// Migrations returns all known schema migrations in order
+func Migrations() []Migration {
+	return append([]Migration(nil), migrations...)
+}
+
+// LatestSchemaVersion is the schema version this directory works with
+func LatestSchemaVersion() uint {
+	return uint(len(migrations))
+}
+
+// SchemaVersion returns the version of the last migration applied to the database, 0 for an empty one
+func (db *Db) SchemaVersion() (uint, error) {
+	if !db.orm.Migrator().HasTable(&models.SchemaVersion{}) {
+		return 0, nil
+	}
+	var version uint
+	if err := db.orm.Model(&models.SchemaVersion{}).Select("COALESCE(MAX(version), 0)").Scan(&version).Error; err != nil {
+		return 0, err
+	}
+	return version, nil
+}
+
+// Migrate moves the database schema up or down to the target version, one migration per transaction.
+// It returns the migrations applied (or reverted) so far, even when one of them fails.
+func (db *Db) Migrate(target uint) ([]Migration, error) {
+	latest := LatestSchemaVersion()
+	if target > latest {
+		return nil, fmt.Errorf("unknown schema version %d, the latest is %d", target, latest)
+	}
+	if err := db.orm.AutoMigrate(&models.SchemaVersion{}); err != nil {
+		return nil, err
+	}
+	current, err := db.SchemaVersion()
+	if err != nil {
+		return nil, err
+	}
+	if current > latest {
+		return nil, fmt.Errorf("database schema version %d is newer than the latest known %d, refusing to touch it", current, latest)
+	}
+
+	var done []Migration
+	for ; current < target; current++ {
+		migration := migrations[current]
+		err := db.orm.Transaction(func(tx *gorm.DB) error {
+			if err := migration.Up(tx); err != nil {
+				return err
+			}
+			return tx.Create(&models.SchemaVersion{
+				Version:     migration.Version,
+				Description: migration.Description,
+				AppliedAt:   time.Now().UnixNano(),
+			}).Error
+		})
+		if err != nil {
+			return done, fmt.Errorf("migration %d (%s) failed: %v", migration.Version, migration.Description, err)
+		}
+		done = append(done, migration)
+	}
+	for ; current > target; current-- {
+		migration := migrations[current-1]
+		err := db.orm.Transaction(func(tx *gorm.DB) error {
+			if err := migration.Down(tx); err != nil {
+				return err
+			}
+			return tx.Delete(&models.SchemaVersion{}, migration.Version).Error
+		})
+		if err != nil {
+			return done, fmt.Errorf("reverting migration %d (%s) failed: %v", migration.Version, migration.Description, err)
+		}
+		done = append(done, migration)
+	}
+	return done, nil
+}
+
+// The tables as of migration 1. They are frozen copies of the models, so that later changes to the models
+// can't change what this migration does. Databases created before migrations existed already have them,
+// in which case creating them is a no-op.
+
+type v1PersistedMixStatus struct {
+	PubKey    string `gorm:"index:status_index"`
+	IPVersion string `gorm:"index:status_index"`
+	Up        *bool
+	Timestamp int64 `gorm:"index:status_index,sort:desc"`
+}
+
+func (v1PersistedMixStatus) TableName() string { return "persisted_mix_statuses" }
+
+type v1MixStatusReport struct {
+	PubKey           string `gorm:"primaryKey;unique"`
+	MostRecentIPV4   bool
+	Last5MinutesIPV4 int
+	LastHourIPV4     int
+	LastDayIPV4      int
+	MostRecentIPV6   bool
+	Last5MinutesIPV6 int
+	LastHourIPV6     int
+	LastDayIPV6      int
+}
+
+func (v1MixStatusReport) TableName() string { return "mix_status_reports" }
+
+type v1Node struct {
+	MixHost           string
+	IdentityKey       string `gorm:"primaryKey;unique"`
+	SphinxKey         string
+	Version           string
+	Location          string
+	IncentivesAddress string
+	RegistrationTime  int64
+	Reputation        int64
+	Deleted           gorm.DeletedAt
+}
+
+type v1Mix struct {
+	Node  v1Node `gorm:"embedded"`
+	Layer uint
+}
+
+type v1Gateway struct {
+	Node        v1Node `gorm:"embedded"`
+	ClientsHost string
+}
+
+type v1RegisteredMix struct {
+	Mix v1Mix `gorm:"embedded"`
+}
+
+func (v1RegisteredMix) TableName() string { return "registered_mixes" }
+
+type v1RegisteredGateway struct {
+	Gateway v1Gateway `gorm:"embedded"`
+}
+
+func (v1RegisteredGateway) TableName() string { return "registered_gateways" }
+
+type v1RemovedMix struct {
+	Mix v1Mix `gorm:"embedded"`
+}
+
+func (v1RemovedMix) TableName() string { return "removed_mixes" }
+
+type v1RemovedGateway struct {
+	Gateway v1Gateway `gorm:"embedded"`
+}
+
+func (v1RemovedGateway) TableName() string { return "removed_gateways" }
+
+func v1Tables() []interface{} {
+	return []interface{}{
+		&v1PersistedMixStatus{},
+		&v1MixStatusReport{},
+		&v1RegisteredMix{},
+		&v1RegisteredGateway{},
+		&v1RemovedMix{},
+		&v1RemovedGateway{},
+	}
+}
+
+func createInitialSchema(tx *gorm.DB) error {
+	return tx.AutoMigrate(v1Tables()...)
+}
+
+func dropInitialSchema(tx *gorm.DB) error {
+	return tx.Migrator().DropTable(v1Tables()...)
+}
+
+// The statuses table as of migration 2
+
+type v2PersistedMixStatus struct {
+	PubKey    string `gorm:"index:status_index"`
+	IPVersion string `gorm:"index:status_index"`
+	Up        *bool
+	Timestamp int64 `gorm:"index:status_index,sort:desc"`
+	Monitor   string
+}
+
+func (v2PersistedMixStatus) TableName() string { return "persisted_mix_statuses" }
+
+func addStatusMonitor(tx *gorm.DB) error {
+	return tx.Migrator().AddColumn(&v2PersistedMixStatus{}, "Monitor")
+}
+
+// dropStatusMonitor rebuilds the statuses table without the monitor column, as the bundled SQLite can't drop columns
+func dropStatusMonitor(tx *gorm.DB) error {
+	const previous = "persisted_mix_statuses_v2"
+	if err := tx.Migrator().DropIndex(&v2PersistedMixStatus{}, "status_index"); err != nil {
+		return err
+	}
+	if err := tx.Migrator().RenameTable(&v2PersistedMixStatus{}, previous); err != nil {
+		return err
+	}
+	if err := tx.AutoMigrate(&v1PersistedMixStatus{}); err != nil {
+		return err
+	}
+	err := tx.Exec("INSERT INTO persisted_mix_statuses (pub_key, ip_version, up, timestamp) " +
+		"SELECT pub_key, ip_version, up, timestamp FROM " + previous).Error
+	if err != nil {
+		return err
+	}
+	return tx.Migrator().DropTable(previous)
+}
+
+// The rollup tables as of migration 3
+
+type v3UptimeRollup struct {
+	PubKey    string `gorm:"primaryKey"`
+	IPVersion string `gorm:"primaryKey"`
+	Start     int64  `gorm:"primaryKey;index"`
+	Reports   int
+	Up        int
+}
+
+type v3HourlyUptime struct {
+	Rollup v3UptimeRollup `gorm:"embedded"`
+}
+
+func (v3HourlyUptime) TableName() string { return "hourly_uptimes" }
+
+type v3DailyUptime struct {
+	Rollup v3UptimeRollup `gorm:"embedded"`
+}
+
+func (v3DailyUptime) TableName() string { return "daily_uptimes" }
+
+func createUptimeRollups(tx *gorm.DB) error {
+	return tx.AutoMigrate(&v3HourlyUptime{}, &v3DailyUptime{})
+}
+
+func dropUptimeRollups(tx *gorm.DB) error {
+	return tx.Migrator().DropTable(&v3HourlyUptime{}, &v3DailyUptime{})
+}
+
+// The reputation ledger as of migration 4
+
+type v4ReputationChange struct {
+	ID         uint   `gorm:"primaryKey"`
+	PubKey     string `gorm:"index:reputation_change_index"`
+	Delta      int64
+	Reputation int64
+	Reason     string
+	Timestamp  int64 `gorm:"index:reputation_change_index,sort:desc"`
+}
+
+func (v4ReputationChange) TableName() string { return "reputation_changes" }
+
+func createReputationLedger(tx *gorm.DB) error {
+	return tx.AutoMigrate(&v4ReputationChange{})
+}
+
+func dropReputationLedger(tx *gorm.DB) error {
+	return tx.Migrator().DropTable(&v4ReputationChange{})
+}
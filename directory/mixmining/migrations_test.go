@@ -0,0 +1,162 @@
+// Copyright 2020 Nym Technologies SA
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mixmining
+
+import (
+	"github.com/nymtech/nym/validator/nym/directory/mixmining/fixtures"
+	"github.com/nymtech/nym/validator/nym/directory/models"
+	. "github.com/onsi/ginkgo"
+	"github.com/stretchr/testify/assert"
+)
+
+var _ = Describe("The mixmining db migrations", func() {
+	AfterEach(removeTestDbFiles)
+
+	It("should be numbered consecutively from 1", func() {
+		for i, migration := range Migrations() {
+			assert.Equal(GinkgoT(), uint(i+1), migration.Version)
+		}
+	})
+
+	for _, backend := range dbBackends {
+		backend := backend
+		Context("backed by "+backend.name, func() {
+			migrationSpecs(backend.open)
+		})
+	}
+})
+
+var nodeTables = []interface{}{
+	&models.PersistedMixStatus{},
+	&models.MixStatusReport{},
+	&models.RegisteredMix{},
+	&models.RegisteredGateway{},
+	&models.RemovedMix{},
+	&models.RemovedGateway{},
+	&models.HourlyUptime{},
+	&models.DailyUptime{},
+	&models.ReputationChange{},
+}
+
+func migrationSpecs(newTestDb func() *Db) {
+	Describe("Constructing a NewDb", func() {
+		It("should bring the schema to the latest version", func() {
+			db := newTestDb()
+			version, err := db.SchemaVersion()
+			assert.Nil(GinkgoT(), err)
+			assert.Equal(GinkgoT(), LatestSchemaVersion(), version)
+		})
+	})
+
+	Describe("Migrating down to version 0", func() {
+		It("should drop every table and forget the reverted migrations", func() {
+			db := newTestDb()
+			reverted, err := db.Migrate(0)
+			assert.Nil(GinkgoT(), err)
+			assert.Len(GinkgoT(), reverted, int(LatestSchemaVersion()))
+
+			for _, table := range nodeTables {
+				assert.False(GinkgoT(), db.orm.Migrator().HasTable(table))
+			}
+			version, err := db.SchemaVersion()
+			assert.Nil(GinkgoT(), err)
+			assert.Equal(GinkgoT(), uint(0), version)
+		})
+
+		It("should recreate them when migrating back up", func() {
+			db := newTestDb()
+			_, err := db.Migrate(0)
+			assert.Nil(GinkgoT(), err)
+			applied, err := db.Migrate(LatestSchemaVersion())
+			assert.Nil(GinkgoT(), err)
+			assert.Len(GinkgoT(), applied, int(LatestSchemaVersion()))
+
+			for _, table := range nodeTables {
+				assert.True(GinkgoT(), db.orm.Migrator().HasTable(table))
+			}
+			db.RegisterMix(fixtures.GoodRegisteredMix())
+			assert.Len(GinkgoT(), db.allRegisteredMixes(), 1)
+		})
+	})
+
+	Describe("Migrating to the current version", func() {
+		It("should do nothing", func() {
+			db := newTestDb()
+			applied, err := db.Migrate(LatestSchemaVersion())
+			assert.Nil(GinkgoT(), err)
+			assert.Len(GinkgoT(), applied, 0)
+		})
+	})
+
+	Describe("Migrating to an unknown version", func() {
+		It("should fail", func() {
+			db := newTestDb()
+			_, err := db.Migrate(LatestSchemaVersion() + 1)
+			assert.NotNil(GinkgoT(), err)
+		})
+	})
+
+	Describe("Migrating a database created before migrations existed", func() {
+		It("should adopt its tables and keep the data", func() {
+			db := newTestDb()
+			_, err := db.Migrate(0)
+			assert.Nil(GinkgoT(), err)
+			assert.Nil(GinkgoT(), db.orm.Migrator().DropTable(&models.SchemaVersion{}))
+			// those databases were created by AutoMigrate on the models of the time
+			assert.Nil(GinkgoT(), db.orm.AutoMigrate(v1Tables()...))
+			mix := fixtures.GoodRegisteredMix()
+			db.RegisterMix(mix)
+
+			applied, err := db.Migrate(LatestSchemaVersion())
+			assert.Nil(GinkgoT(), err)
+			assert.Len(GinkgoT(), applied, int(LatestSchemaVersion()))
+			all := db.allRegisteredMixes()
+			assert.Len(GinkgoT(), all, 1)
+			assert.Equal(GinkgoT(), mix.IdentityKey, all[0].IdentityKey)
+		})
+	})
+
+	Describe("Reverting the monitor of mix statuses", func() {
+		It("should keep the statuses", func() {
+			db := newTestDb()
+			status := fixtures.GoodPersistedMixStatus()
+			status.Monitor = "monitor"
+			db.AddMixStatus(status)
+
+			_, err := db.Migrate(1)
+			assert.Nil(GinkgoT(), err)
+			assert.False(GinkgoT(), db.orm.Migrator().HasColumn(&v2PersistedMixStatus{}, "Monitor"))
+			assert.True(GinkgoT(), db.orm.Migrator().HasIndex(&v1PersistedMixStatus{}, "status_index"))
+
+			_, err = db.Migrate(2)
+			assert.Nil(GinkgoT(), err)
+			status.Monitor = ""
+			assert.Equal(GinkgoT(), []models.PersistedMixStatus{status}, db.ListMixStatus(status.PubKey, 10))
+		})
+	})
+
+	Describe("Migrating a database newer than the directory", func() {
+		It("should refuse to touch it", func() {
+			db := newTestDb()
+			db.orm.Create(&models.SchemaVersion{Version: LatestSchemaVersion() + 1})
+
+			_, err := db.Migrate(0)
+			assert.NotNil(GinkgoT(), err)
+			for _, table := range nodeTables {
+				assert.True(GinkgoT(), db.orm.Migrator().HasTable(table))
+			}
+		})
+	})
+}
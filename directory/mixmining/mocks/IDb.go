@@ -50,14 +50,30 @@ func (_m *IDb) BatchLoadReports(pubkeys []string) models.BatchMixStatusReport {
 	return r0
 }
 
+// BatchLoadReputations provides a mock function with given fields: pubkeys
+func (_m *IDb) BatchLoadReputations(pubkeys []string) map[string]int64 {
+	ret := _m.Called(pubkeys)
+
+	var r0 map[string]int64
+	if rf, ok := ret.Get(0).(func([]string) map[string]int64); ok {
+		r0 = rf(pubkeys)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[string]int64)
+		}
+	}
+
+	return r0
+}
+
 // BatchMoveToRemovedSet provides a mock function with given fields: pubkeys
 func (_m *IDb) BatchMoveToRemovedSet(pubkeys []string) {
 	_m.Called(pubkeys)
 }
 
-// BatchUpdateReputation provides a mock function with given fields: reputationChangeMap
-func (_m *IDb) BatchUpdateReputation(reputationChangeMap map[string]int64) {
-	_m.Called(reputationChangeMap)
+// BatchUpdateReputation provides a mock function with given fields: reputationChangeMap, reason
+func (_m *IDb) BatchUpdateReputation(reputationChangeMap map[string]int64, reason string) {
+	_m.Called(reputationChangeMap, reason)
 }
 
 // GetNMostRecentMixStatuses provides a mock function with given fields: pubkey, ipVersion, n
@@ -104,6 +120,38 @@ func (_m *IDb) IpExists(ip string) bool {
 	return r0
 }
 
+// ListDailyUptimes provides a mock function with given fields: pubkey, ipVersion, start, end
+func (_m *IDb) ListDailyUptimes(pubkey string, ipVersion string, start int64, end int64) []models.UptimeRollup {
+	ret := _m.Called(pubkey, ipVersion, start, end)
+
+	var r0 []models.UptimeRollup
+	if rf, ok := ret.Get(0).(func(string, string, int64, int64) []models.UptimeRollup); ok {
+		r0 = rf(pubkey, ipVersion, start, end)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.UptimeRollup)
+		}
+	}
+
+	return r0
+}
+
+// ListHourlyUptimes provides a mock function with given fields: pubkey, ipVersion, start, end
+func (_m *IDb) ListHourlyUptimes(pubkey string, ipVersion string, start int64, end int64) []models.UptimeRollup {
+	ret := _m.Called(pubkey, ipVersion, start, end)
+
+	var r0 []models.UptimeRollup
+	if rf, ok := ret.Get(0).(func(string, string, int64, int64) []models.UptimeRollup); ok {
+		r0 = rf(pubkey, ipVersion, start, end)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.UptimeRollup)
+		}
+	}
+
+	return r0
+}
+
 // ListMixStatus provides a mock function with given fields: pubkey, limit
 func (_m *IDb) ListMixStatus(pubkey string, limit int) []models.PersistedMixStatus {
 	ret := _m.Called(pubkey, limit)
@@ -152,13 +200,29 @@ func (_m *IDb) ListMixStatusSinceWithLimit(pubkey string, ipVersion string, sinc
 	return r0
 }
 
-// LoadNonStaleReports provides a mock function with given fields:
-func (_m *IDb) LoadNonStaleReports() models.BatchMixStatusReport {
-	ret := _m.Called()
+// ListReputationChanges provides a mock function with given fields: pubkey, limit
+func (_m *IDb) ListReputationChanges(pubkey string, limit int) []models.ReputationChange {
+	ret := _m.Called(pubkey, limit)
+
+	var r0 []models.ReputationChange
+	if rf, ok := ret.Get(0).(func(string, int) []models.ReputationChange); ok {
+		r0 = rf(pubkey, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.ReputationChange)
+		}
+	}
+
+	return r0
+}
+
+// LoadNonStaleReports provides a mock function with given fields: removalUptime
+func (_m *IDb) LoadNonStaleReports(removalUptime int) models.BatchMixStatusReport {
+	ret := _m.Called(removalUptime)
 
 	var r0 models.BatchMixStatusReport
-	if rf, ok := ret.Get(0).(func() models.BatchMixStatusReport); ok {
-		r0 = rf()
+	if rf, ok := ret.Get(0).(func(int) models.BatchMixStatusReport); ok {
+		r0 = rf(removalUptime)
 	} else {
 		r0 = ret.Get(0).(models.BatchMixStatusReport)
 	}
@@ -195,6 +259,11 @@ func (_m *IDb) RegisterMix(mix models.RegisteredMix) {
 	_m.Called(mix)
 }
 
+// RemoveOldRollups provides a mock function with given fields: hourlyBefore, dailyBefore
+func (_m *IDb) RemoveOldRollups(hourlyBefore int64, dailyBefore int64) {
+	_m.Called(hourlyBefore, dailyBefore)
+}
+
 // RemoveOldStatuses provides a mock function with given fields: before
 func (_m *IDb) RemoveOldStatuses(before int64) {
 	_m.Called(before)
@@ -214,6 +283,20 @@ func (_m *IDb) RemovedTopology() models.Topology {
 	return r0
 }
 
+// RollUpStatuses provides a mock function with given fields: since
+func (_m *IDb) RollUpStatuses(since int64) error {
+	ret := _m.Called(since)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(int64) error); ok {
+		r0 = rf(since)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // SaveBatchMixStatusReport provides a mock function with given fields: _a0
 func (_m *IDb) SaveBatchMixStatusReport(_a0 models.BatchMixStatusReport) {
 	_m.Called(_a0)
@@ -224,13 +307,13 @@ func (_m *IDb) SaveMixStatusReport(_a0 models.MixStatusReport) {
 	_m.Called(_a0)
 }
 
-// SetReputation provides a mock function with given fields: id, newRep
-func (_m *IDb) SetReputation(id string, newRep int64) bool {
-	ret := _m.Called(id, newRep)
+// SetMixLayer provides a mock function with given fields: id, layer
+func (_m *IDb) SetMixLayer(id string, layer uint) bool {
+	ret := _m.Called(id, layer)
 
 	var r0 bool
-	if rf, ok := ret.Get(0).(func(string, int64) bool); ok {
-		r0 = rf(id, newRep)
+	if rf, ok := ret.Get(0).(func(string, uint) bool); ok {
+		r0 = rf(id, layer)
 	} else {
 		r0 = ret.Get(0).(bool)
 	}
@@ -238,6 +321,36 @@ func (_m *IDb) SetReputation(id string, newRep int64) bool {
 	return r0
 }
 
+// SetReputation provides a mock function with given fields: id, newRep, reason
+func (_m *IDb) SetReputation(id string, newRep int64, reason string) bool {
+	ret := _m.Called(id, newRep, reason)
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func(string, int64, string) bool); ok {
+		r0 = rf(id, newRep, reason)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	return r0
+}
+
+// SumDailyUptimes provides a mock function with given fields: since, pubkeys
+func (_m *IDb) SumDailyUptimes(since int64, pubkeys []string) []models.UptimeRollup {
+	ret := _m.Called(since, pubkeys)
+
+	var r0 []models.UptimeRollup
+	if rf, ok := ret.Get(0).(func(int64, []string) []models.UptimeRollup); ok {
+		r0 = rf(since, pubkeys)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.UptimeRollup)
+		}
+	}
+
+	return r0
+}
+
 // Topology provides a mock function with given fields:
 func (_m *IDb) Topology() models.Topology {
 	ret := _m.Called()
@@ -266,13 +379,13 @@ func (_m *IDb) UnregisterNode(id string) bool {
 	return r0
 }
 
-// UpdateReputation provides a mock function with given fields: id, repIncrease
-func (_m *IDb) UpdateReputation(id string, repIncrease int64) bool {
-	ret := _m.Called(id, repIncrease)
+// UpdateReputation provides a mock function with given fields: id, repIncrease, reason
+func (_m *IDb) UpdateReputation(id string, repIncrease int64, reason string) bool {
+	ret := _m.Called(id, repIncrease, reason)
 
 	var r0 bool
-	if rf, ok := ret.Get(0).(func(string, int64) bool); ok {
-		r0 = rf(id, repIncrease)
+	if rf, ok := ret.Get(0).(func(string, int64, string) bool); ok {
+		r0 = rf(id, repIncrease, reason)
 	} else {
 		r0 = ret.Get(0).(bool)
 	}
@@ -5,6 +5,8 @@ package mocks
 import (
 	models "github.com/nymtech/nym/validator/nym/directory/models"
 	mock "github.com/stretchr/testify/mock"
+
+	types "github.com/nymtech/nym/validator/nym/x/nym/types"
 )
 
 // IService is an autogenerated mock type for the IService type
@@ -12,13 +14,13 @@ type IService struct {
 	mock.Mock
 }
 
-// BatchCreateMixStatus provides a mock function with given fields: batchMixStatus
-func (_m *IService) BatchCreateMixStatus(batchMixStatus models.BatchMixStatus) []models.PersistedMixStatus {
-	ret := _m.Called(batchMixStatus)
+// BatchCreateMixStatus provides a mock function with given fields: batchMixStatus, monitor
+func (_m *IService) BatchCreateMixStatus(batchMixStatus models.BatchMixStatus, monitor string) []models.PersistedMixStatus {
+	ret := _m.Called(batchMixStatus, monitor)
 
 	var r0 []models.PersistedMixStatus
-	if rf, ok := ret.Get(0).(func(models.BatchMixStatus) []models.PersistedMixStatus); ok {
-		r0 = rf(batchMixStatus)
+	if rf, ok := ret.Get(0).(func(models.BatchMixStatus, string) []models.PersistedMixStatus); ok {
+		r0 = rf(batchMixStatus, monitor)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]models.PersistedMixStatus)
@@ -56,13 +58,13 @@ func (_m *IService) CheckForDuplicateIP(host string) bool {
 	return r0
 }
 
-// CreateMixStatus provides a mock function with given fields: mixStatus
-func (_m *IService) CreateMixStatus(mixStatus models.MixStatus) models.PersistedMixStatus {
-	ret := _m.Called(mixStatus)
+// CreateMixStatus provides a mock function with given fields: mixStatus, monitor
+func (_m *IService) CreateMixStatus(mixStatus models.MixStatus, monitor string) models.PersistedMixStatus {
+	ret := _m.Called(mixStatus, monitor)
 
 	var r0 models.PersistedMixStatus
-	if rf, ok := ret.Get(0).(func(models.MixStatus) models.PersistedMixStatus); ok {
-		r0 = rf(mixStatus)
+	if rf, ok := ret.Get(0).(func(models.MixStatus, string) models.PersistedMixStatus); ok {
+		r0 = rf(mixStatus, monitor)
 	} else {
 		r0 = ret.Get(0).(models.PersistedMixStatus)
 	}
@@ -140,6 +142,20 @@ func (_m *IService) GetTopology() models.Topology {
 	return r0
 }
 
+// GetUptimeSeries provides a mock function with given fields: pubkey, ipVersion, from, to, bucket
+func (_m *IService) GetUptimeSeries(pubkey string, ipVersion string, from int64, to int64, bucket string) models.UptimeSeries {
+	ret := _m.Called(pubkey, ipVersion, from, to, bucket)
+
+	var r0 models.UptimeSeries
+	if rf, ok := ret.Get(0).(func(string, string, int64, int64, string) models.UptimeSeries); ok {
+		r0 = rf(pubkey, ipVersion, from, to, bucket)
+	} else {
+		r0 = ret.Get(0).(models.UptimeSeries)
+	}
+
+	return r0
+}
+
 // ListMixStatus provides a mock function with given fields: pubkey
 func (_m *IService) ListMixStatus(pubkey string) []models.PersistedMixStatus {
 	ret := _m.Called(pubkey)
@@ -170,14 +186,67 @@ func (_m *IService) MixCount() int {
 	return r0
 }
 
+// ProposeLayerRebalance provides a mock function with given fields:
+func (_m *IService) ProposeLayerRebalance() types.LayerRebalance {
+	ret := _m.Called()
+
+	var r0 types.LayerRebalance
+	if rf, ok := ret.Get(0).(func() types.LayerRebalance); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(types.LayerRebalance)
+	}
+
+	return r0
+}
+
+// RebalanceLayers provides a mock function with given fields:
+func (_m *IService) RebalanceLayers() types.LayerRebalance {
+	ret := _m.Called()
+
+	var r0 types.LayerRebalance
+	if rf, ok := ret.Get(0).(func() types.LayerRebalance); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(types.LayerRebalance)
+	}
+
+	return r0
+}
+
 // RegisterGateway provides a mock function with given fields: info
 func (_m *IService) RegisterGateway(info models.GatewayRegistrationInfo) {
 	_m.Called(info)
 }
 
 // RegisterMix provides a mock function with given fields: info
-func (_m *IService) RegisterMix(info models.MixRegistrationInfo) {
-	_m.Called(info)
+func (_m *IService) RegisterMix(info models.MixRegistrationInfo) uint {
+	ret := _m.Called(info)
+
+	var r0 uint
+	if rf, ok := ret.Get(0).(func(models.MixRegistrationInfo) uint); ok {
+		r0 = rf(info)
+	} else {
+		r0 = ret.Get(0).(uint)
+	}
+
+	return r0
+}
+
+// ReputationHistory provides a mock function with given fields: pubkey
+func (_m *IService) ReputationHistory(pubkey string) []models.ReputationChange {
+	ret := _m.Called(pubkey)
+
+	var r0 []models.ReputationChange
+	if rf, ok := ret.Get(0).(func(string) []models.ReputationChange); ok {
+		r0 = rf(pubkey)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.ReputationChange)
+		}
+	}
+
+	return r0
 }
 
 // SaveBatchStatusReport provides a mock function with given fields: status
@@ -222,9 +291,9 @@ func (_m *IService) SetReputation(id string, newRep int64) bool {
 	return r0
 }
 
-// StartupPurge provides a mock function with given fields:
-func (_m *IService) StartupPurge() {
-	_m.Called()
+// StartupPurge provides a mock function with given fields: systemVersion
+func (_m *IService) StartupPurge(systemVersion string) {
+	_m.Called(systemVersion)
 }
 
 // UnregisterNode provides a mock function with given fields: id, remoteIp
@@ -247,3 +316,19 @@ func (_m *IService) UnregisterNode(id string, remoteIp string) (int, error) {
 
 	return r0, r1
 }
+
+// WorkerStatuses provides a mock function with given fields:
+func (_m *IService) WorkerStatuses() []models.WorkerStatus {
+	ret := _m.Called()
+
+	var r0 []models.WorkerStatus
+	if rf, ok := ret.Get(0).(func() []models.WorkerStatus); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.WorkerStatus)
+		}
+	}
+
+	return r0
+}
@@ -0,0 +1,260 @@
+// Copyright 2020 Nym Technologies SA
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mixmining
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/nymtech/nym/validator/nym/directory/models"
+)
+
+// the reputation policies selectable in the configuration
+const DefaultReputationPolicyName = "default"
+const EWMAReputationPolicyName = "ewma"
+
+// EWMAReputationWeight is how far every status moves a reputation towards its value with the ewma policy
+const EWMAReputationWeight = 0.1
+
+// EWMAReputationMax is the reputation of a node that's always up with the ewma policy, so that it needs to be
+// up more than half the time to stay above the ReputationThreshold
+const EWMAReputationMax = 2 * ReputationThreshold
+
+const ReputationDecayInterval = time.Hour
+
+// RemovalUptime is the last day uptime under which nodes get removed from the topology
+const RemovalUptime = 50
+
+// ReputationPolicy decides how the reputation of nodes evolves with the statuses reported for them, and over time,
+// and when they get removed from the topology
+type ReputationPolicy interface {
+	// Change returns how much a status changes the reputation of its node
+	Change(reputation int64, status models.PersistedMixStatus) int64
+	// Decay returns how much the reputation of a node changes over the elapsed time, regardless of its statuses
+	Decay(reputation int64, elapsed time.Duration) int64
+	// ShouldRemove determines whether a node is still eligible to be part of the main topology or should be moved
+	// into the 'removed set'
+	ShouldRemove(report models.MixStatusReport) bool
+}
+
+// ReputationConfig selects the reputation policy and tunes it
+type ReputationConfig struct {
+	// Policy is either "default", changing the reputation by fixed amounts for every status, or "ewma", making it
+	// an exponentially weighted moving average of the statuses
+	Policy        string `mapstructure:"policy"`
+	SuccessChange int64  `mapstructure:"success_change"`
+	FailureChange int64  `mapstructure:"failure_change"`
+
+	EWMAWeight float64 `mapstructure:"ewma_weight"`
+	EWMAMax    int64   `mapstructure:"ewma_max"`
+
+	// DecayHalfLife is how long it takes for reputations to halve, they don't decay without it
+	DecayHalfLife time.Duration `mapstructure:"decay_half_life"`
+	DecayInterval time.Duration `mapstructure:"decay_interval"`
+
+	// Cap is the highest reputation nodes can get, they're uncapped without it
+	Cap int64 `mapstructure:"cap"`
+
+	RemovalUptime int `mapstructure:"removal_uptime"`
+}
+
+// DefaultReputationConfig returns the reputation policy the directory always had
+func DefaultReputationConfig() ReputationConfig {
+	return ReputationConfig{
+		Policy:        DefaultReputationPolicyName,
+		SuccessChange: ReportSuccessReputationIncrease,
+		FailureChange: ReportFailureReputationDecrease,
+		EWMAWeight:    EWMAReputationWeight,
+		EWMAMax:       EWMAReputationMax,
+		DecayInterval: ReputationDecayInterval,
+		RemovalUptime: RemovalUptime,
+	}
+}
+
+// Validate checks the reputation policy is known and its settings make sense
+func (cfg ReputationConfig) Validate() error {
+	switch cfg.Policy {
+	case DefaultReputationPolicyName:
+	case EWMAReputationPolicyName:
+		if cfg.EWMAWeight <= 0 || cfg.EWMAWeight > 1 {
+			return fmt.Errorf("ewma reputation weight must be within (0, 1], got %v", cfg.EWMAWeight)
+		}
+		if cfg.EWMAMax <= 0 {
+			return fmt.Errorf("ewma maximum reputation must be positive, got %d", cfg.EWMAMax)
+		}
+	default:
+		return fmt.Errorf("unknown reputation policy %q", cfg.Policy)
+	}
+	if cfg.DecayHalfLife < 0 {
+		return fmt.Errorf("reputation decay half-life can't be negative, got %v", cfg.DecayHalfLife)
+	}
+	if cfg.DecayHalfLife > 0 && cfg.DecayInterval <= 0 {
+		return fmt.Errorf("reputation decay interval must be positive, got %v", cfg.DecayInterval)
+	}
+	if cfg.Cap < 0 {
+		return fmt.Errorf("reputation cap can't be negative, got %d", cfg.Cap)
+	}
+	if cfg.RemovalUptime < 0 || cfg.RemovalUptime > 100 {
+		return errors.New("removal uptime must be a percentage")
+	}
+	return nil
+}
+
+// NewPolicy builds the configured reputation policy, which should be valid
+func (cfg ReputationConfig) NewPolicy() ReputationPolicy {
+	var policy ReputationPolicy = DefaultReputationPolicy{
+		SuccessChange: cfg.SuccessChange,
+		FailureChange: cfg.FailureChange,
+		RemovalUptime: cfg.RemovalUptime,
+	}
+	if cfg.Policy == EWMAReputationPolicyName {
+		policy = EWMAReputationPolicy{
+			Weight:        cfg.EWMAWeight,
+			Max:           cfg.EWMAMax,
+			RemovalUptime: cfg.RemovalUptime,
+		}
+	}
+	if cfg.DecayHalfLife > 0 {
+		policy = DecayingReputationPolicy{ReputationPolicy: policy, HalfLife: cfg.DecayHalfLife}
+	}
+	if cfg.Cap > 0 {
+		policy = CappedReputationPolicy{ReputationPolicy: policy, Cap: cfg.Cap}
+	}
+	return policy
+}
+
+// DefaultReputationPolicy changes the reputation by fixed amounts for every status, whichever its ip version
+type DefaultReputationPolicy struct {
+	SuccessChange int64
+	FailureChange int64
+	RemovalUptime int
+}
+
+func (policy DefaultReputationPolicy) Change(reputation int64, status models.PersistedMixStatus) int64 {
+	if *status.Up {
+		return policy.SuccessChange
+	}
+	return policy.FailureChange
+}
+
+func (policy DefaultReputationPolicy) Decay(reputation int64, elapsed time.Duration) int64 {
+	return 0
+}
+
+func (policy DefaultReputationPolicy) ShouldRemove(report models.MixStatusReport) bool {
+	return lowUptime(report, policy.RemovalUptime)
+}
+
+// EWMAReputationPolicy makes the reputation an exponentially weighted moving average of the statuses, an up one
+// being worth Max and a down one 0. Every status moves the reputation Weight of the way towards its worth, so
+// recent statuses matter more than old ones and the reputation reflects the uptime of the node.
+type EWMAReputationPolicy struct {
+	Weight        float64
+	Max           int64
+	RemovalUptime int
+}
+
+func (policy EWMAReputationPolicy) Change(reputation int64, status models.PersistedMixStatus) int64 {
+	worth := int64(0)
+	if *status.Up {
+		worth = policy.Max
+	}
+	return int64(math.Round(policy.Weight * float64(worth-reputation)))
+}
+
+func (policy EWMAReputationPolicy) Decay(reputation int64, elapsed time.Duration) int64 {
+	return 0
+}
+
+func (policy EWMAReputationPolicy) ShouldRemove(report models.MixStatusReport) bool {
+	return lowUptime(report, policy.RemovalUptime)
+}
+
+// DecayingReputationPolicy halves the reputations given by another policy every HalfLife, so that nodes have to
+// keep proving themselves rather than living off their past uptime
+type DecayingReputationPolicy struct {
+	ReputationPolicy
+	HalfLife time.Duration
+}
+
+func (policy DecayingReputationPolicy) Decay(reputation int64, elapsed time.Duration) int64 {
+	reputation += policy.ReputationPolicy.Decay(reputation, elapsed)
+	decayed := math.Round(float64(reputation) * math.Pow(0.5, float64(elapsed)/float64(policy.HalfLife)))
+	return int64(decayed) - reputation
+}
+
+// CappedReputationPolicy keeps the reputations given by another policy under Cap, so that no node can build up
+// enough of it to stay in the active topology long after it stopped mixing
+type CappedReputationPolicy struct {
+	ReputationPolicy
+	Cap int64
+}
+
+func (policy CappedReputationPolicy) Change(reputation int64, status models.PersistedMixStatus) int64 {
+	return policy.capped(reputation, policy.ReputationPolicy.Change(reputation, status))
+}
+
+func (policy CappedReputationPolicy) Decay(reputation int64, elapsed time.Duration) int64 {
+	return policy.capped(reputation, policy.ReputationPolicy.Decay(reputation, elapsed))
+}
+
+func (policy CappedReputationPolicy) capped(reputation int64, change int64) int64 {
+	if reputation+change > policy.Cap {
+		return policy.Cap - reputation
+	}
+	return change
+}
+
+// lowUptime tells whether the last day uptime of a node is under the removal uptime. Unknown uptimes are no
+// evidence of anything.
+func lowUptime(report models.MixStatusReport, removalUptime int) bool {
+	if report.LastDayIPV4 != UnknownUptime && report.LastDayIPV4 < removalUptime {
+		return true
+	}
+
+	// if it ever mixed any ipv6 packet, do the same check for ipv6 uptime
+	if report.LastDayIPV6 > 0 && report.LastDayIPV6 < removalUptime {
+		return true
+	}
+
+	// TODO: does it make sense to also check reputation here? But if we do it, then each new node would get
+	// removed immediately before they even get a chance to build it up
+
+	return false
+}
+
+// decayReputations decays the reputation of every registered node by an interval of the decay worker
+func (service *Service) decayReputations() error {
+	topology := service.db.Topology()
+	elapsed := service.cfg.Reputation.DecayInterval
+
+	changes := make(map[string]int64)
+	for _, mix := range topology.MixNodes {
+		if change := service.reputation.Decay(mix.Reputation, elapsed); change != 0 {
+			changes[mix.IdentityKey] = change
+		}
+	}
+	for _, gateway := range topology.Gateways {
+		if change := service.reputation.Decay(gateway.Reputation, elapsed); change != 0 {
+			changes[gateway.IdentityKey] = change
+		}
+	}
+	if len(changes) > 0 {
+		service.db.BatchUpdateReputation(changes, models.ReputationChangeDecay)
+	}
+	return nil
+}
@@ -0,0 +1,133 @@
+// Copyright 2020 Nym Technologies SA
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mixmining
+
+import (
+	"time"
+
+	"github.com/nymtech/nym/validator/nym/directory/models"
+	. "github.com/onsi/ginkgo"
+	"github.com/stretchr/testify/assert"
+)
+
+// evolve returns the successive reputations of a node starting at the given one, as its statuses get reported
+func evolve(policy ReputationPolicy, reputation int64, statuses ...bool) []int64 {
+	reputations := make([]int64, len(statuses))
+	for i, up := range statuses {
+		up := up
+		reputation += policy.Change(reputation, models.PersistedMixStatus{MixStatus: models.MixStatus{Up: &up}})
+		reputations[i] = reputation
+	}
+	return reputations
+}
+
+var _ = Describe("Reputation policies", func() {
+	const up, down = true, false
+
+	Describe("The default policy", func() {
+		policy := DefaultReputationConfig().NewPolicy()
+
+		It("should change the reputation by fixed amounts", func() {
+			assert.Equal(GinkgoT(), []int64{3, 6, 4, 2, 5}, evolve(policy, 0, up, up, down, down, up))
+		})
+
+		It("should not decay", func() {
+			assert.Equal(GinkgoT(), int64(0), policy.Decay(1000, 24*time.Hour))
+		})
+	})
+
+	Describe("The ewma policy", func() {
+		policy := EWMAReputationPolicy{Weight: 0.5, Max: 200, RemovalUptime: RemovalUptime}
+
+		It("should move the reputation part of the way towards the worth of every status", func() {
+			assert.Equal(GinkgoT(), []int64{100, 150, 75, 37, 119}, evolve(policy, 0, up, up, down, down, up))
+		})
+
+		It("should settle on the uptime of the node", func() {
+			statuses := make([]bool, 0, 100)
+			for i := 0; i < 50; i++ {
+				statuses = append(statuses, up, down)
+			}
+			// up half of the time, the node hovers around half of the maximum reputation
+			reputation := evolve(EWMAReputationPolicy{Weight: 0.1, Max: 200}, 0, statuses...)[len(statuses)-1]
+			assert.InDelta(GinkgoT(), 100, reputation, 10)
+		})
+	})
+
+	Describe("A decaying policy", func() {
+		policy := DecayingReputationPolicy{ReputationPolicy: DefaultReputationConfig().NewPolicy(), HalfLife: time.Hour}
+
+		It("should halve the reputation every half-life", func() {
+			assert.Equal(GinkgoT(), int64(-200), policy.Decay(400, time.Hour))
+			assert.Equal(GinkgoT(), int64(-300), policy.Decay(400, 2*time.Hour))
+			assert.Equal(GinkgoT(), int64(-117), policy.Decay(400, 30*time.Minute))
+		})
+
+		It("should keep changing the reputation with the statuses like the policy it decays", func() {
+			assert.Equal(GinkgoT(), []int64{3, 6, 4}, evolve(policy, 0, up, up, down))
+		})
+	})
+
+	Describe("A capped policy", func() {
+		policy := CappedReputationPolicy{ReputationPolicy: DefaultReputationConfig().NewPolicy(), Cap: 5}
+
+		It("should keep the reputation under the cap", func() {
+			assert.Equal(GinkgoT(), []int64{3, 5, 5, 3, 5}, evolve(policy, 0, up, up, up, down, up))
+		})
+
+		It("should bring reputations over the cap back to it", func() {
+			assert.Equal(GinkgoT(), []int64{5}, evolve(policy, 100, up))
+		})
+	})
+
+	Describe("Removing nodes", func() {
+		policy := DefaultReputationConfig().NewPolicy()
+
+		It("should remove the nodes with a low last day uptime", func() {
+			assert.True(GinkgoT(), policy.ShouldRemove(models.MixStatusReport{LastDayIPV4: 49, LastDayIPV6: UnknownUptime}))
+			assert.True(GinkgoT(), policy.ShouldRemove(models.MixStatusReport{LastDayIPV4: 100, LastDayIPV6: 10}))
+			assert.False(GinkgoT(), policy.ShouldRemove(models.MixStatusReport{LastDayIPV4: 50, LastDayIPV6: 0}))
+		})
+
+		It("should not remove the nodes whose uptime is unknown", func() {
+			assert.False(GinkgoT(), policy.ShouldRemove(models.MixStatusReport{LastDayIPV4: UnknownUptime, LastDayIPV6: UnknownUptime}))
+		})
+	})
+
+	Describe("Configuring the policy", func() {
+		It("should wrap the selected policy into the decay and the cap", func() {
+			cfg := DefaultReputationConfig()
+			cfg.Policy = EWMAReputationPolicyName
+			cfg.DecayHalfLife = time.Hour
+			cfg.Cap = 150
+
+			assert.Nil(GinkgoT(), cfg.Validate())
+			assert.Equal(GinkgoT(), CappedReputationPolicy{
+				ReputationPolicy: DecayingReputationPolicy{
+					ReputationPolicy: EWMAReputationPolicy{Weight: EWMAReputationWeight, Max: EWMAReputationMax, RemovalUptime: RemovalUptime},
+					HalfLife:         time.Hour,
+				},
+				Cap: 150,
+			}, cfg.NewPolicy())
+		})
+
+		It("should reject an ewma weight out of range", func() {
+			cfg := DefaultReputationConfig()
+			cfg.Policy = EWMAReputationPolicyName
+			cfg.EWMAWeight = 1.5
+			assert.NotNil(GinkgoT(), cfg.Validate())
+		})
+	})
+})
@@ -0,0 +1,203 @@
+// Copyright 2020 Nym Technologies SA
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mixmining
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/BorisBorshevsky/timemock"
+	"github.com/nymtech/nym/validator/nym/directory/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const HourlyUptimesRetention = time.Hour * 24 * 30
+const DailyUptimesRetention = time.Hour * 24 * 365
+
+const day = time.Hour * 24
+
+// rollupBatchSize keeps the upserts of rollups well within the bind variables limit of sqlite
+const rollupBatchSize = 100
+
+// RollUpStatuses aggregates the statuses reported since the given timestamp into hourly and daily uptimes,
+// replacing the rollups of the periods they fall in. It should be given the start of a day, for the daily
+// rollup of its first day to include all of it. Rollups are only replaced by ones built from more statuses,
+// so rolling up periods whose statuses were partly purged since, as after a restart, leaves them untouched.
+func (db *Db) RollUpStatuses(since int64) error {
+	return db.orm.Transaction(func(tx *gorm.DB) error {
+		hourly, err := rollUp(tx, since, time.Hour)
+		if err != nil {
+			return err
+		}
+		hourlyUptimes := make([]models.HourlyUptime, len(hourly))
+		for i, rollup := range hourly {
+			hourlyUptimes[i] = models.HourlyUptime{UptimeRollup: rollup}
+		}
+		if err := upsertRollups(tx, &hourlyUptimes, len(hourlyUptimes)); err != nil {
+			return err
+		}
+
+		daily, err := rollUp(tx, since, day)
+		if err != nil {
+			return err
+		}
+		dailyUptimes := make([]models.DailyUptime, len(daily))
+		for i, rollup := range daily {
+			dailyUptimes[i] = models.DailyUptime{UptimeRollup: rollup}
+		}
+		return upsertRollups(tx, &dailyUptimes, len(dailyUptimes))
+	})
+}
+
+// rollUp aggregates the statuses reported since the given timestamp per node, ip version and period
+func rollUp(tx *gorm.DB, since int64, period time.Duration) ([]models.UptimeRollup, error) {
+	periodStart := fmt.Sprintf("timestamp / %d * %d", int64(period), int64(period))
+	var rollups []models.UptimeRollup
+	err := tx.Model(&models.PersistedMixStatus{}).
+		Select("pub_key, ip_version, "+periodStart+" AS start, COUNT(*) AS reports, SUM(CASE WHEN up THEN 1 ELSE 0 END) AS up").
+		Where("timestamp >= ?", since).
+		Group("pub_key, ip_version, " + periodStart).
+		Scan(&rollups).Error
+	return rollups, err
+}
+
+func upsertRollups(tx *gorm.DB, rollups interface{}, count int) error {
+	if count == 0 {
+		return nil
+	}
+	return tx.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "pub_key"}, {Name: "ip_version"}, {Name: "start"}},
+		DoUpdates: clause.AssignmentColumns([]string{"reports", "up"}),
+		Where: clause.Where{Exprs: []clause.Expression{clause.Expr{
+			SQL: "? > ?",
+			Vars: []interface{}{
+				clause.Column{Table: "excluded", Name: "reports"},
+				clause.Column{Table: clause.CurrentTable, Name: "reports"},
+			},
+		}}},
+	}).CreateInBatches(rollups, rollupBatchSize).Error
+}
+
+// RemoveOldRollups removes the hourly and daily uptimes of periods that started before the provided timestamps
+func (db *Db) RemoveOldRollups(hourlyBefore int64, dailyBefore int64) {
+	if err := db.orm.Where("start < ?", hourlyBefore).Delete(&models.HourlyUptime{}).Error; err != nil {
+		fmt.Printf("failed to remove old hourly uptimes from the database - %v\n", err)
+	}
+	if err := db.orm.Where("start < ?", dailyBefore).Delete(&models.DailyUptime{}).Error; err != nil {
+		fmt.Printf("failed to remove old daily uptimes from the database - %v\n", err)
+	}
+}
+
+// ListHourlyUptimes lists the hourly uptimes of a node for either IPv4 or IPv6 of the hours starting within the specified range
+func (db *Db) ListHourlyUptimes(pubkey string, ipVersion string, start int64, end int64) []models.UptimeRollup {
+	return db.listRollups(&models.HourlyUptime{}, pubkey, ipVersion, start, end)
+}
+
+// ListDailyUptimes lists the daily uptimes of a node for either IPv4 or IPv6 of the days starting within the specified range
+func (db *Db) ListDailyUptimes(pubkey string, ipVersion string, start int64, end int64) []models.UptimeRollup {
+	return db.listRollups(&models.DailyUptime{}, pubkey, ipVersion, start, end)
+}
+
+func (db *Db) listRollups(model interface{}, pubkey string, ipVersion string, start int64, end int64) []models.UptimeRollup {
+	var rollups []models.UptimeRollup
+	if err := db.orm.Model(model).Where("pub_key = ?", pubkey).Where("ip_version = ?", ipVersion).Where("start >= ?", start).Where("start <= ?", end).Order("start").Scan(&rollups).Error; err != nil {
+		return make([]models.UptimeRollup, 0)
+	}
+	return rollups
+}
+
+// SumDailyUptimes adds up the daily uptimes of the days starting since the provided timestamp, per node and
+// ip version. Without pubkeys, it does so for every node.
+func (db *Db) SumDailyUptimes(since int64, pubkeys []string) []models.UptimeRollup {
+	query := db.orm.Model(&models.DailyUptime{}).
+		Select("pub_key, ip_version, SUM(reports) AS reports, SUM(up) AS up").
+		Where("start >= ?", since)
+	if len(pubkeys) > 0 {
+		query = query.Where("pub_key IN ?", pubkeys)
+	}
+	var rollups []models.UptimeRollup
+	if err := query.Group("pub_key, ip_version").Scan(&rollups).Error; err != nil {
+		return make([]models.UptimeRollup, 0)
+	}
+	for i := range rollups {
+		rollups[i].Start = since
+	}
+	return rollups
+}
+
+// rollUpAndPurgeStatuses rolls up the statuses reported since the previous run, or since the oldest one still
+// kept on the first run, then purges the statuses and rollups past their retention. Statuses are only purged
+// once they're rolled up.
+func (service *Service) rollUpAndPurgeStatuses() error {
+	now := timemock.Now()
+	since := service.rolledUpUntil
+	if since == 0 {
+		since = now.Add(-service.cfg.StatusesRetention).UnixNano()
+	}
+	// periods are recomputed as a whole, statuses reported since the previous run may be part of a started day
+	if err := service.db.RollUpStatuses(alignToBucket(since, day)); err != nil {
+		return fmt.Errorf("failed to roll up statuses: %v", err)
+	}
+	service.rolledUpUntil = now.UnixNano()
+
+	service.db.RemoveOldStatuses(now.Add(-service.cfg.StatusesRetention).UnixNano())
+	service.db.RemoveOldRollups(
+		now.Add(-service.cfg.HourlyUptimesRetention).UnixNano(),
+		now.Add(-service.cfg.DailyUptimesRetention).UnixNano(),
+	)
+	return nil
+}
+
+// longTermUptimes sets the 30 and 90 days uptimes of the reports, summing up the daily uptimes of the given
+// nodes, or of every node without pubkeys
+func (service *Service) longTermUptimes(reports []models.MixStatusReport, pubkeys []string) {
+	if len(reports) == 0 {
+		return
+	}
+
+	now := timemock.Now()
+	last30Days := service.sumDailyUptimes(now.Add(-30*day), pubkeys)
+	last90Days := service.sumDailyUptimes(now.Add(-90*day), pubkeys)
+	for i := range reports {
+		pubkey := reports[i].PubKey
+		reports[i].Last30DaysIPV4 = service.rollupUptime(last30Days[pubkey+"/4"])
+		reports[i].Last90DaysIPV4 = service.rollupUptime(last90Days[pubkey+"/4"])
+		reports[i].Last30DaysIPV6 = service.rollupUptime(last30Days[pubkey+"/6"])
+		reports[i].Last90DaysIPV6 = service.rollupUptime(last90Days[pubkey+"/6"])
+	}
+}
+
+// unknownLongTermUptimes marks the 30 and 90 days uptimes of a report as unknown. They aren't saved with the
+// report, so only the reports longTermUptimes went through know them.
+func unknownLongTermUptimes(report *models.MixStatusReport) {
+	report.Last30DaysIPV4 = UnknownUptime
+	report.Last90DaysIPV4 = UnknownUptime
+	report.Last30DaysIPV6 = UnknownUptime
+	report.Last90DaysIPV6 = UnknownUptime
+}
+
+// sumDailyUptimes adds up the daily uptimes since the start of the day of since, keyed by node and ip version
+func (service *Service) sumDailyUptimes(since time.Time, pubkeys []string) map[string]models.UptimeRollup {
+	uptimes := make(map[string]models.UptimeRollup)
+	for _, rollup := range service.db.SumDailyUptimes(alignToBucket(since.UnixNano(), day), pubkeys) {
+		uptimes[rollup.PubKey+"/"+rollup.IPVersion] = rollup
+	}
+	return uptimes
+}
+
+func (service *Service) rollupUptime(rollup models.UptimeRollup) int {
+	return service.uptimePercent(rollup.Up, rollup.Reports)
+}
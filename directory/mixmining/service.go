@@ -15,17 +15,22 @@
 package mixmining
 
 import (
+	stdcontext "context"
 	"errors"
 	"fmt"
 	"github.com/cosmos/cosmos-sdk/client/context"
 	"github.com/cosmos/cosmos-sdk/client/rpc"
+	"log"
 	"net"
 	"net/http"
+	"sync"
 	"sync/atomic"
 	"time"
 
 	"github.com/BorisBorshevsky/timemock"
+	sdk "github.com/cosmos/cosmos-sdk/types"
 	"github.com/nymtech/nym/validator/nym/directory/models"
+	"github.com/nymtech/nym/validator/nym/x/nym/types"
 )
 
 // so if you can mix ipv4 but not ipv6, your reputation will go down but not as fast as if you didn't mix at all
@@ -34,18 +39,106 @@ const ReportFailureReputationDecrease = int64(-2)
 const ReputationThreshold = int64(100)
 const TopologyCacheTTL = time.Second * 30
 
-const Last5MinutesReports = 5
-const LastHourReports = 50
-const LastDayReports = 1000
+// the windows of the uptimes in the status reports, ending when they're computed
+const Last5Minutes = time.Minute * 5
+const LastHour = time.Hour
+const LastDay = time.Hour * 24
 
 const TopologyRefreshing = 1
 const TopologyNotRefreshing = 0
 
+// Layers is the number of mixnet layers the directory assigns mixnodes to
+const Layers = uint(3)
+const LayerRebalanceInterval = time.Hour
+
+// ChainBridgeQueueSize is the number of registration changes that can wait to be mirrored onto the chain
+const ChainBridgeQueueSize = 1000
+
+// ChainBridgeReconcileInterval is how often the chain is reconciled with the directory, catching up on the changes
+// that were dropped or failed to be mirrored
+const ChainBridgeReconcileInterval = time.Hour
+
+const ValidatorsRefreshInterval = time.Second * 30
+const ReportsRefreshInterval = time.Minute * 10
+const StatusesPurgeInterval = time.Hour
+const StatusesRetention = time.Hour * 24 * 7
+
+// ServiceConfig tunes the service, the constants above are its defaults
+type ServiceConfig struct {
+	// ReputationThreshold is the reputation a node needs to be part of the active topology
+	ReputationThreshold int64         `mapstructure:"reputation_threshold"`
+	TopologyCacheTTL    time.Duration `mapstructure:"topology_cache_ttl"`
+	Layers              uint          `mapstructure:"layers"`
+
+	ValidatorsRefreshInterval time.Duration `mapstructure:"validators_refresh_interval"`
+	// ReportsRefreshInterval is how often every uptime of the mixes reports gets recalculated
+	ReportsRefreshInterval time.Duration `mapstructure:"reports_refresh_interval"`
+	StatusesPurgeInterval  time.Duration `mapstructure:"statuses_purge_interval"`
+	// StatusesRetention is how long mix statuses are kept before being purged, they're rolled up into hourly
+	// and daily uptimes first, which are kept for their own retention
+	StatusesRetention      time.Duration `mapstructure:"statuses_retention"`
+	HourlyUptimesRetention time.Duration `mapstructure:"hourly_uptimes_retention"`
+	DailyUptimesRetention  time.Duration `mapstructure:"daily_uptimes_retention"`
+	LayerRebalanceInterval time.Duration `mapstructure:"layer_rebalance_interval"`
+
+	ChainBridgeQueueSize         int           `mapstructure:"chain_bridge_queue_size"`
+	ChainBridgeReconcileInterval time.Duration `mapstructure:"chain_bridge_reconcile_interval"`
+
+	Reputation ReputationConfig `mapstructure:"reputation"`
+}
+
+// DefaultServiceConfig returns the configuration the service was designed around
+func DefaultServiceConfig() ServiceConfig {
+	return ServiceConfig{
+		ReputationThreshold:          ReputationThreshold,
+		TopologyCacheTTL:             TopologyCacheTTL,
+		Layers:                       Layers,
+		ValidatorsRefreshInterval:    ValidatorsRefreshInterval,
+		ReportsRefreshInterval:       ReportsRefreshInterval,
+		StatusesPurgeInterval:        StatusesPurgeInterval,
+		StatusesRetention:            StatusesRetention,
+		HourlyUptimesRetention:       HourlyUptimesRetention,
+		DailyUptimesRetention:        DailyUptimesRetention,
+		LayerRebalanceInterval:       LayerRebalanceInterval,
+		ChainBridgeQueueSize:         ChainBridgeQueueSize,
+		ChainBridgeReconcileInterval: ChainBridgeReconcileInterval,
+		Reputation:                   DefaultReputationConfig(),
+	}
+}
+
+// Validate checks the configuration is usable
+func (cfg ServiceConfig) Validate() error {
+	if cfg.Layers == 0 {
+		return errors.New("the mixnet needs at least one layer")
+	}
+	if cfg.ChainBridgeQueueSize <= 0 {
+		return fmt.Errorf("chain bridge queue size must be positive, got %d", cfg.ChainBridgeQueueSize)
+	}
+	for name, duration := range map[string]time.Duration{
+		"topology cache ttl":          cfg.TopologyCacheTTL,
+		"validators refresh interval": cfg.ValidatorsRefreshInterval,
+		"reports refresh interval":    cfg.ReportsRefreshInterval,
+		"statuses purge interval":     cfg.StatusesPurgeInterval,
+		"statuses retention":          cfg.StatusesRetention,
+		"hourly uptimes retention":    cfg.HourlyUptimesRetention,
+		"daily uptimes retention":     cfg.DailyUptimesRetention,
+		"layer rebalance interval":    cfg.LayerRebalanceInterval,
+		"chain reconcile interval":    cfg.ChainBridgeReconcileInterval,
+	} {
+		if duration <= 0 {
+			return fmt.Errorf("%s must be positive, got %v", name, duration)
+		}
+	}
+	return cfg.Reputation.Validate()
+}
+
 // Service struct
 type Service struct {
 	db         IDb
 	cliCtx     context.CLIContext
+	cfg        ServiceConfig
 	validators *rpc.ResultValidatorsOutput
+	reputation ReputationPolicy
 
 	topology                 models.Topology
 	topologyRefreshed        time.Time
@@ -57,23 +150,48 @@ type Service struct {
 	topologyRefreshing        uint32
 	activeTopologyRefreshing  uint32
 	removedTopologyRefreshing uint32
+
+	// bridge, if set, mirrors registrations onto the chain. Changes are queued so they're applied in order
+	// without blocking the http handlers on block inclusion.
+	bridge      IChainBridge
+	bridgeQueue chan bridgeTask
+
+	// workers run in the background once the service is started, the bridge worker only if the bridge is enabled
+	workers      []*worker
+	bridgeWorker *worker
+
+	// rolledUpUntil is when statuses were last rolled up, 0 until they first are
+	rolledUpUntil int64
+
+	lifecycleLock sync.Mutex
+	started       bool
+	cancel        stdcontext.CancelFunc
+	running       sync.WaitGroup
+}
+
+// bridgeTask is a registration change waiting to be mirrored onto the chain
+type bridgeTask struct {
+	description string
+	run         func() error
 }
 
 // IService defines the REST service interface for mixmining.
 type IService interface {
-	CreateMixStatus(mixStatus models.MixStatus) models.PersistedMixStatus
+	CreateMixStatus(mixStatus models.MixStatus, monitor string) models.PersistedMixStatus
 	ListMixStatus(pubkey string) []models.PersistedMixStatus
 	SaveStatusReport(status models.PersistedMixStatus) models.MixStatusReport
 	GetStatusReport(pubkey string) models.MixStatusReport
+	GetUptimeSeries(pubkey string, ipVersion string, from int64, to int64, bucket string) models.UptimeSeries
 
 	SaveBatchStatusReport(status []models.PersistedMixStatus) models.BatchMixStatusReport
-	BatchCreateMixStatus(batchMixStatus models.BatchMixStatus) []models.PersistedMixStatus
+	BatchCreateMixStatus(batchMixStatus models.BatchMixStatus, monitor string) []models.PersistedMixStatus
 	BatchGetMixStatusReport() models.BatchMixStatusReport
 
-	RegisterMix(info models.MixRegistrationInfo)
+	RegisterMix(info models.MixRegistrationInfo) uint
 	RegisterGateway(info models.GatewayRegistrationInfo)
 	UnregisterNode(id string, remoteIp string) (int, error)
 	SetReputation(id string, newRep int64) bool
+	ReputationHistory(pubkey string) []models.ReputationChange
 	GetTopology() models.Topology
 	GetActiveTopology() models.Topology
 
@@ -81,73 +199,118 @@ type IService interface {
 	MixCount() int
 	GatewayCount() int
 	GetRemovedTopology() models.Topology
-	StartupPurge()
+	StartupPurge(systemVersion string)
+	WorkerStatuses() []models.WorkerStatus
+
+	ProposeLayerRebalance() types.LayerRebalance
+	RebalanceLayers() types.LayerRebalance
 }
 
-// NewService constructor
-func NewService(db IDb, cliCtx context.CLIContext, isTest bool) *Service {
+// NewService constructor, the background workers only run once the service is started
+func NewService(db IDb, cliCtx context.CLIContext, cfg ServiceConfig) *Service {
 	emptyValidators := emptyValidators()
 	service := &Service{
 		db:                       db,
 		cliCtx:                   cliCtx,
+		cfg:                      cfg,
 		validators:               &emptyValidators,
+		reputation:               cfg.Reputation.NewPolicy(),
 		topology:                 db.Topology(),
 		topologyRefreshed:        timemock.Now(),
-		activeTopology:           db.ActiveTopology(ReputationThreshold),
+		activeTopology:           db.ActiveTopology(cfg.ReputationThreshold),
 		activeTopologyRefreshed:  timemock.Now(),
 		removedTopology:          db.RemovedTopology(),
 		removedTopologyRefreshed: timemock.Now(),
 	}
-
-	if !isTest {
-		// start validator updater in background (every 30s)
-		go updateValidators(service)
-		// same with 'last day' report updater (every 10min)
-		go lastDayReportsUpdater(service)
-		// and old statuses remover (every 1h)
-		go oldStatusesPurger(service)
+	service.workers = []*worker{
+		newWorker("validators", cfg.ValidatorsRefreshInterval, true, service.updateValidators),
+		newWorker("reports", cfg.ReportsRefreshInterval, false, service.refreshReports),
+		newWorker("statuses_purge", cfg.StatusesPurgeInterval, true, service.rollUpAndPurgeStatuses),
+		newWorker("layer_rebalance", cfg.LayerRebalanceInterval, false, service.rebalanceLayersPeriodically),
+	}
+	if cfg.Reputation.DecayHalfLife > 0 {
+		service.workers = append(service.workers,
+			newWorker("reputation_decay", cfg.Reputation.DecayInterval, false, service.decayReputations))
 	}
 
 	return service
 }
 
-func updateValidators(service *Service) {
-	ticker := time.NewTicker(time.Second * 30)
+// EnableChainBridge mirrors all subsequent registrations and unregistrations onto the chain, the changes are
+// applied once the service is started. The chain is first reconciled with the directory topology so the
+// two converge, and then again every ChainBridgeReconcileInterval.
+// The chain owns the layers of the mixnodes it knows, so the directory stops rebalancing them periodically.
+func (service *Service) EnableChainBridge(bridge IChainBridge) {
+	service.bridge = bridge
+	service.bridgeQueue = make(chan bridgeTask, service.cfg.ChainBridgeQueueSize)
+	service.bridgeWorker = newWorker("chain_bridge", 0, false, nil)
 
-	for {
-		validators, err := rpc.GetValidators(service.cliCtx, nil, 1, 100)
-		if err != nil {
-			fmt.Printf("failed to grab validators - %v\n", err)
-		} else {
-			*service.validators = validators
+	workers := make([]*worker, 0, len(service.workers)+1)
+	for _, w := range service.workers {
+		if w.name != "layer_rebalance" {
+			workers = append(workers, w)
 		}
-		<-ticker.C
 	}
+	service.workers = append(workers,
+		newWorker("chain_reconciliation", service.cfg.ChainBridgeReconcileInterval, false, service.queueReconciliation))
+
+	service.mirror("startup reconciliation", service.reconcileChain)
 }
 
-func lastDayReportsUpdater(service *Service) {
-	ticker := time.NewTicker(time.Minute * 10)
+// mirror queues the change to be applied onto the chain, if the chain bridge is enabled, returning whether it was.
+// Changes that don't fit in the queue are dropped, they will be picked up by the next reconciliation.
+func (service *Service) mirror(description string, run func() error) bool {
+	if service.bridge == nil {
+		return false
+	}
+	select {
+	case service.bridgeQueue <- bridgeTask{description: description, run: run}:
+		return true
+	default:
+		log.Printf("chain bridge queue is full, dropping %s until the next reconciliation", description)
+		return false
+	}
+}
 
-	for {
-		<-ticker.C
-		batchReport := service.updateLastDayReports()
-		service.removeBrokenNodes(&batchReport)
+// queueReconciliation queues a reconciliation behind the changes already queued, so it doesn't undo them
+func (service *Service) queueReconciliation() error {
+	if !service.mirror("periodic reconciliation", service.reconcileChain) {
+		return errors.New("chain bridge queue is full, postponing the reconciliation")
 	}
+	return nil
+}
 
+// reconcileChain makes the chain match the directory topology as it is when the reconciliation gets applied
+func (service *Service) reconcileChain() error {
+	return service.bridge.Reconcile(service.db.Topology())
 }
 
-func oldStatusesPurger(service *Service) {
-	ticker := time.NewTicker(time.Hour * 1)
+func (service *Service) updateValidators() error {
+	validators, err := rpc.GetValidators(service.cliCtx, nil, 1, 100)
+	if err != nil {
+		return fmt.Errorf("failed to grab validators: %v", err)
+	}
+	*service.validators = validators
+	return nil
+}
 
-	for {
-		now := timemock.Now()
-		lastWeek := now.Add(- (time.Hour * 24 * 7)).UnixNano()
-		service.db.RemoveOldStatuses(lastWeek)
-		<-ticker.C
+func (service *Service) refreshReports() error {
+	batchReport := service.updateReports()
+	service.removeBrokenNodes(&batchReport)
+	return nil
+}
+
+func (service *Service) rebalanceLayersPeriodically() error {
+	rebalance := service.RebalanceLayers()
+	if len(rebalance.Moves) > 0 {
+		fmt.Printf("rebalanced mixnet layers, moved %d mixnodes\n", len(rebalance.Moves))
 	}
+	return nil
 }
 
-func (service *Service) updateLastDayReports() models.BatchMixStatusReport {
+// updateReports recomputes every uptime of the mixes reports, so that those of mixes which stopped being tested
+// turn unknown rather than keeping the figures of their last status
+func (service *Service) updateReports() models.BatchMixStatusReport {
 	topology := service.GetTopology()
 
 	// right there are no reports for gateways so ignore them.
@@ -156,18 +319,14 @@ func (service *Service) updateLastDayReports() models.BatchMixStatusReport {
 		reportKeys = append(reportKeys, mix.IdentityKey)
 	}
 
-	dayAgo := timemock.Now().Add(time.Duration(-time.Hour * 24)).UnixNano()
 	batchReport := service.db.BatchLoadReports(reportKeys)
 	for idx := range batchReport.Report {
 		report := &batchReport.Report[idx]
-		lastDayUptime := service.CalculateUptimeSince(report.PubKey, "4", dayAgo, LastDayReports)
-		if lastDayUptime == -1 {
-			// there were no reports to calculate uptime with
-			continue
-		}
-
-		report.LastDayIPV4 = lastDayUptime
-		report.LastDayIPV6 = service.CalculateUptimeSince(report.PubKey, "6", dayAgo, LastDayReports)
+		unknownLongTermUptimes(report)
+		uptimes := service.calculateUptimes(report.PubKey, "4", Last5Minutes, LastHour, LastDay)
+		report.Last5MinutesIPV4, report.LastHourIPV4, report.LastDayIPV4 = uptimes[0], uptimes[1], uptimes[2]
+		uptimes = service.calculateUptimes(report.PubKey, "6", Last5Minutes, LastHour, LastDay)
+		report.Last5MinutesIPV6, report.LastHourIPV6, report.LastDayIPV6 = uptimes[0], uptimes[1], uptimes[2]
 	}
 
 	service.db.SaveBatchMixStatusReport(batchReport)
@@ -182,11 +341,12 @@ func (service *Service) removeBrokenNodes(batchReport *models.BatchMixStatusRepo
 	}
 }
 
-// CreateMixStatus adds a new PersistedMixStatus in the orm.
-func (service *Service) CreateMixStatus(mixStatus models.MixStatus) models.PersistedMixStatus {
+// CreateMixStatus adds a new PersistedMixStatus, reported by the given monitor, in the orm.
+func (service *Service) CreateMixStatus(mixStatus models.MixStatus, monitor string) models.PersistedMixStatus {
 	persistedMixStatus := models.PersistedMixStatus{
 		MixStatus: mixStatus,
 		Timestamp: timemock.Now().UnixNano(),
+		Monitor:   monitor,
 	}
 	service.db.AddMixStatus(persistedMixStatus)
 
@@ -200,16 +360,23 @@ func (service *Service) ListMixStatus(pubkey string) []models.PersistedMixStatus
 
 // GetStatusReport gets a single MixStatusReport by node public key
 func (service *Service) GetStatusReport(pubkey string) models.MixStatusReport {
-	return service.db.LoadReport(pubkey)
+	report := service.db.LoadReport(pubkey)
+	if report.PubKey == "" {
+		return report
+	}
+	reports := []models.MixStatusReport{report}
+	service.longTermUptimes(reports, []string{pubkey})
+	return reports[0]
 }
 
-// BatchCreateMixStatus batch adds new multiple PersistedMixStatus in the orm.
-func (service *Service) BatchCreateMixStatus(batchMixStatus models.BatchMixStatus) []models.PersistedMixStatus {
+// BatchCreateMixStatus batch adds new multiple PersistedMixStatus, reported by the given monitor, in the orm.
+func (service *Service) BatchCreateMixStatus(batchMixStatus models.BatchMixStatus, monitor string) []models.PersistedMixStatus {
 	statusList := make([]models.PersistedMixStatus, len(batchMixStatus.Status))
 	for i, mixStatus := range batchMixStatus.Status {
 		persistedMixStatus := models.PersistedMixStatus{
 			MixStatus: mixStatus,
 			Timestamp: timemock.Now().UnixNano(),
+			Monitor:   monitor,
 		}
 		statusList[i] = persistedMixStatus
 	}
@@ -220,7 +387,11 @@ func (service *Service) BatchCreateMixStatus(batchMixStatus models.BatchMixStatu
 
 // BatchGetMixStatusReport gets BatchMixStatusReport which contain multiple MixStatusReport.
 func (service *Service) BatchGetMixStatusReport() models.BatchMixStatusReport {
-	return service.db.LoadNonStaleReports()
+	// the nodes about to be removed for their low uptime aren't worth reporting
+	batchReport := service.db.LoadNonStaleReports(service.cfg.Reputation.RemovalUptime)
+	// the reports are those of every node, which longTermUptimes is told with no pubkeys rather than all of them
+	service.longTermUptimes(batchReport.Report, nil)
+	return batchReport
 }
 
 // SaveBatchStatusReport builds and saves a status report for multiple mixnodes simultaneously.
@@ -242,44 +413,50 @@ func (service *Service) SaveBatchStatusReport(status []models.PersistedMixStatus
 		reportMap[report.PubKey] = i
 	}
 
+	// the reputation of a node with several statuses changes with each of them in turn
+	reputations := service.db.BatchLoadReputations(pubkeys)
 	for _, mixStatus := range status {
 		if reportIdx, ok := reportMap[mixStatus.PubKey]; ok {
 			service.updateReportUpToLastHour(&batchReport.Report[reportIdx], &mixStatus)
-			if *mixStatus.Up {
-				reputationChangeMap[mixStatus.PubKey] += ReportSuccessReputationIncrease
-			} else {
-				reputationChangeMap[mixStatus.PubKey] += ReportFailureReputationDecrease
-			}
 		} else {
 			var freshReport models.MixStatusReport
 			service.updateReportUpToLastHour(&freshReport, &mixStatus)
 			batchReport.Report = append(batchReport.Report, freshReport)
 			reportMap[freshReport.PubKey] = len(batchReport.Report) - 1
-			if *mixStatus.Up {
-				reputationChangeMap[mixStatus.PubKey] = ReportSuccessReputationIncrease
-			} else {
-				reputationChangeMap[mixStatus.PubKey] = ReportFailureReputationDecrease
-			}
 		}
+		reputation := reputations[mixStatus.PubKey] + reputationChangeMap[mixStatus.PubKey]
+		reputationChangeMap[mixStatus.PubKey] += service.reputation.Change(reputation, mixStatus)
 	}
 
 	service.db.SaveBatchMixStatusReport(batchReport)
-	service.db.BatchUpdateReputation(reputationChangeMap)
+	service.db.BatchUpdateReputation(reputationChangeMap, models.ReputationChangeMonitorReport)
 
 	return batchReport
 }
 
 func (service *Service) updateReportUpToLastHour(report *models.MixStatusReport, status *models.PersistedMixStatus) {
-	report.PubKey = status.PubKey // crude, we do this in case it's a fresh struct returned from the db
+	if report.PubKey == "" {
+		// a fresh struct returned from the db, its uptimes are unknown until they get computed
+		*report = models.MixStatusReport{
+			PubKey:           status.PubKey,
+			Last5MinutesIPV4: UnknownUptime,
+			LastHourIPV4:     UnknownUptime,
+			LastDayIPV4:      UnknownUptime,
+			Last5MinutesIPV6: UnknownUptime,
+			LastHourIPV6:     UnknownUptime,
+			LastDayIPV6:      UnknownUptime,
+		}
+	}
+	unknownLongTermUptimes(report)
 
 	if status.IPVersion == "4" {
 		report.MostRecentIPV4 = *status.Up
-		report.Last5MinutesIPV4 = service.CalculateUptime(status.PubKey, "4", Last5MinutesReports)
-		report.LastHourIPV4 = service.CalculateUptime(status.PubKey, "4", LastHourReports)
+		uptimes := service.calculateUptimes(status.PubKey, "4", Last5Minutes, LastHour)
+		report.Last5MinutesIPV4, report.LastHourIPV4 = uptimes[0], uptimes[1]
 	} else if status.IPVersion == "6" {
 		report.MostRecentIPV6 = *status.Up
-		report.Last5MinutesIPV6 = service.CalculateUptime(status.PubKey, "6", Last5MinutesReports)
-		report.LastHourIPV6 = service.CalculateUptime(status.PubKey, "6", LastHourReports)
+		uptimes := service.calculateUptimes(status.PubKey, "6", Last5Minutes, LastHour)
+		report.Last5MinutesIPV6, report.LastHourIPV6 = uptimes[0], uptimes[1]
 	}
 }
 
@@ -292,96 +469,72 @@ func (service *Service) SaveStatusReport(status models.PersistedMixStatus) model
 	service.updateReportUpToLastHour(&report, &status)
 	service.db.SaveMixStatusReport(report)
 
-	if *status.Up {
-		service.db.UpdateReputation(status.PubKey, ReportSuccessReputationIncrease)
-		// if the status was up, there's no way the quality has decreased
-	} else {
-		service.db.UpdateReputation(status.PubKey, ReportFailureReputationDecrease)
-		if service.shouldGetRemoved(&report) {
-			service.db.MoveToRemovedSet(report.PubKey)
-		}
+	reputation := service.db.BatchLoadReputations([]string{status.PubKey})[status.PubKey]
+	service.db.UpdateReputation(status.PubKey, service.reputation.Change(reputation, status), models.ReputationChangeMonitorReport)
+	// if the status was up, there's no way the quality has decreased
+	if !*status.Up && service.reputation.ShouldRemove(report) {
+		service.db.MoveToRemovedSet(report.PubKey)
 	}
 
 	return report
 }
 
-// shouldGetRemoved is called upon receiving mix status for this particular node. It determines whether the node is still
-// eligible to be part of the main topology or should moved into 'removed set'
-func (service *Service) shouldGetRemoved(report *models.MixStatusReport) bool {
-	// check if last 24h ipv4 uptime is > 50%
-	if report.LastDayIPV4 < 50 {
-		return true
-	}
-
-	// if it ever mixed any ipv6 packet, do the same check for ipv6 uptime
-	if report.LastDayIPV6 > 0 && report.LastDayIPV6 < 50 {
-		return true
-	}
-
-	// TODO: does it make sense to also check reputation here? But if we do it, then each new node would get
-	// removed immediately before they even get a chance to build it up
-
-	return false
-}
-
 // batchShouldGetRemoved is called upon receiving batch mix status for the set of those particular nodes.
 // It determines whether the nodes are still eligible to be part of the main topology or should moved into 'removed set'
 func (service *Service) batchShouldGetRemoved(batchReport *models.BatchMixStatusReport) []string {
 	broken := make([]string, 0)
 
 	for _, report := range batchReport.Report {
-		// check if last 24h ipv4 uptime is > 50%
-		if report.LastDayIPV4 < 50 {
+		if service.reputation.ShouldRemove(report) {
 			broken = append(broken, report.PubKey)
-			continue
 		}
-
-		// if it ever mixed any ipv6 packet, do the same check for ipv6 uptime
-		if report.LastDayIPV6 > 0 && report.LastDayIPV6 < 50 {
-			broken = append(broken, report.PubKey)
-			continue
-		}
-
-		// TODO: does it make sense to also check reputation here? But if we do it, then each new node would get
-		// removed immediately before they even get a chance to build it up
 	}
 
 	return broken
 }
 
-// CalculateUptime calculates percentage uptime for a given node, protocol since a specific time
-func (service *Service) CalculateUptime(pubkey string, ipVersion string, numReports int) int {
-	statuses := service.db.GetNMostRecentMixStatuses(pubkey, ipVersion, numReports)
-	numStatuses := len(statuses)
-	if numStatuses == 0 {
-		// this can only happen to the goroutine calculating uptime for last 1000 reports
-		return -1
-	}
-	up := 0
-	for _, status := range statuses {
-		if *status.Up {
-			up = up + 1
-		}
-	}
-
-	return service.calculatePercent(up, numStatuses)
+// CalculateUptime calculates the percentage uptime of a node, for either IPv4 or IPv6, over the window ending now.
+// It's UnknownUptime if the node wasn't tested during the window.
+func (service *Service) CalculateUptime(pubkey string, ipVersion string, window time.Duration) int {
+	return service.calculateUptimes(pubkey, ipVersion, window)[0]
 }
 
-func (service *Service) CalculateUptimeSince(pubkey string, ipVersion string, since int64, numReports int) int {
-	statuses := service.db.ListMixStatusSinceWithLimit(pubkey, ipVersion, since, numReports)
-	numStatuses := len(statuses)
-	if numStatuses == 0 {
-		// this can only happen to the goroutine calculating uptime for last 1000 reports
-		return -1
+// calculateUptimes calculates the uptimes of a node over each of the windows ending now, out of the statuses of
+// the longest one
+func (service *Service) calculateUptimes(pubkey string, ipVersion string, windows ...time.Duration) []int {
+	longest := windows[0]
+	for _, window := range windows {
+		if window > longest {
+			longest = window
+		}
 	}
-	up := 0
-	for _, status := range statuses {
-		if *status.Up {
-			up = up + 1
+	now := timemock.Now()
+	statuses := service.db.ListMixStatusDateRange(pubkey, ipVersion, now.Add(-longest).UnixNano(), now.UnixNano())
+
+	uptimes := make([]int, len(windows))
+	for i, window := range windows {
+		since := now.Add(-window).UnixNano()
+		reports, up := 0, 0
+		for _, status := range statuses {
+			if status.Timestamp < since {
+				continue
+			}
+			reports++
+			if *status.Up {
+				up++
+			}
 		}
+		uptimes[i] = service.uptimePercent(up, reports)
 	}
+	return uptimes
+}
 
-	return service.calculatePercent(up, numStatuses)
+// uptimePercent is the percentage of up statuses out of the reported ones, or UnknownUptime without any
+func (service *Service) uptimePercent(up int, reports int) int {
+	if reports == 0 {
+		return UnknownUptime
+	}
+	return service.calculatePercent(up, reports)
 }
 
 func (service *Service) calculatePercent(num int, outOf int) int {
@@ -392,12 +545,60 @@ func (service *Service) CheckForDuplicateIP(host string) bool {
 	return service.db.IpExists(host)
 }
 
-func (service *Service) RegisterMix(info models.MixRegistrationInfo) {
+// RegisterMix registers the mix in the layer assigned to it by the directory, which it returns
+func (service *Service) RegisterMix(info models.MixRegistrationInfo) uint {
+	info.Layer = service.assignLayer(info.IdentityKey)
 	registeredMix := models.RegisteredMix{
 		MixRegistrationInfo: info,
 	}
 
 	service.db.RegisterMix(registeredMix)
+	service.mirror("registration of mix "+info.IdentityKey, func() error {
+		return service.bridge.RegisterMix(info)
+	})
+	return info.Layer
+}
+
+// assignLayer returns the layer a registering mix should join. A mix registering again keeps its layer,
+// moving mixes around being left to the rebalancing.
+func (service *Service) assignLayer(identityKey string) uint {
+	var others []models.RegisteredMix
+	for _, mix := range service.db.Topology().MixNodes {
+		if mix.IdentityKey != identityKey {
+			others = append(others, mix)
+		} else if mix.Layer >= 1 && mix.Layer <= service.cfg.Layers {
+			return mix.Layer
+		}
+	}
+	return uint(types.LeastLoadedLayer(layerNodes(others), uint32(service.cfg.Layers)))
+}
+
+// ProposeLayerRebalance returns the moves that would balance the mixes and their reputation across the layers,
+// without applying them
+func (service *Service) ProposeLayerRebalance() types.LayerRebalance {
+	return types.NewLayerRebalance(layerNodes(service.db.Topology().MixNodes), uint32(service.cfg.Layers))
+}
+
+// RebalanceLayers moves mixes between layers to balance their number and reputation, returning the rebalance made.
+// The moves aren't mirrored onto the chain, which rebalances the layers it owns itself.
+func (service *Service) RebalanceLayers() types.LayerRebalance {
+	rebalance := service.ProposeLayerRebalance()
+	for _, move := range rebalance.Moves {
+		service.db.SetMixLayer(move.ID, uint(move.To))
+	}
+	return rebalance
+}
+
+func layerNodes(mixes []models.RegisteredMix) []types.LayerNode {
+	nodes := make([]types.LayerNode, len(mixes))
+	for i, mix := range mixes {
+		nodes[i] = types.LayerNode{
+			ID:     mix.IdentityKey,
+			Layer:  uint32(mix.Layer),
+			Weight: sdk.NewInt(mix.Reputation),
+		}
+	}
+	return nodes
 }
 
 func (service *Service) RegisterGateway(info models.GatewayRegistrationInfo) {
@@ -406,6 +607,9 @@ func (service *Service) RegisterGateway(info models.GatewayRegistrationInfo) {
 	}
 
 	service.db.RegisterGateway(registeredGateway)
+	service.mirror("registration of gateway "+info.IdentityKey, func() error {
+		return service.bridge.RegisterGateway(info)
+	})
 }
 
 func (service *Service) UnregisterNode(id string, remoteIp string) (int, error) {
@@ -455,6 +659,9 @@ func (service *Service) UnregisterNode(id string, remoteIp string) (int, error)
 			if !service.db.UnregisterNode(id) {
 				return http.StatusInternalServerError, errors.New("failed to unregister node")
 			}
+			service.mirror("unregistration of node "+id, func() error {
+				return service.bridge.UnregisterNode(id)
+			})
 			return http.StatusOK, nil
 		}
 	}
@@ -462,8 +669,9 @@ func (service *Service) UnregisterNode(id string, remoteIp string) (int, error)
 	return http.StatusForbidden, errors.New("node's mix host does not match the remote address")
 }
 
+// SetReputation overrides the reputation of a node, as an admin
 func (service *Service) SetReputation(id string, newRep int64) bool {
-	return service.db.SetReputation(id, newRep)
+	return service.db.SetReputation(id, newRep, models.ReputationChangeAdminOverride)
 }
 
 func emptyValidators() rpc.ResultValidatorsOutput {
@@ -476,7 +684,7 @@ func emptyValidators() rpc.ResultValidatorsOutput {
 func (service *Service) GetTopology() models.Topology {
 	now := timemock.Now()
 
-	if now.Sub(service.topologyRefreshed) > TopologyCacheTTL {
+	if now.Sub(service.topologyRefreshed) > service.cfg.TopologyCacheTTL {
 		// if topology is not refreshing, start refreshing
 		if atomic.CompareAndSwapUint32(&service.topologyRefreshing, TopologyNotRefreshing, TopologyRefreshing) {
 			// put in defer block to ensure it's going to get called if something crashes
@@ -496,7 +704,7 @@ func (service *Service) GetTopology() models.Topology {
 
 func (service *Service) GetActiveTopology() models.Topology {
 	now := timemock.Now()
-	if now.Sub(service.activeTopologyRefreshed) > TopologyCacheTTL {
+	if now.Sub(service.activeTopologyRefreshed) > service.cfg.TopologyCacheTTL {
 		// if topology is not refreshing, start refreshing
 		if atomic.CompareAndSwapUint32(&service.activeTopologyRefreshing, TopologyNotRefreshing, TopologyRefreshing) {
 			// put in defer block to ensure it's going to get called if something crashes
@@ -504,7 +712,7 @@ func (service *Service) GetActiveTopology() models.Topology {
 				service.activeTopologyRefreshing = TopologyNotRefreshing
 			}()
 
-			newTopology := service.db.ActiveTopology(ReputationThreshold)
+			newTopology := service.db.ActiveTopology(service.cfg.ReputationThreshold)
 			newTopology.Validators = *service.validators
 			service.activeTopology = newTopology
 			service.activeTopologyRefreshed = now
@@ -526,7 +734,7 @@ func (service *Service) GatewayCount() int {
 
 func (service *Service) GetRemovedTopology() models.Topology {
 	now := timemock.Now()
-	if now.Sub(service.removedTopologyRefreshed) > TopologyCacheTTL {
+	if now.Sub(service.removedTopologyRefreshed) > service.cfg.TopologyCacheTTL {
 		// if topology is not refreshing, start refreshing
 		if atomic.CompareAndSwapUint32(&service.removedTopologyRefreshing, TopologyNotRefreshing, TopologyRefreshing) {
 			// put in defer block to ensure it's going to get called if something crashes
@@ -544,19 +752,19 @@ func (service *Service) GetRemovedTopology() models.Topology {
 }
 
 // StartupPurge moves any mixnode from the main topology into 'removed' if it is not running
-// version 0.9.2. The "50%" uptime requirement does not need to be checked here as if it's
+// the system version. The "50%" uptime requirement does not need to be checked here as if it's
 // not fulfilled, the node will be automatically moved to "removed set" on the first
 // run of the network monitor
-func (service *Service) StartupPurge() {
+func (service *Service) StartupPurge(systemVersion string) {
 	nodesToRemove := make([]string, 0)
 	topology := service.db.Topology()
 	for _, mix := range topology.MixNodes {
-		if mix.Version != SystemVersion {
+		if mix.Version != systemVersion {
 			nodesToRemove = append(nodesToRemove, mix.IdentityKey)
 		}
 	}
 	for _, gateway := range topology.Gateways {
-		if gateway.Version != SystemVersion {
+		if gateway.Version != systemVersion {
 			nodesToRemove = append(nodesToRemove, gateway.IdentityKey)
 		}
 	}
@@ -15,13 +15,19 @@
 package mixmining
 
 import (
+	stdcontext "context"
+	"errors"
+	"fmt"
 	"github.com/BorisBorshevsky/timemock"
 	"github.com/cosmos/cosmos-sdk/client/context"
 	"github.com/nymtech/nym/validator/nym/directory/mixmining/fixtures"
 	"github.com/nymtech/nym/validator/nym/directory/mixmining/mocks"
 	"github.com/nymtech/nym/validator/nym/directory/models"
+	"github.com/nymtech/nym/validator/nym/x/nym/types"
 	. "github.com/onsi/ginkgo"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"math"
 	"net/http"
 	"time"
 )
@@ -134,7 +140,7 @@ var _ = Describe("mixmining.Service", func() {
 	var persisted1 models.PersistedMixStatus
 	var persisted2 models.PersistedMixStatus
 
-	var serv Service
+	var serv *Service
 
 	boolfalse := false
 	booltrue := true
@@ -175,17 +181,20 @@ var _ = Describe("mixmining.Service", func() {
 		mockDb.On("Topology").Return(models.Topology{})
 		mockDb.On("ActiveTopology", ReputationThreshold).Return(models.Topology{}).Once()
 		mockDb.On("RemovedTopology").Return(models.Topology{})
-		serv = *NewService(&mockDb, context.NewCLIContext(), true)
+		mockDb.On("BatchLoadReputations", mock.Anything).Return(map[string]int64{})
+		serv = NewService(&mockDb, context.NewCLIContext(), DefaultServiceConfig())
 	})
 
 	Describe("Adding a mix status and creating a new summary report for a node", func() {
 		Context("when no statuses have yet been saved", func() {
 			It("should add a PersistedMixStatus to the db and save the new report", func() {
 
-				mockDb.On("AddMixStatus", persisted1)
+				reported := persisted1
+				reported.Monitor = "monitor"
+				mockDb.On("AddMixStatus", reported)
 
-				serv.CreateMixStatus(status1)
-				mockDb.AssertCalled(GinkgoT(), "AddMixStatus", persisted1)
+				serv.CreateMixStatus(status1, "monitor")
+				mockDb.AssertCalled(GinkgoT(), "AddMixStatus", reported)
 			})
 		})
 	})
@@ -203,33 +212,111 @@ var _ = Describe("mixmining.Service", func() {
 		})
 	})
 
+	Describe("Listing the reputation history", func() {
+		It("should list at most the history limit of changes", func() {
+			changes := []models.ReputationChange{{PubKey: "key1", Delta: 2, Reputation: 2, Reason: models.ReputationChangeMonitorReport}}
+			mockDb.On("ListReputationChanges", "key1", ReputationHistoryLimit).Return(changes)
+
+			assert.Equal(GinkgoT(), changes, serv.ReputationHistory("key1"))
+		})
+	})
+
 	Describe("Calculating uptime", func() {
-		Context("when no statuses exist yet", func() {
-			It("should return 0", func() {
-				mockDb.On("ListMixStatusSinceWithLimit", "key1", "4", daysAgo(30), LastDayReports*30).Return(emptyList)
+		Context("when no statuses exist in the given window", func() {
+			It("should be unknown", func() {
+				mockDb.On("ListMixStatusDateRange", "key1", "4", daysAgo(1), now()).Return(emptyList)
 
-				uptime := serv.CalculateUptimeSince(persisted1.PubKey, persisted1.IPVersion, daysAgo(30), LastDayReports*30)
-				assert.Equal(GinkgoT(), -1, uptime)
+				uptime := serv.CalculateUptime(persisted1.PubKey, persisted1.IPVersion, LastDay)
+				assert.Equal(GinkgoT(), UnknownUptime, uptime)
 			})
 
 		})
-		Context("when 2 ups and 1 down exist in the given time period", func() {
+		Context("when 2 ups and 1 down exist in the given window", func() {
 			It("should return 66", func() {
-				mockDb.On("ListMixStatusSinceWithLimit", "key1", "4", daysAgo(1), LastDayReports).Return(twoUpOneDown())
+				mockDb.On("ListMixStatusDateRange", "key1", "4", daysAgo(1), now()).Return(twoUpOneDown())
 
-				uptime := serv.CalculateUptimeSince("key1", "4", daysAgo(1), LastDayReports)
+				uptime := serv.CalculateUptime("key1", "4", LastDay)
 				expected := 66 // percent
 				assert.Equal(GinkgoT(), expected, uptime)
 			})
 		})
+		Context("for several windows", func() {
+			It("should only count the statuses within each of them", func() {
+				mockDb.On("ListMixStatusDateRange", "key1", "4", minutesAgo(60), now()).Return(twoUpOneDown())
+
+				uptimes := serv.calculateUptimes("key1", "4", Last5Minutes, LastHour, time.Minute)
+				assert.Equal(GinkgoT(), []int{100, 66, UnknownUptime}, uptimes)
+				mockDb.AssertNumberOfCalls(GinkgoT(), "ListMixStatusDateRange", 1)
+			})
+		})
+	})
+
+	Describe("Refreshing the status reports", func() {
+		It("should recompute every uptime, turning those of untested windows unknown", func() {
+			db := &mocks.IDb{}
+			mix := fixtures.GoodRegisteredMix()
+			db.On("Topology").Return(models.Topology{MixNodes: []models.RegisteredMix{mix}})
+			db.On("ActiveTopology", ReputationThreshold).Return(models.Topology{})
+			db.On("RemovedTopology").Return(models.Topology{})
+			db.On("BatchLoadReports", []string{mix.IdentityKey}).Return(models.BatchMixStatusReport{
+				Report: []models.MixStatusReport{{
+					PubKey:           mix.IdentityKey,
+					MostRecentIPV4:   true,
+					Last5MinutesIPV4: 100,
+					LastHourIPV4:     100,
+					LastDayIPV4:      100,
+					MostRecentIPV6:   true,
+					Last5MinutesIPV6: 100,
+					LastHourIPV6:     100,
+					LastDayIPV6:      100,
+				}},
+			})
+			up, down := true, false
+			db.On("ListMixStatusDateRange", mix.IdentityKey, "4", daysAgo(1), now()).Return([]models.PersistedMixStatus{
+				{MixStatus: models.MixStatus{PubKey: mix.IdentityKey, IPVersion: "4", Up: &up}, Timestamp: minutesAgo(90)},
+				{MixStatus: models.MixStatus{PubKey: mix.IdentityKey, IPVersion: "4", Up: &down}, Timestamp: minutesAgo(120)},
+			})
+			db.On("ListMixStatusDateRange", mix.IdentityKey, "6", daysAgo(1), now()).Return(emptyList)
+			expected := models.BatchMixStatusReport{
+				Report: []models.MixStatusReport{{
+					PubKey:           mix.IdentityKey,
+					MostRecentIPV4:   true,
+					Last5MinutesIPV4: UnknownUptime,
+					LastHourIPV4:     UnknownUptime,
+					LastDayIPV4:      50,
+					MostRecentIPV6:   true,
+					Last5MinutesIPV6: UnknownUptime,
+					LastHourIPV6:     UnknownUptime,
+					LastDayIPV6:      UnknownUptime,
+					Last30DaysIPV4:   UnknownUptime,
+					Last90DaysIPV4:   UnknownUptime,
+					Last30DaysIPV6:   UnknownUptime,
+					Last90DaysIPV6:   UnknownUptime,
+				}},
+			}
+			db.On("SaveBatchMixStatusReport", expected)
+			service := NewService(db, context.NewCLIContext(), DefaultServiceConfig())
+
+			assert.Equal(GinkgoT(), expected, service.updateReports())
+			db.AssertExpectations(GinkgoT())
+		})
+
+		It("should not remove the mixes whose daily uptime is unknown", func() {
+			broken := serv.batchShouldGetRemoved(&models.BatchMixStatusReport{
+				Report: []models.MixStatusReport{
+					{PubKey: "untested", LastDayIPV4: UnknownUptime, LastDayIPV6: UnknownUptime},
+					{PubKey: "broken", LastDayIPV4: 49, LastDayIPV6: UnknownUptime},
+				},
+			})
+			assert.Equal(GinkgoT(), []string{"broken"}, broken)
+		})
 	})
 
 	Describe("Saving a mix status report", func() {
 		Context("when 1 down status exists", func() {
 			BeforeEach(func() {
 				oneDown := []models.PersistedMixStatus{downer}
-				mockDb.On("GetNMostRecentMixStatuses", downer.PubKey, downer.IPVersion, Last5MinutesReports).Return(oneDown)
-				mockDb.On("GetNMostRecentMixStatuses", downer.PubKey, downer.IPVersion, LastHourReports).Return(oneDown)
+				mockDb.On("ListMixStatusDateRange", downer.PubKey, downer.IPVersion, minutesAgo(60), now()).Return(oneDown)
 			})
 			Context("this one *must be* a downer, so calculate using it", func() {
 				BeforeEach(func() {
@@ -239,22 +326,26 @@ var _ = Describe("mixmining.Service", func() {
 						MostRecentIPV4:   false,
 						Last5MinutesIPV4: 0,
 						LastHourIPV4:     0,
-						LastDayIPV4:      0,
+						LastDayIPV4:      UnknownUptime,
 						MostRecentIPV6:   false,
-						Last5MinutesIPV6: 0,
-						LastHourIPV6:     0,
-						LastDayIPV6:      0,
+						Last5MinutesIPV6: UnknownUptime,
+						LastHourIPV6:     UnknownUptime,
+						LastDayIPV6:      UnknownUptime,
+						Last30DaysIPV4:   UnknownUptime,
+						Last90DaysIPV4:   UnknownUptime,
+						Last30DaysIPV6:   UnknownUptime,
+						Last90DaysIPV6:   UnknownUptime,
 					}
-					mockDb.On("UpdateReputation", downer.PubKey, ReportFailureReputationDecrease).Return(true)
+					mockDb.On("UpdateReputation", downer.PubKey, ReportFailureReputationDecrease, models.ReputationChangeMonitorReport).Return(true)
 					mockDb.On("SaveMixStatusReport", expectedSave)
 				})
-				It("should save the initial report, all statuses will be set to down. Node will also be moved to removed set", func() {
-					mockDb.On("MoveToRemovedSet", downer.PubKey)
+				It("should save the initial report, the statuses of the tested windows will be set to down and the others unknown. Node won't be removed until its last day uptime is known", func() {
 					result := serv.SaveStatusReport(downer)
 					assert.Equal(GinkgoT(), 0, result.Last5MinutesIPV4)
 					assert.Equal(GinkgoT(), 0, result.LastHourIPV4)
-					assert.Equal(GinkgoT(), 0, result.LastDayIPV4)
+					assert.Equal(GinkgoT(), UnknownUptime, result.LastDayIPV4)
 					mockDb.AssertExpectations(GinkgoT())
+					mockDb.AssertNotCalled(GinkgoT(), "MoveToRemovedSet", downer.PubKey)
 				})
 			})
 
@@ -262,43 +353,42 @@ var _ = Describe("mixmining.Service", func() {
 		Context("when 1 up status exists", func() {
 			BeforeEach(func() {
 				oneUp := []models.PersistedMixStatus{upper}
-				mockDb.On("GetNMostRecentMixStatuses", downer.PubKey, downer.IPVersion, Last5MinutesReports).Return(oneUp)
-				mockDb.On("GetNMostRecentMixStatuses", downer.PubKey, downer.IPVersion, LastHourReports).Return(oneUp)
+				mockDb.On("ListMixStatusDateRange", upper.PubKey, upper.IPVersion, minutesAgo(60), now()).Return(oneUp)
 			})
 			Context("this one *must be* an upper, so calculate using it", func() {
 				BeforeEach(func() {
-					oneDown := []models.PersistedMixStatus{downer}
-					mockDb.On("GetNMostRecentMixStatuses", upper.PubKey, upper.IPVersion, Last5MinutesReports, now()).Return(oneDown)
-					mockDb.On("GetNMostRecentMixStatuses", upper.PubKey, upper.IPVersion, LastHourReports, now()).Return(oneDown)
 					mockDb.On("LoadReport", upper.PubKey).Return(models.MixStatusReport{}) // TODO: Mockery isn't happy returning an untyped nil, so I've had to sub in a blank `models.MixStatusReport{}`. It will actually return a nil.
 					expectedSave := models.MixStatusReport{
 						PubKey:           upper.PubKey,
 						MostRecentIPV4:   true,
 						Last5MinutesIPV4: 100,
 						LastHourIPV4:     100,
-						LastDayIPV4:      0,
+						LastDayIPV4:      UnknownUptime,
 						MostRecentIPV6:   false,
-						Last5MinutesIPV6: 0,
-						LastHourIPV6:     0,
-						LastDayIPV6:      0,
+						Last5MinutesIPV6: UnknownUptime,
+						LastHourIPV6:     UnknownUptime,
+						LastDayIPV6:      UnknownUptime,
+						Last30DaysIPV4:   UnknownUptime,
+						Last90DaysIPV4:   UnknownUptime,
+						Last30DaysIPV6:   UnknownUptime,
+						Last90DaysIPV6:   UnknownUptime,
 					}
-					mockDb.On("UpdateReputation", upper.PubKey, ReportSuccessReputationIncrease).Return(true)
+					mockDb.On("UpdateReputation", upper.PubKey, ReportSuccessReputationIncrease, models.ReputationChangeMonitorReport).Return(true)
 					mockDb.On("SaveMixStatusReport", expectedSave)
 				})
-				It("should save the initial report, all statuses will be set to up", func() {
+				It("should save the initial report, the statuses of the tested windows will be set to up", func() {
 					result := serv.SaveStatusReport(upper)
 					assert.Equal(GinkgoT(), true, result.MostRecentIPV4)
 					assert.Equal(GinkgoT(), 100, result.Last5MinutesIPV4)
 					assert.Equal(GinkgoT(), 100, result.LastHourIPV4)
-					assert.Equal(GinkgoT(), 0, result.LastDayIPV4)
+					assert.Equal(GinkgoT(), UnknownUptime, result.LastDayIPV4)
 				})
 			})
 		})
 
-		Context("when 2 up statuses exist for the last 5 minutes already and we just added a down", func() {
+		Context("when 2 up statuses and a down one exist for the last hour, and only an up one for the last 5 minutes", func() {
 			BeforeEach(func() {
-				mockDb.On("GetNMostRecentMixStatuses", downer.PubKey, downer.IPVersion, Last5MinutesReports).Return(twoUpOneDown())
-				mockDb.On("GetNMostRecentMixStatuses", downer.PubKey, downer.IPVersion, LastHourReports).Return(twoUpOneDown())
+				mockDb.On("ListMixStatusDateRange", downer.PubKey, downer.IPVersion, minutesAgo(60), now()).Return(twoUpOneDown())
 			})
 			It("should save the report", func() {
 				initialState := models.MixStatusReport{
@@ -316,21 +406,25 @@ var _ = Describe("mixmining.Service", func() {
 				expectedAfterUpdate := models.MixStatusReport{
 					PubKey:           downer.PubKey,
 					MostRecentIPV4:   false,
-					Last5MinutesIPV4: 66,
+					Last5MinutesIPV4: 100,
 					LastHourIPV4:     66,
 					LastDayIPV4:      100, // last day will not change, it's updated in separate routine
 					MostRecentIPV6:   false,
 					Last5MinutesIPV6: 0,
 					LastHourIPV6:     0,
 					LastDayIPV6:      0,
+					Last30DaysIPV4:   UnknownUptime,
+					Last90DaysIPV4:   UnknownUptime,
+					Last30DaysIPV6:   UnknownUptime,
+					Last90DaysIPV6:   UnknownUptime,
 				}
 				mockDb.On("LoadReport", downer.PubKey).Return(initialState)
 				mockDb.On("SaveMixStatusReport", expectedAfterUpdate)
-				mockDb.On("UpdateReputation", downer.PubKey, ReportFailureReputationDecrease).Return(true)
+				mockDb.On("UpdateReputation", downer.PubKey, ReportFailureReputationDecrease, models.ReputationChangeMonitorReport).Return(true)
 
 				updatedStatus := serv.SaveStatusReport(downer)
 				assert.Equal(GinkgoT(), expectedAfterUpdate, updatedStatus)
-				mockDb.AssertCalled(GinkgoT(), "UpdateReputation", downer.PubKey, ReportFailureReputationDecrease)
+				mockDb.AssertCalled(GinkgoT(), "UpdateReputation", downer.PubKey, ReportFailureReputationDecrease, models.ReputationChangeMonitorReport)
 
 				mockDb.AssertExpectations(GinkgoT())
 			})
@@ -339,7 +433,7 @@ var _ = Describe("mixmining.Service", func() {
 
 	Describe("Saving batch status report", func() {
 		Context("if it contains v4 and v6 up status for same node", func() {
-			It("should combine them into single entry. Node will also be moved to removed set", func() {
+			It("should combine them into single entry", func() {
 				upv4 := persistedStatusFrom(statusDown("key1", "4"))
 				upv6 := persistedStatusFrom(statusDown("key1", "6"))
 				batchReport := []models.PersistedMixStatus{upv4, upv6}
@@ -350,30 +444,97 @@ var _ = Describe("mixmining.Service", func() {
 						MostRecentIPV4:   false,
 						Last5MinutesIPV4: 0,
 						LastHourIPV4:     0,
-						LastDayIPV4:      0,
+						LastDayIPV4:      UnknownUptime,
 						MostRecentIPV6:   false,
 						Last5MinutesIPV6: 0,
 						LastHourIPV6:     0,
-						LastDayIPV6:      0,
+						LastDayIPV6:      UnknownUptime,
+						Last30DaysIPV4:   UnknownUptime,
+						Last90DaysIPV4:   UnknownUptime,
+						Last30DaysIPV6:   UnknownUptime,
+						Last90DaysIPV6:   UnknownUptime,
 					}},
 				}
 
-				mockDb.On("GetNMostRecentMixStatuses", "key1", "4", Last5MinutesReports).Return([]models.PersistedMixStatus{persistedStatusDown("key1", "4")})
-				mockDb.On("GetNMostRecentMixStatuses", "key1", "4", LastHourReports).Return([]models.PersistedMixStatus{persistedStatusDown("key1", "4")})
-				mockDb.On("GetNMostRecentMixStatuses", "key1", "6", Last5MinutesReports).Return([]models.PersistedMixStatus{persistedStatusDown("key1", "6")})
-				mockDb.On("GetNMostRecentMixStatuses", "key1", "6", LastHourReports).Return([]models.PersistedMixStatus{persistedStatusDown("key1", "6")})
+				mockDb.On("ListMixStatusDateRange", "key1", "4", minutesAgo(60), now()).Return([]models.PersistedMixStatus{persistedStatusDown("key1", "4")})
+				mockDb.On("ListMixStatusDateRange", "key1", "6", minutesAgo(60), now()).Return([]models.PersistedMixStatus{persistedStatusDown("key1", "6")})
 
 				mockDb.On("BatchLoadReports", []string{"key1", "key1"}).Return(models.BatchMixStatusReport{Report: make([]models.MixStatusReport, 0)})
 				mockDb.On("SaveBatchMixStatusReport", expected)
-				mockDb.On("BatchUpdateReputation", map[string]int64{"key1": 2 * ReportFailureReputationDecrease})
-				mockDb.On("BatchMoveToRemovedSet", []string{"key1"})
+				mockDb.On("BatchUpdateReputation", map[string]int64{"key1": 2 * ReportFailureReputationDecrease}, models.ReputationChangeMonitorReport)
 				updatedStatus := serv.SaveBatchStatusReport(batchReport)
 				assert.Equal(GinkgoT(), 1, len(updatedStatus.Report))
-				mockDb.AssertCalled(GinkgoT(), "BatchUpdateReputation", map[string]int64{"key1": 2 * ReportFailureReputationDecrease})
+				mockDb.AssertCalled(GinkgoT(), "BatchUpdateReputation", map[string]int64{"key1": 2 * ReportFailureReputationDecrease}, models.ReputationChangeMonitorReport)
 			})
 		})
 	})
 
+	Describe("Changing reputations with another policy", func() {
+		var ewmaServ *Service
+		var db *mocks.IDb
+
+		BeforeEach(func() {
+			db = &mocks.IDb{}
+			db.On("Topology").Return(models.Topology{})
+			db.On("ActiveTopology", ReputationThreshold).Return(models.Topology{})
+			db.On("RemovedTopology").Return(models.Topology{})
+			db.On("ListMixStatusDateRange", "key1", mock.Anything, minutesAgo(60), now()).Return(emptyList)
+			db.On("BatchLoadReputations", []string{"key1", "key1"}).Return(map[string]int64{"key1": 100})
+			db.On("BatchLoadReputations", []string{"key1"}).Return(map[string]int64{"key1": 100})
+			cfg := DefaultServiceConfig()
+			cfg.Reputation.Policy = EWMAReputationPolicyName
+			ewmaServ = NewService(db, context.NewCLIContext(), cfg)
+		})
+
+		It("should change the reputation of a single status by the policy", func() {
+			db.On("LoadReport", "key1").Return(models.MixStatusReport{})
+			db.On("SaveMixStatusReport", mock.Anything)
+			db.On("UpdateReputation", "key1", int64(10), models.ReputationChangeMonitorReport).Return(true)
+
+			ewmaServ.SaveStatusReport(persistedStatusFrom(statusUp("key1", "4")))
+			db.AssertCalled(GinkgoT(), "UpdateReputation", "key1", int64(10), models.ReputationChangeMonitorReport)
+		})
+
+		It("should change the reputation of a node for each status of a batch in turn", func() {
+			db.On("BatchLoadReports", []string{"key1", "key1"}).Return(models.BatchMixStatusReport{Report: make([]models.MixStatusReport, 0)})
+			db.On("SaveBatchMixStatusReport", mock.Anything)
+			// 100 moves a tenth of the way to 200, then 110 does
+			db.On("BatchUpdateReputation", map[string]int64{"key1": 10 + 9}, models.ReputationChangeMonitorReport)
+
+			ewmaServ.SaveBatchStatusReport([]models.PersistedMixStatus{
+				persistedStatusFrom(statusUp("key1", "4")),
+				persistedStatusFrom(statusUp("key1", "6")),
+			})
+			db.AssertCalled(GinkgoT(), "BatchUpdateReputation", map[string]int64{"key1": 10 + 9}, models.ReputationChangeMonitorReport)
+		})
+	})
+
+	Describe("Decaying reputations", func() {
+		It("should decay the reputation of every registered node", func() {
+			mix := fixtures.GoodRegisteredMix()
+			mix.Reputation = 200
+			gateway := fixtures.GoodRegisteredGateway()
+			gateway.Reputation = 1
+			db := &mocks.IDb{}
+			db.On("Topology").Return(models.Topology{
+				MixNodes: []models.RegisteredMix{mix},
+				Gateways: []models.RegisteredGateway{gateway},
+			})
+			db.On("ActiveTopology", ReputationThreshold).Return(models.Topology{})
+			db.On("RemovedTopology").Return(models.Topology{})
+			// a reputation of 1 rounds back to 1 and isn't changed
+			db.On("BatchUpdateReputation", map[string]int64{mix.IdentityKey: -100}, models.ReputationChangeDecay)
+			cfg := DefaultServiceConfig()
+			cfg.Reputation.DecayHalfLife = time.Hour
+			cfg.Reputation.DecayInterval = time.Hour
+			decayingServ := NewService(db, context.NewCLIContext(), cfg)
+
+			assert.Nil(GinkgoT(), decayingServ.decayReputations())
+			db.AssertExpectations(GinkgoT())
+			assert.Len(GinkgoT(), decayingServ.WorkerStatuses(), 5)
+		})
+	})
+
 	Describe("Getting a mix status report", func() {
 		Context("When no saved report exists for a pubkey", func() {
 			It("should return an empty report", func() {
@@ -398,12 +559,48 @@ var _ = Describe("mixmining.Service", func() {
 					LastDayIPV6:      100,
 				}
 				mockDb.On("LoadReport", "superkey").Return(perfect)
+				last30Days := alignToBucket(timemock.Now().Add(-30*day).UnixNano(), day)
+				last90Days := alignToBucket(timemock.Now().Add(-90*day).UnixNano(), day)
+				mockDb.On("SumDailyUptimes", last30Days, []string{"superkey"}).Return([]models.UptimeRollup{
+					{PubKey: "superkey", IPVersion: "4", Start: last30Days, Reports: 10, Up: 10},
+				})
+				mockDb.On("SumDailyUptimes", last90Days, []string{"superkey"}).Return([]models.UptimeRollup{
+					{PubKey: "superkey", IPVersion: "4", Start: last90Days, Reports: 40, Up: 30},
+				})
 
 				report := serv.GetStatusReport("superkey")
-				assert.Equal(GinkgoT(), perfect, report)
+				expected := perfect
+				expected.Last30DaysIPV4 = 100
+				expected.Last90DaysIPV4 = 75
+				expected.Last30DaysIPV6 = UnknownUptime
+				expected.Last90DaysIPV6 = UnknownUptime
+				assert.Equal(GinkgoT(), expected, report)
 			})
 		})
 	})
+
+	Describe("Getting every mix status report", func() {
+		It("should add up the daily uptimes of every node", func() {
+			mockDb.On("LoadNonStaleReports", RemovalUptime).Return(models.BatchMixStatusReport{
+				Report: []models.MixStatusReport{{PubKey: "key1"}, {PubKey: "key2"}},
+			})
+			last30Days := alignToBucket(timemock.Now().Add(-30*day).UnixNano(), day)
+			last90Days := alignToBucket(timemock.Now().Add(-90*day).UnixNano(), day)
+			mockDb.On("SumDailyUptimes", last30Days, []string(nil)).Return([]models.UptimeRollup{
+				{PubKey: "key1", IPVersion: "6", Start: last30Days, Reports: 4, Up: 1},
+			})
+			mockDb.On("SumDailyUptimes", last90Days, []string(nil)).Return([]models.UptimeRollup{
+				{PubKey: "key1", IPVersion: "6", Start: last90Days, Reports: 4, Up: 1},
+				{PubKey: "key2", IPVersion: "4", Start: last90Days, Reports: 2, Up: 2},
+			})
+
+			reports := serv.BatchGetMixStatusReport().Report
+			assert.Equal(GinkgoT(), []models.MixStatusReport{
+				{PubKey: "key1", Last30DaysIPV4: UnknownUptime, Last90DaysIPV4: UnknownUptime, Last30DaysIPV6: 25, Last90DaysIPV6: 25},
+				{PubKey: "key2", Last30DaysIPV4: UnknownUptime, Last90DaysIPV4: 100, Last30DaysIPV6: UnknownUptime, Last90DaysIPV6: UnknownUptime},
+			}, reports)
+		})
+	})
 })
 
 var _ = Describe("mixmining.registration.Service", func() {
@@ -415,7 +612,7 @@ var _ = Describe("mixmining.registration.Service", func() {
 		mockDb.On("Topology").Return(models.Topology{})
 		mockDb.On("ActiveTopology", ReputationThreshold).Return(models.Topology{})
 		mockDb.On("RemovedTopology").Return(models.Topology{})
-		serv = NewService(mockDb, context.NewCLIContext(), true)
+		serv = NewService(mockDb, context.NewCLIContext(), DefaultServiceConfig())
 	})
 
 	Describe("Adding mix registration info", func() {
@@ -431,6 +628,80 @@ var _ = Describe("mixmining.registration.Service", func() {
 		})
 	})
 
+	Describe("Assigning mix layers", func() {
+		mixInLayer := func(identityKey string, layer uint, reputation int64) models.RegisteredMix {
+			mix := fixtures.GoodRegisteredMix()
+			mix.IdentityKey = identityKey
+			mix.Layer = layer
+			mix.Reputation = reputation
+			return mix
+		}
+		withMixes := func(mixes ...models.RegisteredMix) {
+			mockDb = &mocks.IDb{}
+			mockDb.On("Topology").Return(models.Topology{MixNodes: mixes})
+			mockDb.On("ActiveTopology", ReputationThreshold).Return(models.Topology{})
+			mockDb.On("RemovedTopology").Return(models.Topology{})
+			serv = NewService(mockDb, context.NewCLIContext(), DefaultServiceConfig())
+		}
+
+		Context("When a new mix registers", func() {
+			It("ignores the announced layer and puts it in the least populated layer", func() {
+				withMixes(mixInLayer("a", 1, 10), mixInLayer("b", 2, 10))
+
+				info := fixtures.GoodMixRegistrationInfo()
+				info.Layer = 1
+				expected := info
+				expected.Layer = 3
+				mockDb.On("RegisterMix", models.RegisteredMix{MixRegistrationInfo: expected})
+
+				assert.Equal(GinkgoT(), uint(3), serv.RegisterMix(info))
+				mockDb.AssertCalled(GinkgoT(), "RegisterMix", models.RegisteredMix{MixRegistrationInfo: expected})
+			})
+		})
+
+		Context("When a registered mix registers again", func() {
+			It("keeps its layer", func() {
+				info := fixtures.GoodMixRegistrationInfo()
+				withMixes(mixInLayer(info.IdentityKey, 2, 10))
+
+				expected := info
+				expected.Layer = 2
+				mockDb.On("RegisterMix", models.RegisteredMix{MixRegistrationInfo: expected})
+
+				assert.Equal(GinkgoT(), uint(2), serv.RegisterMix(info))
+			})
+		})
+
+		Context("When the layers are unbalanced", func() {
+			BeforeEach(func() {
+				withMixes(
+					mixInLayer("a", 1, 30),
+					mixInLayer("b", 1, 20),
+					mixInLayer("c", 1, 10),
+					mixInLayer("d", 2, 40),
+				)
+			})
+
+			It("proposes moving the lightest extra mixes without applying it", func() {
+				rebalance := serv.ProposeLayerRebalance()
+				assert.Equal(GinkgoT(), []types.LayerMove{
+					{ID: "b", From: 1, To: 3},
+				}, rebalance.Moves)
+				assert.Equal(GinkgoT(), []int{3, 1, 0}, []int{rebalance.Before[0].Nodes, rebalance.Before[1].Nodes, rebalance.Before[2].Nodes})
+				assert.Equal(GinkgoT(), []int{2, 1, 1}, []int{rebalance.After[0].Nodes, rebalance.After[1].Nodes, rebalance.After[2].Nodes})
+				mockDb.AssertNotCalled(GinkgoT(), "SetMixLayer", mock.Anything, mock.Anything)
+			})
+
+			It("moves the mixes when rebalancing", func() {
+				mockDb.On("SetMixLayer", "b", uint(3)).Return(true)
+
+				rebalance := serv.RebalanceLayers()
+				assert.Len(GinkgoT(), rebalance.Moves, 1)
+				mockDb.AssertCalled(GinkgoT(), "SetMixLayer", "b", uint(3))
+			})
+		})
+	})
+
 	Describe("Adding gateway registration info", func() {
 		It("creates new registered gateway with empty reputation and zero timestamp", func() {
 			info := fixtures.GoodGatewayRegistrationInfo()
@@ -514,15 +785,83 @@ var _ = Describe("mixmining.registration.Service", func() {
 		})
 	})
 
+	Describe("Mirroring registrations onto the chain", func() {
+		var mockBridge *mocks.IChainBridge
+		var mirrored chan string
+
+		// nextMirrored returns the next bridge call made by the background worker
+		nextMirrored := func() string {
+			select {
+			case call := <-mirrored:
+				return call
+			case <-time.After(time.Second):
+				return "nothing"
+			}
+		}
+
+		BeforeEach(func() {
+			mockBridge = &mocks.IChainBridge{}
+			mirrored = make(chan string, 10)
+			record := func(args mock.Arguments) {
+				mirrored <- fmt.Sprintf("%v", args.Get(0))
+			}
+			mockBridge.On("Reconcile", mock.Anything).Return(nil).Run(func(mock.Arguments) { mirrored <- "reconcile" })
+			mockBridge.On("RegisterMix", mock.Anything).Return(nil).Run(record)
+			mockBridge.On("RegisterGateway", mock.Anything).Return(errors.New("chain unavailable")).Run(record)
+			mockBridge.On("UnregisterNode", mock.Anything).Return(nil).Run(record)
+			mockDb.On("RollUpStatuses", mock.Anything).Return(nil)
+			mockDb.On("RemoveOldStatuses", mock.Anything)
+			mockDb.On("RemoveOldRollups", mock.Anything, mock.Anything)
+			serv.EnableChainBridge(mockBridge)
+			assert.Nil(GinkgoT(), serv.Start(stdcontext.Background()))
+		})
+
+		AfterEach(func() {
+			serv.Stop()
+		})
+
+		It("reconciles the chain with the directory topology when enabled", func() {
+			assert.Equal(GinkgoT(), "reconcile", nextMirrored())
+			mockBridge.AssertCalled(GinkgoT(), "Reconcile", models.Topology{})
+		})
+
+		It("mirrors registrations and unregistrations in order", func() {
+			mix := fixtures.GoodMixRegistrationInfo()
+			mockDb.On("RegisterMix", models.RegisteredMix{MixRegistrationInfo: mix})
+			mockDb.On("GetNodeMixHost", mix.IdentityKey).Return("127.0.0.1:1234")
+			mockDb.On("UnregisterNode", mix.IdentityKey).Return(true)
+
+			serv.RegisterMix(mix)
+			_, err := serv.UnregisterNode(mix.IdentityKey, "127.0.0.1")
+			assert.Nil(GinkgoT(), err)
+
+			assert.Equal(GinkgoT(), "reconcile", nextMirrored())
+			assert.Equal(GinkgoT(), fmt.Sprintf("%v", mix), nextMirrored())
+			assert.Equal(GinkgoT(), mix.IdentityKey, nextMirrored())
+		})
+
+		It("keeps the directory registration when mirroring fails", func() {
+			gateway := fixtures.GoodGatewayRegistrationInfo()
+			registeredGateway := models.RegisteredGateway{GatewayRegistrationInfo: gateway}
+			mockDb.On("RegisterGateway", registeredGateway)
+
+			serv.RegisterGateway(gateway)
+
+			assert.Equal(GinkgoT(), "reconcile", nextMirrored())
+			assert.Equal(GinkgoT(), fmt.Sprintf("%v", gateway), nextMirrored())
+			mockDb.AssertCalled(GinkgoT(), "RegisterGateway", registeredGateway)
+		})
+	})
+
 	Describe("Setting reputation of a node", func() {
 		Context("With given identity when it exists", func() {
 			It("Calls internal database with correct arguments", func() {
 				nodeID := "foomp"
 				newRep := int64(42)
-				mockDb.On("SetReputation", nodeID, newRep).Return(true)
+				mockDb.On("SetReputation", nodeID, newRep, models.ReputationChangeAdminOverride).Return(true)
 
 				assert.True(GinkgoT(), serv.SetReputation(nodeID, newRep))
-				mockDb.AssertCalled(GinkgoT(), "SetReputation", nodeID, newRep)
+				mockDb.AssertCalled(GinkgoT(), "SetReputation", nodeID, newRep, models.ReputationChangeAdminOverride)
 			})
 		})
 
@@ -530,11 +869,242 @@ var _ = Describe("mixmining.registration.Service", func() {
 			It("Calls internal database with correct arguments", func() {
 				nodeID := "foomp"
 				newRep := int64(42)
-				mockDb.On("SetReputation", nodeID, newRep).Return(false)
+				mockDb.On("SetReputation", nodeID, newRep, models.ReputationChangeAdminOverride).Return(false)
 
 				assert.False(GinkgoT(), serv.SetReputation(nodeID, newRep))
-				mockDb.AssertCalled(GinkgoT(), "SetReputation", nodeID, newRep)
+				mockDb.AssertCalled(GinkgoT(), "SetReputation", nodeID, newRep, models.ReputationChangeAdminOverride)
 			})
 		})
 	})
 })
+
+var _ = Describe("mixmining.Service lifecycle", func() {
+	var mockDb *mocks.IDb
+	var serv *Service
+
+	BeforeEach(func() {
+		mockDb = &mocks.IDb{}
+		mockDb.On("Topology").Return(models.Topology{})
+		mockDb.On("ActiveTopology", ReputationThreshold).Return(models.Topology{})
+		mockDb.On("RemovedTopology").Return(models.Topology{})
+		mockDb.On("RollUpStatuses", mock.Anything).Return(nil)
+		mockDb.On("RemoveOldStatuses", mock.Anything)
+		mockDb.On("RemoveOldRollups", mock.Anything, mock.Anything)
+		serv = NewService(mockDb, context.NewCLIContext(), DefaultServiceConfig())
+	})
+
+	statusOf := func(name string) models.WorkerStatus {
+		for _, status := range serv.WorkerStatuses() {
+			if status.Name == name {
+				return status
+			}
+		}
+		panic("no such worker " + name)
+	}
+
+	Context("Before being started", func() {
+		It("runs none of the workers", func() {
+			for _, status := range serv.WorkerStatuses() {
+				assert.Equal(GinkgoT(), uint64(0), status.Runs)
+				assert.Equal(GinkgoT(), int64(0), status.LastRun)
+			}
+			mockDb.AssertNotCalled(GinkgoT(), "RemoveOldStatuses", mock.Anything)
+		})
+
+		It("can still be stopped", func() {
+			serv.Stop()
+		})
+	})
+
+	Context("Once started", func() {
+		It("runs the immediate workers straight away and records how it went", func() {
+			assert.Nil(GinkgoT(), serv.Start(stdcontext.Background()))
+			serv.Stop()
+
+			purge := statusOf("statuses_purge")
+			assert.Equal(GinkgoT(), uint64(1), purge.Runs)
+			assert.NotZero(GinkgoT(), purge.LastRun)
+			assert.Empty(GinkgoT(), purge.LastError)
+			assert.False(GinkgoT(), purge.Running)
+			assert.Equal(GinkgoT(), int64(StatusesPurgeInterval), purge.Interval)
+			mockDb.AssertCalled(GinkgoT(), "RollUpStatuses", alignToBucket(timemock.Now().Add(-StatusesRetention).UnixNano(), day))
+			mockDb.AssertCalled(GinkgoT(), "RemoveOldStatuses", timemock.Now().Add(-StatusesRetention).UnixNano())
+
+			// there's no validator node to ask in the tests
+			validators := statusOf("validators")
+			assert.Equal(GinkgoT(), uint64(1), validators.Runs)
+			assert.Contains(GinkgoT(), validators.LastError, "failed to grab validators")
+
+			assert.Equal(GinkgoT(), uint64(0), statusOf("reports").Runs)
+			assert.Equal(GinkgoT(), uint64(0), statusOf("layer_rebalance").Runs)
+		})
+
+		It("runs the workers every interval", func() {
+			cfg := DefaultServiceConfig()
+			cfg.LayerRebalanceInterval = time.Millisecond
+			serv = NewService(mockDb, context.NewCLIContext(), cfg)
+
+			assert.Nil(GinkgoT(), serv.Start(stdcontext.Background()))
+			assert.Eventually(GinkgoT(), func() bool {
+				return statusOf("layer_rebalance").Runs >= 2
+			}, time.Second, time.Millisecond)
+			serv.Stop()
+		})
+
+		It("can't be started again", func() {
+			assert.Nil(GinkgoT(), serv.Start(stdcontext.Background()))
+			assert.NotNil(GinkgoT(), serv.Start(stdcontext.Background()))
+			serv.Stop()
+		})
+
+		It("stops the workers when its context is done", func() {
+			cfg := DefaultServiceConfig()
+			cfg.LayerRebalanceInterval = time.Millisecond
+			serv = NewService(mockDb, context.NewCLIContext(), cfg)
+
+			ctx, cancel := stdcontext.WithCancel(stdcontext.Background())
+			assert.Nil(GinkgoT(), serv.Start(ctx))
+			cancel()
+			serv.Stop()
+
+			runs := statusOf("layer_rebalance").Runs
+			time.Sleep(time.Millisecond * 10)
+			assert.Equal(GinkgoT(), runs, statusOf("layer_rebalance").Runs)
+		})
+	})
+
+	Context("With the chain bridge enabled", func() {
+		It("reports the bridge worker as well", func() {
+			mockBridge := &mocks.IChainBridge{}
+			mockBridge.On("Reconcile", mock.Anything).Return(errors.New("chain unavailable"))
+			serv.EnableChainBridge(mockBridge)
+
+			assert.Nil(GinkgoT(), serv.Start(stdcontext.Background()))
+			assert.Eventually(GinkgoT(), func() bool {
+				return statusOf("chain_bridge").Runs == 1
+			}, time.Second, time.Millisecond)
+			serv.Stop()
+
+			bridge := statusOf("chain_bridge")
+			assert.Equal(GinkgoT(), int64(0), bridge.Interval)
+			assert.Contains(GinkgoT(), bridge.LastError, "chain unavailable")
+		})
+
+		It("reconciles the chain again every interval", func() {
+			cfg := DefaultServiceConfig()
+			cfg.ChainBridgeReconcileInterval = time.Millisecond
+			serv = NewService(mockDb, context.NewCLIContext(), cfg)
+			mockBridge := &mocks.IChainBridge{}
+			mockBridge.On("Reconcile", mock.Anything).Return(nil)
+			serv.EnableChainBridge(mockBridge)
+
+			assert.Nil(GinkgoT(), serv.Start(stdcontext.Background()))
+			assert.Eventually(GinkgoT(), func() bool {
+				return statusOf("chain_reconciliation").Runs >= 2 && statusOf("chain_bridge").Runs >= 3
+			}, time.Second, time.Millisecond)
+			serv.Stop()
+
+			assert.Equal(GinkgoT(), int64(time.Millisecond), statusOf("chain_reconciliation").Interval)
+			mockBridge.AssertCalled(GinkgoT(), "Reconcile", models.Topology{})
+		})
+
+		It("leaves the rebalancing of the layers to the chain", func() {
+			serv.EnableChainBridge(&mocks.IChainBridge{})
+
+			for _, status := range serv.WorkerStatuses() {
+				assert.NotEqual(GinkgoT(), "layer_rebalance", status.Name)
+			}
+			assert.Equal(GinkgoT(), int64(ChainBridgeReconcileInterval), statusOf("chain_reconciliation").Interval)
+		})
+
+		It("postpones the reconciliation while the queue is full", func() {
+			cfg := DefaultServiceConfig()
+			cfg.ChainBridgeQueueSize = 1
+			serv = NewService(mockDb, context.NewCLIContext(), cfg)
+			serv.EnableChainBridge(&mocks.IChainBridge{})
+
+			// the startup reconciliation fills the queue as the service isn't started
+			assert.NotNil(GinkgoT(), serv.queueReconciliation())
+		})
+	})
+})
+
+var _ = Describe("mixmining.Service uptime series", func() {
+	var mockDb *mocks.IDb
+	var serv *Service
+
+	booltrue := true
+	boolfalse := false
+	hour := int64(time.Hour)
+	statusAt := func(timestamp int64, up *bool) models.PersistedMixStatus {
+		return models.PersistedMixStatus{
+			MixStatus: models.MixStatus{PubKey: "key", IPVersion: "4", Up: up},
+			Timestamp: timestamp,
+		}
+	}
+
+	BeforeEach(func() {
+		mockDb = &mocks.IDb{}
+		mockDb.On("Topology").Return(models.Topology{})
+		mockDb.On("ActiveTopology", ReputationThreshold).Return(models.Topology{})
+		mockDb.On("RemovedTopology").Return(models.Topology{})
+		serv = NewService(mockDb, context.NewCLIContext(), DefaultServiceConfig())
+	})
+
+	It("computes the uptime of every bucket, aligned on the bucket size", func() {
+		yesterday := alignToBucket(timemock.Now().UnixNano(), day) - int64(day)
+		mockDb.On("ListMixStatusDateRange", "key", "4", yesterday+10*hour, yesterday+13*hour-1).Return([]models.PersistedMixStatus{
+			statusAt(yesterday+12*hour+int64(time.Minute), &booltrue),
+			statusAt(yesterday+10*hour+int64(50*time.Minute), &boolfalse),
+			statusAt(yesterday+10*hour+int64(40*time.Minute), &booltrue),
+		})
+
+		series := serv.GetUptimeSeries("key", "4", yesterday+10*hour+int64(30*time.Minute), yesterday+13*hour, "1h")
+
+		assert.Equal(GinkgoT(), models.UptimeSeries{
+			PubKey:    "key",
+			IPVersion: "4",
+			Bucket:    "1h",
+			Buckets: []models.UptimeBucket{
+				{Start: yesterday + 10*hour, End: yesterday + 11*hour, Uptime: 50, Reports: 2},
+				{Start: yesterday + 11*hour, End: yesterday + 12*hour, Uptime: UnknownUptime, Reports: 0},
+				{Start: yesterday + 12*hour, End: yesterday + 13*hour, Uptime: 100, Reports: 1},
+			},
+		}, series)
+	})
+
+	It("takes the buckets whose statuses were purged from the rollups", func() {
+		days := int64(day)
+		// statuses are kept for a week, the 6th day back is the oldest one still whole
+		nineDaysAgo := alignToBucket(timemock.Now().Add(-9*day).UnixNano(), day)
+		sixDaysAgo := nineDaysAgo + 3*days
+		mockDb.On("ListDailyUptimes", "key", "4", nineDaysAgo, sixDaysAgo-1).Return([]models.UptimeRollup{
+			{PubKey: "key", IPVersion: "4", Start: nineDaysAgo, Reports: 10, Up: 9},
+			{PubKey: "key", IPVersion: "4", Start: nineDaysAgo + 2*days, Reports: 4, Up: 0},
+		})
+		mockDb.On("ListMixStatusDateRange", "key", "4", sixDaysAgo, sixDaysAgo+days-1).Return([]models.PersistedMixStatus{
+			statusAt(sixDaysAgo+hour, &booltrue),
+		})
+
+		series := serv.GetUptimeSeries("key", "4", nineDaysAgo, sixDaysAgo+days, "1d")
+
+		assert.Equal(GinkgoT(), []models.UptimeBucket{
+			{Start: nineDaysAgo, End: nineDaysAgo + days, Uptime: 90, Reports: 10},
+			{Start: nineDaysAgo + days, End: nineDaysAgo + 2*days, Uptime: UnknownUptime, Reports: 0},
+			{Start: nineDaysAgo + 2*days, End: sixDaysAgo, Uptime: 0, Reports: 4},
+			{Start: sixDaysAgo, End: sixDaysAgo + days, Uptime: 100, Reports: 1},
+		}, series.Buckets)
+	})
+
+	It("rejects series it can't compute", func() {
+		assert.Nil(GinkgoT(), validateUptimeSeries("6", 0, 24*hour, "5m"))
+		assert.NotNil(GinkgoT(), validateUptimeSeries("5", 0, hour, "1h"))
+		assert.NotNil(GinkgoT(), validateUptimeSeries("4", 0, hour, "1w"))
+		assert.NotNil(GinkgoT(), validateUptimeSeries("4", hour, hour, "1h"))
+		assert.NotNil(GinkgoT(), validateUptimeSeries("4", 0, 30*24*hour, "5m"))
+		assert.NotNil(GinkgoT(), validateUptimeSeries("4", -hour, hour, "1h"))
+		assert.NotNil(GinkgoT(), validateUptimeSeries("4", math.MinInt64, math.MaxInt64, "1d"))
+		assert.NotNil(GinkgoT(), validateUptimeSeries("4", 0, math.MaxInt64, "1d"))
+		assert.NotNil(GinkgoT(), validateUptimeSeries("4", math.MaxInt64-hour, math.MaxInt64, "5m"))
+	})
+})
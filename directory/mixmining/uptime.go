@@ -0,0 +1,139 @@
+// Copyright 2020 Nym Technologies SA
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mixmining
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/BorisBorshevsky/timemock"
+	"github.com/nymtech/nym/validator/nym/directory/models"
+)
+
+// UnknownUptime is reported for periods during which a node wasn't tested
+const UnknownUptime = -1
+
+// MaxUptimeBuckets bounds the size of an uptime series, it's a week worth of the smallest buckets
+const MaxUptimeBuckets = 7 * 24 * 12
+
+// UptimeBuckets are the bucket sizes uptime series can be split into
+var UptimeBuckets = map[string]time.Duration{
+	"5m": time.Minute * 5,
+	"1h": time.Hour,
+	"1d": time.Hour * 24,
+}
+
+// validateUptimeSeries checks the uptime series can be computed, and isn't too large
+func validateUptimeSeries(ipVersion string, from int64, to int64, bucketName string) error {
+	if ipVersion != "4" && ipVersion != "6" {
+		return fmt.Errorf("ip version must be 4 or 6, got %q", ipVersion)
+	}
+	bucket, ok := UptimeBuckets[bucketName]
+	if !ok {
+		return fmt.Errorf("bucket must be one of 5m, 1h or 1d, got %q", bucketName)
+	}
+	if from < 0 {
+		return fmt.Errorf("from (%d) must not be before the unix epoch", from)
+	}
+	if from >= to {
+		return fmt.Errorf("from (%d) must be before to (%d)", from, to)
+	}
+	// bounding the range first keeps the bucket count, and the end of the last bucket, from overflowing
+	if to > math.MaxInt64-int64(bucket) {
+		return fmt.Errorf("to (%d) is too far in the future", to)
+	}
+	if to-from > MaxUptimeBuckets*int64(bucket) {
+		return fmt.Errorf("the range spans more than %d buckets", MaxUptimeBuckets)
+	}
+	if buckets := (to - alignToBucket(from, bucket) + int64(bucket) - 1) / int64(bucket); buckets > MaxUptimeBuckets {
+		return fmt.Errorf("the range spans %d buckets, at most %d are allowed", buckets, MaxUptimeBuckets)
+	}
+	return nil
+}
+
+func alignToBucket(timestamp int64, bucket time.Duration) int64 {
+	return timestamp - timestamp%int64(bucket)
+}
+
+// GetUptimeSeries computes the uptime of a node between from and to, in unix nanoseconds, bucket by bucket.
+// Buckets are aligned on multiples of their size since the unix epoch, so that the same bucket always covers
+// the same period, which means the first one may start before from. Hourly and daily buckets outlive the
+// statuses thanks to the rollups, 5 minute ones don't.
+func (service *Service) GetUptimeSeries(pubkey string, ipVersion string, from int64, to int64, bucketName string) models.UptimeSeries {
+	bucket := int64(UptimeBuckets[bucketName])
+	start := alignToBucket(from, UptimeBuckets[bucketName])
+
+	var buckets []models.UptimeBucket
+	for bucketStart := start; bucketStart < to; bucketStart += bucket {
+		buckets = append(buckets, models.UptimeBucket{Start: bucketStart, End: bucketStart + bucket})
+	}
+	up := make([]int, len(buckets))
+
+	// buckets holding statuses past their retention, even partially, come from the rollups of the same size.
+	// The ranges are inclusive on both ends, while buckets exclude their end.
+	statusesSince := start
+	if listRollups := service.rollupsOfSize(UptimeBuckets[bucketName]); listRollups != nil {
+		retained := timemock.Now().Add(-service.cfg.StatusesRetention).UnixNano()
+		if firstRetainedBucket := alignToBucket(retained, UptimeBuckets[bucketName]) + bucket; firstRetainedBucket > start {
+			statusesSince = firstRetainedBucket
+			rollupsUntil := firstRetainedBucket
+			if rollupsUntil > to {
+				rollupsUntil = to
+			}
+			for _, rollup := range listRollups(pubkey, ipVersion, start, rollupsUntil-1) {
+				if i := (rollup.Start - start) / bucket; i >= 0 && i < int64(len(buckets)) {
+					buckets[i].Reports += rollup.Reports
+					up[i] += rollup.Up
+				}
+			}
+		}
+	}
+
+	if statusesSince < to {
+		for _, status := range service.db.ListMixStatusDateRange(pubkey, ipVersion, statusesSince, to-1) {
+			i := (status.Timestamp - start) / bucket
+			if i < 0 || i >= int64(len(buckets)) {
+				continue
+			}
+			buckets[i].Reports++
+			if *status.Up {
+				up[i]++
+			}
+		}
+	}
+
+	for i := range buckets {
+		buckets[i].Uptime = service.uptimePercent(up[i], buckets[i].Reports)
+	}
+
+	return models.UptimeSeries{
+		PubKey:    pubkey,
+		IPVersion: ipVersion,
+		Bucket:    bucketName,
+		Buckets:   buckets,
+	}
+}
+
+// rollupsOfSize returns how to list the rollups matching buckets of the given size, nil if there are none
+func (service *Service) rollupsOfSize(bucket time.Duration) func(string, string, int64, int64) []models.UptimeRollup {
+	switch bucket {
+	case time.Hour:
+		return service.db.ListHourlyUptimes
+	case day:
+		return service.db.ListDailyUptimes
+	}
+	return nil
+}
@@ -0,0 +1,158 @@
+// Copyright 2020 Nym Technologies SA
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mixmining
+
+import (
+	stdcontext "context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/BorisBorshevsky/timemock"
+	"github.com/nymtech/nym/validator/nym/directory/models"
+)
+
+// worker is a background job of the service, keeping track of how its runs went
+type worker struct {
+	name      string
+	interval  time.Duration
+	immediate bool
+	work      func() error
+
+	lock   sync.Mutex
+	status models.WorkerStatus
+}
+
+func newWorker(name string, interval time.Duration, immediate bool, work func() error) *worker {
+	return &worker{
+		name:      name,
+		interval:  interval,
+		immediate: immediate,
+		work:      work,
+		status:    models.WorkerStatus{Name: name, Interval: int64(interval)},
+	}
+}
+
+// run does the work once, recording when it ran, how long it took and how it went
+func (w *worker) run(work func() error) {
+	started := time.Now()
+	w.lock.Lock()
+	w.status.Running = true
+	w.status.LastRun = timemock.Now().UnixNano()
+	w.lock.Unlock()
+
+	err := work()
+
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	w.status.Running = false
+	w.status.Runs++
+	w.status.LastDuration = int64(time.Since(started))
+	w.status.LastError = ""
+	if err != nil {
+		w.status.LastError = err.Error()
+		fmt.Printf("%s worker failed - %v\n", w.name, err)
+	}
+}
+
+func (w *worker) currentStatus() models.WorkerStatus {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	return w.status
+}
+
+// Start runs the background workers until ctx is done or the service is stopped. A service can only be started once.
+func (service *Service) Start(ctx stdcontext.Context) error {
+	service.lifecycleLock.Lock()
+	defer service.lifecycleLock.Unlock()
+	if service.started {
+		return errors.New("the service was already started")
+	}
+	service.started = true
+
+	ctx, service.cancel = stdcontext.WithCancel(ctx)
+	for _, w := range service.workers {
+		service.runPeriodically(ctx, w)
+	}
+	if service.bridgeWorker != nil {
+		service.running.Add(1)
+		go service.chainBridgeWorker(ctx)
+	}
+	return nil
+}
+
+// Stop stops the background workers, waiting for any busy one to finish what it's doing
+func (service *Service) Stop() {
+	service.lifecycleLock.Lock()
+	if service.cancel != nil {
+		service.cancel()
+	}
+	service.lifecycleLock.Unlock()
+	service.running.Wait()
+}
+
+// WorkerStatuses reports how every background worker is doing
+func (service *Service) WorkerStatuses() []models.WorkerStatus {
+	statuses := make([]models.WorkerStatus, 0, len(service.workers)+1)
+	for _, w := range service.workers {
+		statuses = append(statuses, w.currentStatus())
+	}
+	if service.bridgeWorker != nil {
+		statuses = append(statuses, service.bridgeWorker.currentStatus())
+	}
+	return statuses
+}
+
+// runPeriodically runs the worker every interval, also straight away if it's immediate, until ctx is done
+func (service *Service) runPeriodically(ctx stdcontext.Context, w *worker) {
+	service.running.Add(1)
+	go func() {
+		defer service.running.Done()
+		ticker := time.NewTicker(w.interval)
+		defer ticker.Stop()
+
+		if w.immediate {
+			w.run(w.work)
+		}
+		for {
+			select {
+			case <-ticker.C:
+				w.run(w.work)
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+}
+
+// chainBridgeWorker applies the queued changes until ctx is done, changes still queued by then
+// are picked up by the reconciliation on the next start
+func (service *Service) chainBridgeWorker(ctx stdcontext.Context) {
+	defer service.running.Done()
+	for {
+		select {
+		case task := <-service.bridgeQueue:
+			service.bridgeWorker.run(func() error {
+				if err := task.run(); err != nil {
+					return fmt.Errorf("failed to mirror %s onto the chain: %v", task.description, err)
+				}
+				return nil
+			})
+		case <-ctx.Done():
+			return
+		}
+	}
+}
@@ -0,0 +1,27 @@
+// Copyright 2020 Nym Technologies SA
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+// SchemaVersion records a schema migration applied to the directory database
+type SchemaVersion struct {
+	Version     uint   `json:"version" gorm:"primaryKey"`
+	Description string `json:"description"`
+	AppliedAt   int64  `json:"appliedAt"`
+}
+
+// TableName pins the table name, as the version history must be found whatever gorm would call it
+func (SchemaVersion) TableName() string {
+	return "schema_version"
+}
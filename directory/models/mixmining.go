@@ -31,11 +31,12 @@ type MixStatus struct {
 }
 
 // PersistedMixStatus is a saved MixStatus with a timestamp recording when it
-// was seen by the directory server. It can be used to build visualizations of
-// mixnode uptime.
+// was seen by the directory server, and the identity key of the monitor that reported it.
+// It can be used to build visualizations of mixnode uptime.
 type PersistedMixStatus struct {
 	MixStatus
-	Timestamp int64 `json:"timestamp" binding:"required" gorm:"index:status_index,sort:desc"`
+	Timestamp int64  `json:"timestamp" binding:"required" gorm:"index:status_index,sort:desc"`
+	Monitor   string `json:"monitor"`
 }
 
 // MixStatusReport gives a quick view of mixnode uptime performance
@@ -49,6 +50,11 @@ type MixStatusReport struct {
 	Last5MinutesIPV6 int    `json:"last5MinutesIPV6" binding:"required"`
 	LastHourIPV6     int    `json:"lastHourIPV6" binding:"required"`
 	LastDayIPV6      int    `json:"lastDayIPV6" binding:"required"`
+	// The long term uptimes come from the daily uptimes, they're -1 for nodes that weren't tested at all
+	Last30DaysIPV4 int `json:"last30DaysIPV4" binding:"required" gorm:"-"`
+	Last90DaysIPV4 int `json:"last90DaysIPV4" binding:"required" gorm:"-"`
+	Last30DaysIPV6 int `json:"last30DaysIPV6" binding:"required" gorm:"-"`
+	Last90DaysIPV6 int `json:"last90DaysIPV6" binding:"required" gorm:"-"`
 }
 
 // BatchMixStatus allows to indicate whether given set of nodes is up or down, as reported by a Nym monitor node.
@@ -60,3 +66,64 @@ type BatchMixStatus struct {
 type BatchMixStatusReport struct {
 	Report []MixStatusReport `json:"report" binding:"required"`
 }
+
+// UptimeBucket is the uptime of a node over a period of time
+type UptimeBucket struct {
+	// Start of the bucket, included, in unix nanoseconds
+	Start int64 `json:"start" binding:"required"`
+	// End of the bucket, excluded, in unix nanoseconds
+	End int64 `json:"end" binding:"required"`
+	// Uptime percentage over the bucket, -1 if the node wasn't tested during it
+	Uptime int `json:"uptime" binding:"required"`
+	// Reports is the number of statuses the uptime is computed from
+	Reports int `json:"reports" binding:"required"`
+}
+
+// UptimeSeries charts the uptime of a node, over either IPv4 or IPv6, bucket by bucket
+type UptimeSeries struct {
+	PubKey    string         `json:"pubKey" binding:"required"`
+	IPVersion string         `json:"ipVersion" binding:"required"`
+	Bucket    string         `json:"bucket" binding:"required"`
+	Buckets   []UptimeBucket `json:"buckets" binding:"required"`
+}
+
+// UptimeRollup aggregates the statuses of a node, over either IPv4 or IPv6, reported during a period of time.
+// Rollups outlive the statuses they're computed from.
+type UptimeRollup struct {
+	PubKey    string `json:"pubKey" binding:"required" gorm:"primaryKey"`
+	IPVersion string `json:"ipVersion" binding:"required" gorm:"primaryKey"`
+	// Start of the period in unix nanoseconds
+	Start   int64 `json:"start" binding:"required" gorm:"primaryKey;index"`
+	Reports int   `json:"reports" binding:"required"`
+	// Up is the number of statuses reporting the node as up
+	Up int `json:"up" binding:"required"`
+}
+
+// HourlyUptime is the uptime of a node over an hour
+type HourlyUptime struct {
+	UptimeRollup
+}
+
+// DailyUptime is the uptime of a node over a day
+type DailyUptime struct {
+	UptimeRollup
+}
+
+// the reasons the reputation of a node changes for
+const ReputationChangeMonitorReport = "monitor_report"
+const ReputationChangeAdminOverride = "admin_override"
+const ReputationChangeDecay = "decay"
+
+// ReputationChange is an entry of the reputation ledger, recording a change of the reputation of a node and why it
+// happened
+type ReputationChange struct {
+	ID     uint   `json:"-" gorm:"primaryKey"`
+	PubKey string `json:"pubKey" binding:"required" gorm:"index:reputation_change_index"`
+	// Delta is how much the reputation changed by
+	Delta int64 `json:"delta" binding:"required"`
+	// Reputation is the reputation of the node after the change
+	Reputation int64 `json:"reputation" binding:"required"`
+	// Reason is either monitor_report, admin_override or decay
+	Reason    string `json:"reason" binding:"required"`
+	Timestamp int64  `json:"timestamp" binding:"required" gorm:"index:reputation_change_index,sort:desc"`
+}
@@ -32,7 +32,8 @@ type NodeInfo struct {
 
 type MixRegistrationInfo struct {
 	NodeInfo
-	Layer uint `json:"layer" binding:"required"`
+	// Layer is assigned by the directory. It is no longer required on registration, whatever the node announces being ignored
+	Layer uint `json:"layer"`
 }
 
 type RegisteredMix struct {
@@ -0,0 +1,30 @@
+// Copyright 2020 Nym Technologies SA
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+// WorkerStatus shows how a background worker of the directory is doing
+type WorkerStatus struct {
+	Name string `json:"name" binding:"required"`
+	// Interval between two runs in nanoseconds, 0 for workers that run whenever they have work queued
+	Interval int64  `json:"interval" binding:"required"`
+	Running  bool   `json:"running" binding:"required"`
+	Runs     uint64 `json:"runs" binding:"required"`
+	// LastRun is the timestamp the last run started at, 0 if the worker hasn't run yet
+	LastRun int64 `json:"lastRun" binding:"required"`
+	// LastDuration of the last run, in nanoseconds
+	LastDuration int64 `json:"lastDuration" binding:"required"`
+	// LastError is the error the last run failed with, empty if it succeeded
+	LastError string `json:"lastError"`
+}
@@ -0,0 +1,79 @@
+// Copyright 2020 Nym Technologies SA
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/nymtech/nym/validator/nym/directory/mixmining"
+)
+
+// DefaultShutdownTimeout is how long in-flight requests get to finish on shutdown
+const DefaultShutdownTimeout = time.Second * 10
+
+// Server is the directory http server along with the database and services behind it
+type Server struct {
+	http            *http.Server
+	db              *mixmining.Db
+	service         *mixmining.Service
+	shutdownTimeout time.Duration
+}
+
+// Run starts the background workers and serves the directory until ctx is done, then stops accepting
+// connections, waits up to the shutdown timeout for in-flight requests to finish, and stops the workers.
+func (server *Server) Run(ctx context.Context) error {
+	// the workers outlive ctx so that requests still being drained get served by a working service
+	if err := server.service.Start(context.Background()); err != nil {
+		server.close()
+		return fmt.Errorf("failed to start the directory service: %v", err)
+	}
+
+	failed := make(chan error, 1)
+	go func() {
+		fmt.Printf("directory listening on %s\n", server.http.Addr)
+		if err := server.http.ListenAndServe(); err != http.ErrServerClosed {
+			failed <- err
+		}
+	}()
+
+	select {
+	case err := <-failed:
+		server.close()
+		return fmt.Errorf("directory server failed: %v", err)
+	case <-ctx.Done():
+	}
+
+	fmt.Println("shutting down the directory, draining in-flight requests")
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), server.shutdownTimeout)
+	defer cancel()
+	err := server.http.Shutdown(shutdownCtx)
+	server.close()
+	if err != nil {
+		return fmt.Errorf("directory shutdown did not complete: %v", err)
+	}
+	fmt.Println("directory stopped")
+	return nil
+}
+
+// close stops the background workers, then releases the database they use
+func (server *Server) close() {
+	server.service.Stop()
+	if err := server.db.Close(); err != nil {
+		fmt.Printf("failed to close the database - %v\n", err)
+	}
+}
@@ -15,9 +15,12 @@
 package server
 
 import (
+	"fmt"
 	"github.com/cosmos/cosmos-sdk/client/context"
 	"net/http"
 
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
 	"github.com/microcosm-cc/bluemonday"
@@ -35,7 +38,7 @@ import (
 // @termsOfService http://swagger.io/terms/
 // @license.name Apache 2.0
 // @license.url https://github.com/nymtech/nym-validator/license
-func New(cliCtx context.CLIContext) *gin.Engine {
+func New(cliCtx context.CLIContext, cfg Config) (*Server, error) {
 	gin.SetMode(gin.ReleaseMode)
 	
 	// Set the router as the default one shipped with Gin
@@ -50,7 +53,7 @@ func New(cliCtx context.CLIContext) *gin.Engine {
 	// Add HTML templates to the router
 	t, err := html.LoadTemplate()
 	if err != nil {
-		panic(err)
+		return nil, fmt.Errorf("failed to load html templates: %v", err)
 	}
 	router.SetHTMLTemplate(t)
 	router.GET("/", func(c *gin.Context) {
@@ -60,27 +63,104 @@ func New(cliCtx context.CLIContext) *gin.Engine {
 	// Sanitize controller input against XSS attacks using bluemonday.Policy
 	policy := bluemonday.UGCPolicy()
 
+	// with the bridge enabled, nodes are only accepted under the rules of the chain they get mirrored onto
+	bridge := newChainBridge(cliCtx, cfg.Bridge)
+	if bridge != nil {
+		params, err := bridge.Params()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read the nym params from the chain: %v", err)
+		}
+		cfg = cfg.withChainParams(params)
+	}
+
 	// Measurements: wire up dependency injection
-	measurementsCfg := injectMeasurements(policy, cliCtx)
+	db, err := openDb(cfg.Database)
+	if err != nil {
+		return nil, err
+	}
+	service := mixmining.NewService(db, cliCtx, cfg.Service)
+	if bridge != nil {
+		service.EnableChainBridge(bridge)
+	}
+	measurementsCfg := injectMeasurements(policy, service, cfg)
+	if len(cfg.Auth.Monitors) == 0 {
+		fmt.Println("no network monitor keys configured, mix statuses will be rejected")
+	}
 
 	// Register all HTTP controller routes
 	healthcheck.New().RegisterRoutes(router)
 	mixmining.New(measurementsCfg).RegisterRoutes(router)
 
-	return router
+	return &Server{
+		http: &http.Server{
+			Addr:    cfg.ListenAddress,
+			Handler: router,
+		},
+		db:              db,
+		service:         service,
+		shutdownTimeout: cfg.ShutdownTimeout,
+	}, nil
 }
 
-func injectMeasurements(policy *bluemonday.Policy, cliCtx context.CLIContext) mixmining.Config {
+// openDb connects to the directory database and brings its schema up to date
+func openDb(dsn string) (*mixmining.Db, error) {
+	db, err := mixmining.OpenDb(dsn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open the database: %v", err)
+	}
+	applied, err := db.Migrate(mixmining.LatestSchemaVersion())
+	for _, migration := range applied {
+		fmt.Printf("applied database migration %d: %s\n", migration.Version, migration.Description)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to migrate the database: %v", err)
+	}
+	return db, nil
+}
+
+func injectMeasurements(policy *bluemonday.Policy, service mixmining.IService, cfg Config) mixmining.Config {
 	sanitizer := mixmining.NewSanitizer(policy)
 	batchSanitizer := mixmining.NewBatchSanitizer(policy)
 	genericSanitizer := mixmining.NewGenericSanitizer(policy)
-	db := mixmining.NewDb(false)
-	mixminingService := *mixmining.NewService(db, cliCtx, false)
 
 	return mixmining.Config{
-		Service:   &mixminingService,
+		Service:   service,
 		Sanitizer: sanitizer,
 		GenericSanitizer: genericSanitizer,
 		BatchSanitizer: batchSanitizer,
+		Registration: cfg.Registration,
+		RateLimits: cfg.RateLimits,
+		Auth: cfg.Auth,
+	}
+}
+
+// newChainBridge returns the configured chain bridge, or nil when it's disabled or can't be set up
+func newChainBridge(cliCtx context.CLIContext, cfg BridgeConfig) *mixmining.ChainBridge {
+	if cfg.Key == "" {
+		return nil
+	}
+
+	bridgeCfg := mixmining.ChainBridgeConfig{
+		KeyName:        cfg.Key,
+		KeyringBackend: cfg.KeyringBackend,
+		Home:           valueOrDefault(cfg.Home, cliCtx.HomeDir),
+		ChainID:        valueOrDefault(cfg.ChainID, cliCtx.ChainID),
+		Fees:           cfg.Fees,
+	}
+	// the bond was validated with the rest of the config
+	bridgeCfg.Bond, _ = sdk.ParseCoin(cfg.Bond)
+
+	bridge, err := mixmining.NewChainBridge(cliCtx, bridgeCfg)
+	if err != nil {
+		fmt.Printf("chain bridge disabled - %v\n", err)
+		return nil
+	}
+	return bridge
+}
+
+func valueOrDefault(value string, def string) string {
+	if value != "" {
+		return value
 	}
+	return def
 }
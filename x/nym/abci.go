@@ -27,7 +27,27 @@ func BeginBlocker(ctx sdk.Context, req abci.RequestBeginBlock, k keeper.Keeper)
 	// 	TODO: fill out if your application requires beginblock, if not you can delete this function
 }
 
-// EndBlocker called every block, process inflation, update validator set.
+// EndBlocker called every block, distributes mixnode rewards on the last block of every epoch, prunes the uptime
+// reports they were based on and, if enabled, rebalances the mixnet layers once they are paid.
 func EndBlocker(ctx sdk.Context, k keeper.Keeper) {
-	// 	TODO: fill out if your application requires endblock, if not you can delete this function
+	if !k.IsEpochEnd(ctx) {
+		return
+	}
+
+	// distribute within a cached context so that a failed payout does not leave the rewards partially paid
+	epoch := k.CurrentEpoch(ctx)
+	cacheCtx, write := ctx.CacheContext()
+	if err := k.DistributeRewards(cacheCtx, epoch); err != nil {
+		k.Logger(ctx).Error("failed to distribute rewards", "epoch", epoch, "err", err)
+	} else {
+		write()
+		ctx.EventManager().EmitEvents(cacheCtx.EventManager().Events())
+	}
+	// the reports of a finished epoch are never used again, even if its rewards could not be paid
+	k.RemoveEpochUptimeReports(ctx, epoch)
+
+	if k.GetParams(ctx).RebalanceLayers {
+		moves := k.RebalanceLayers(ctx)
+		k.Logger(ctx).Info("rebalanced mixnet layers", "epoch", epoch, "moves", len(moves))
+	}
 }
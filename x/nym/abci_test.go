@@ -0,0 +1,185 @@
+// Copyright 2020 Nym Technologies SA
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nym
+
+import (
+	"testing"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/nymtech/nym/validator/nym/x/nym/keeper"
+	"github.com/nymtech/nym/validator/nym/x/nym/types"
+)
+
+// setupRewards returns a handler with 10 blocks epochs rewarding the given amount, the mixnode "node" bonded by the owner
+// with a delegation of 100nym and the mixnode "other" bonded by the thief
+func setupRewards(t *testing.T, reward int64) (sdk.Context, keeper.Keeper, sdk.Handler, *testSupplyKeeper) {
+	ctx, k, handler, supplyKeeper := setupDelegation(t)
+	params := k.GetParams(ctx)
+	params.EpochLength = 10
+	params.EpochReward = nymCoin(reward)
+	params.NetworkMonitors = []sdk.AccAddress{monitor}
+	k.SetParams(ctx, params)
+
+	_, err := handler(ctx, createMixnodeMsg(thief, "other", otherIdentityKey))
+	require.NoError(t, err)
+	_, err = handler(ctx, types.NewMsgDelegate("node", delegator, nymCoin(100)))
+	require.NoError(t, err)
+	return ctx, k, handler, supplyKeeper
+}
+
+// reportUptimes submits the uptime of the mixnodes "node" and "other" during the current epoch
+func reportUptimes(t *testing.T, ctx sdk.Context, handler sdk.Handler, nodeUptime int32, otherUptime int32) {
+	_, err := handler(ctx, types.NewMsgSubmitUptimeReport(monitor, []types.NodeUptime{
+		{NodeID: "node", IPV4Uptime: nodeUptime, IPV6Uptime: nodeUptime},
+		{NodeID: "other", IPV4Uptime: otherUptime, IPV6Uptime: otherUptime},
+	}))
+	require.NoError(t, err)
+}
+
+// endEpoch runs the end blocker on the last block of the first epoch, returning the events it emitted
+func endEpoch(ctx sdk.Context, k keeper.Keeper) sdk.Events {
+	ctx = ctx.WithBlockHeight(9).WithEventManager(sdk.NewEventManager())
+	EndBlocker(ctx, k)
+	return ctx.EventManager().Events()
+}
+
+func rewardEvents(events sdk.Events) sdk.Events {
+	var rewards sdk.Events
+	for _, event := range events {
+		if event.Type == types.EventTypeReward {
+			rewards = append(rewards, event)
+		}
+	}
+	return rewards
+}
+
+func TestEpochBoundaries(t *testing.T) {
+	ctx, k, _, _ := setupRewards(t, 1000)
+
+	assert.False(t, k.IsEpochEnd(ctx.WithBlockHeight(8)))
+	assert.True(t, k.IsEpochEnd(ctx.WithBlockHeight(9)))
+	assert.False(t, k.IsEpochEnd(ctx.WithBlockHeight(10)))
+	assert.True(t, k.IsEpochEnd(ctx.WithBlockHeight(19)))
+	assert.Equal(t, int64(0), k.CurrentEpoch(ctx.WithBlockHeight(9)))
+	assert.Equal(t, int64(1), k.CurrentEpoch(ctx.WithBlockHeight(10)))
+}
+
+func TestEndBlockerOnlyRewardsAtTheEndOfEpochs(t *testing.T) {
+	ctx, k, handler, supplyKeeper := setupRewards(t, 1000)
+	reportUptimes(t, ctx, handler, 100, 50)
+
+	ctx = ctx.WithBlockHeight(8).WithEventManager(sdk.NewEventManager())
+	EndBlocker(ctx, k)
+	assert.Empty(t, ctx.EventManager().Events())
+	assert.Equal(t, nymCoins(900), supplyKeeper.balance(ctx, owner))
+	assert.Equal(t, nymCoins(300), supplyKeeper.balance(ctx, moduleAddress))
+
+	assert.Len(t, rewardEvents(endEpoch(ctx, k)), 3)
+}
+
+func TestEndBlockerSplitsRewardsByUptimeAndStake(t *testing.T) {
+	ctx, k, handler, supplyKeeper := setupRewards(t, 1000)
+	// coins of another denomination are not counted towards the stake of the mixnode
+	k.SetDelegation(ctx, types.NewDelegation("node", thief, sdk.NewInt64Coin("stake", 1000)))
+	reportUptimes(t, ctx, handler, 100, 50)
+
+	events := rewardEvents(endEpoch(ctx, k))
+
+	// "node" weighs 200nym at full uptime and "other" 100nym at half uptime, so the reward is split 4:1
+	// and "node" shares its 800nym equally between its operator and delegator
+	assert.Equal(t, nymCoins(900+400), supplyKeeper.balance(ctx, owner))
+	assert.Equal(t, nymCoins(900+400), supplyKeeper.balance(ctx, delegator))
+	assert.Equal(t, nymCoins(900+200), supplyKeeper.balance(ctx, thief))
+	assert.Equal(t, nymCoins(300), supplyKeeper.balance(ctx, moduleAddress))
+	require.Len(t, events, 3)
+	assert.Equal(t, sdk.NewEvent(types.EventTypeReward,
+		sdk.NewAttribute(types.AttributeKeyEpoch, "0"),
+		sdk.NewAttribute(types.AttributeKeyMixnodeID, "node"),
+		sdk.NewAttribute(types.AttributeKeyRecipient, owner.String()),
+		sdk.NewAttribute(sdk.AttributeKeyAmount, nymCoin(400).String()),
+	), events[0])
+}
+
+func TestEndBlockerOnlyMintsTheRewardsPaidOut(t *testing.T) {
+	ctx, k, handler, supplyKeeper := setupRewards(t, 7)
+	reportUptimes(t, ctx, handler, 100, 50)
+
+	endEpoch(ctx, k)
+
+	// 2.8nym, 2.8nym and 1.4nym are truncated and the dust is never minted
+	assert.Equal(t, nymCoins(900+2), supplyKeeper.balance(ctx, owner))
+	assert.Equal(t, nymCoins(900+2), supplyKeeper.balance(ctx, delegator))
+	assert.Equal(t, nymCoins(900+1), supplyKeeper.balance(ctx, thief))
+	assert.Equal(t, nymCoins(300), supplyKeeper.balance(ctx, moduleAddress))
+}
+
+func TestEndBlockerDoesNotRewardMixnodesWithoutUptime(t *testing.T) {
+	ctx, k, handler, supplyKeeper := setupRewards(t, 1000)
+	reportUptimes(t, ctx, handler, 100, 0)
+
+	endEpoch(ctx, k)
+
+	assert.Equal(t, nymCoins(900+500), supplyKeeper.balance(ctx, owner))
+	assert.Equal(t, nymCoins(900+500), supplyKeeper.balance(ctx, delegator))
+	assert.Equal(t, nymCoins(900), supplyKeeper.balance(ctx, thief))
+}
+
+func TestEndBlockerDoesNotMintWithoutReports(t *testing.T) {
+	ctx, k, _, supplyKeeper := setupRewards(t, 1000)
+
+	assert.Empty(t, rewardEvents(endEpoch(ctx, k)))
+	assert.Equal(t, nymCoins(900), supplyKeeper.balance(ctx, owner))
+	assert.Equal(t, nymCoins(900), supplyKeeper.balance(ctx, delegator))
+	assert.Equal(t, nymCoins(900), supplyKeeper.balance(ctx, thief))
+	assert.Equal(t, nymCoins(300), supplyKeeper.balance(ctx, moduleAddress))
+}
+
+func TestEndBlockerRollsBackFailedDistributions(t *testing.T) {
+	ctx, k, handler, supplyKeeper := setupRewards(t, 1000)
+	reportUptimes(t, ctx, handler, 100, 50)
+	supplyKeeper.failingRecipient = delegator
+
+	assert.Empty(t, rewardEvents(endEpoch(ctx, k)))
+
+	// the operator was paid before the payout to the delegator failed, but none of it is kept
+	assert.Equal(t, nymCoins(900), supplyKeeper.balance(ctx, owner))
+	assert.Equal(t, nymCoins(900), supplyKeeper.balance(ctx, delegator))
+	assert.Equal(t, nymCoins(900), supplyKeeper.balance(ctx, thief))
+	assert.Equal(t, nymCoins(300), supplyKeeper.balance(ctx, moduleAddress))
+}
+
+func TestEndBlockerPrunesTheReportsOfTheFinishedEpoch(t *testing.T) {
+	ctx, k, handler, _ := setupRewards(t, 1000)
+	reportUptimes(t, ctx, handler, 100, 50)
+	reportUptimes(t, ctx.WithBlockHeight(10), handler, 100, 100)
+
+	endEpoch(ctx, k)
+
+	assert.Empty(t, k.GetEpochUptimeReports(ctx, 0))
+	assert.Len(t, k.GetEpochUptimeReports(ctx, 1), 2)
+}
+
+func TestEndBlockerPrunesTheReportsOfFailedDistributions(t *testing.T) {
+	ctx, k, handler, supplyKeeper := setupRewards(t, 1000)
+	reportUptimes(t, ctx, handler, 100, 50)
+	supplyKeeper.failingRecipient = delegator
+
+	endEpoch(ctx, k)
+
+	assert.Empty(t, k.GetEpochUptimeReports(ctx, 0))
+}
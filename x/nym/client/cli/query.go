@@ -46,8 +46,17 @@ func GetQueryCmd(queryRoute string, cdc *codec.Codec) *cobra.Command {
 			// this line is used by starport scaffolding # 1
 			GetCmdListGateway(queryRoute, cdc),
 			GetCmdGetGateway(queryRoute, cdc),
+			GetCmdGetGatewayByIdentity(queryRoute, cdc),
 			GetCmdListMixnode(queryRoute, cdc),
 			GetCmdGetMixnode(queryRoute, cdc),
+			GetCmdGetMixnodeByIdentity(queryRoute, cdc),
+			GetCmdListMixnodeDelegations(queryRoute, cdc),
+			GetCmdListDelegatorDelegations(queryRoute, cdc),
+			GetCmdGetUptime(queryRoute, cdc),
+			GetCmdGetReputation(queryRoute, cdc),
+			GetCmdTopology(queryRoute, cdc),
+			GetCmdLayerRebalance(queryRoute, cdc),
+			GetCmdQueryParams(queryRoute, cdc),
 		)...,
 	)
 
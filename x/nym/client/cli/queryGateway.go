@@ -4,18 +4,35 @@ import (
 	"fmt"
 
 	"github.com/cosmos/cosmos-sdk/client/context"
+	"github.com/cosmos/cosmos-sdk/client/flags"
 	"github.com/cosmos/cosmos-sdk/codec"
 	"github.com/nymtech/nym/validator/nym/x/nym/types"
 	"github.com/spf13/cobra"
 )
 
 func GetCmdListGateway(queryRoute string, cdc *codec.Codec) *cobra.Command {
-	return &cobra.Command{
-		Use:   "list-gateway",
-		Short: "list all gateway",
+	cmd := &cobra.Command{
+		Use:     "list-gateway",
+		Short:   "list gateways, optionally filtered by location or creator",
+		Example: fmt.Sprintf("$ nymcli query %s list-gateway --location Neuchatel --page 2 --limit 50", types.ModuleName),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			cliCtx := context.NewCLIContext().WithCodec(cdc)
-			res, _, err := cliCtx.QueryWithData(fmt.Sprintf("custom/%s/"+types.QueryListGateway, queryRoute), nil)
+
+			page, _ := cmd.Flags().GetInt(flags.FlagPage)
+			limit, _ := cmd.Flags().GetInt(flags.FlagLimit)
+			location, _ := cmd.Flags().GetString(flagLocation)
+			creator, err := parseCreatorFlag(cmd)
+			if err != nil {
+				return err
+			}
+
+			params := types.NewQueryGatewaysParams(page, limit, location, creator)
+			bz, err := cdc.MarshalJSON(params)
+			if err != nil {
+				return err
+			}
+
+			res, _, err := cliCtx.QueryWithData(fmt.Sprintf("custom/%s/"+types.QueryListGateway, queryRoute), bz)
 			if err != nil {
 				fmt.Printf("could not list Gateway\n%s\n", err.Error())
 				return nil
@@ -25,6 +42,12 @@ func GetCmdListGateway(queryRoute string, cdc *codec.Codec) *cobra.Command {
 			return cliCtx.PrintOutput(out)
 		},
 	}
+
+	cmd.Flags().Int(flags.FlagPage, 1, "pagination page of gateways to query for")
+	cmd.Flags().Int(flags.FlagLimit, types.DefaultQueryLimit, fmt.Sprintf("pagination limit of gateways to query for, at most %d", types.MaxQueryLimit))
+	cmd.Flags().String(flagLocation, "", "only list gateways in the given location")
+	cmd.Flags().String(flagCreator, "", "only list gateways created by the given address")
+	return cmd
 }
 
 func GetCmdGetGateway(queryRoute string, cdc *codec.Codec) *cobra.Command {
@@ -49,3 +72,26 @@ func GetCmdGetGateway(queryRoute string, cdc *codec.Codec) *cobra.Command {
 		},
 	}
 }
+
+func GetCmdGetGatewayByIdentity(queryRoute string, cdc *codec.Codec) *cobra.Command {
+	return &cobra.Command{
+		Use:   "get-gateway-by-identity [identityKey]",
+		Short: "Query a gateway by its identity key",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cliCtx := context.NewCLIContext().WithCodec(cdc)
+			key := args[0]
+
+			res, _, err := cliCtx.QueryWithData(fmt.Sprintf("custom/%s/%s/%s", queryRoute, types.QueryGetGatewayByIdentity, key), nil)
+			if err != nil {
+				fmt.Printf("could not resolve gateway with identity %s \n%s\n", key, err.Error())
+
+				return nil
+			}
+
+			var out types.Gateway
+			cdc.MustUnmarshalJSON(res, &out)
+			return cliCtx.PrintOutput(out)
+		},
+	}
+}
@@ -18,18 +18,45 @@ import (
 	"fmt"
 
 	"github.com/cosmos/cosmos-sdk/client/context"
+	"github.com/cosmos/cosmos-sdk/client/flags"
 	"github.com/cosmos/cosmos-sdk/codec"
+	sdk "github.com/cosmos/cosmos-sdk/types"
 	"github.com/nymtech/nym/validator/nym/x/nym/types"
 	"github.com/spf13/cobra"
 )
 
+const (
+	flagLayer    = "layer"
+	flagLocation = "location"
+	flagVersion  = "version"
+	flagCreator  = "creator"
+)
+
 func GetCmdListMixnode(queryRoute string, cdc *codec.Codec) *cobra.Command {
-	return &cobra.Command{
-		Use:   "list-mixnode",
-		Short: "list all mixnode",
+	cmd := &cobra.Command{
+		Use:     "list-mixnode",
+		Short:   "list mixnodes, optionally filtered by layer, location, version or creator",
+		Example: fmt.Sprintf("$ nymcli query %s list-mixnode --layer 2 --page 2 --limit 50", types.ModuleName),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			cliCtx := context.NewCLIContext().WithCodec(cdc)
-			res, _, err := cliCtx.QueryWithData(fmt.Sprintf("custom/%s/"+types.QueryListMixnode, queryRoute), nil)
+
+			page, _ := cmd.Flags().GetInt(flags.FlagPage)
+			limit, _ := cmd.Flags().GetInt(flags.FlagLimit)
+			layer, _ := cmd.Flags().GetInt32(flagLayer)
+			location, _ := cmd.Flags().GetString(flagLocation)
+			version, _ := cmd.Flags().GetString(flagVersion)
+			creator, err := parseCreatorFlag(cmd)
+			if err != nil {
+				return err
+			}
+
+			params := types.NewQueryMixnodesParams(page, limit, layer, location, version, creator)
+			bz, err := cdc.MarshalJSON(params)
+			if err != nil {
+				return err
+			}
+
+			res, _, err := cliCtx.QueryWithData(fmt.Sprintf("custom/%s/"+types.QueryListMixnode, queryRoute), bz)
 			if err != nil {
 				fmt.Printf("could not list Mixnode\n%s\n", err.Error())
 				return nil
@@ -39,6 +66,23 @@ func GetCmdListMixnode(queryRoute string, cdc *codec.Codec) *cobra.Command {
 			return cliCtx.PrintOutput(out)
 		},
 	}
+
+	cmd.Flags().Int(flags.FlagPage, 1, "pagination page of mixnodes to query for")
+	cmd.Flags().Int(flags.FlagLimit, types.DefaultQueryLimit, fmt.Sprintf("pagination limit of mixnodes to query for, at most %d", types.MaxQueryLimit))
+	cmd.Flags().Int32(flagLayer, 0, "only list mixnodes in the given layer")
+	cmd.Flags().String(flagLocation, "", "only list mixnodes in the given location")
+	cmd.Flags().String(flagVersion, "", "only list mixnodes running the given version")
+	cmd.Flags().String(flagCreator, "", "only list mixnodes created by the given address")
+	return cmd
+}
+
+// parseCreatorFlag returns the address passed with the creator flag, if any
+func parseCreatorFlag(cmd *cobra.Command) (sdk.AccAddress, error) {
+	creator, _ := cmd.Flags().GetString(flagCreator)
+	if creator == "" {
+		return nil, nil
+	}
+	return sdk.AccAddressFromBech32(creator)
 }
 
 func GetCmdGetMixnode(queryRoute string, cdc *codec.Codec) *cobra.Command {
@@ -63,3 +107,26 @@ func GetCmdGetMixnode(queryRoute string, cdc *codec.Codec) *cobra.Command {
 		},
 	}
 }
+
+func GetCmdGetMixnodeByIdentity(queryRoute string, cdc *codec.Codec) *cobra.Command {
+	return &cobra.Command{
+		Use:   "get-mixnode-by-identity [identityKey]",
+		Short: "Query a mixnode by its identity key",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cliCtx := context.NewCLIContext().WithCodec(cdc)
+			key := args[0]
+
+			res, _, err := cliCtx.QueryWithData(fmt.Sprintf("custom/%s/%s/%s", queryRoute, types.QueryGetMixnodeByIdentity, key), nil)
+			if err != nil {
+				fmt.Printf("could not resolve mixnode with identity %s \n%s\n", key, err.Error())
+
+				return nil
+			}
+
+			var out types.Mixnode
+			cdc.MustUnmarshalJSON(res, &out)
+			return cliCtx.PrintOutput(out)
+		},
+	}
+}
@@ -0,0 +1,55 @@
+// Copyright 2020 Nym Technologies SA
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"fmt"
+
+	"github.com/cosmos/cosmos-sdk/client/context"
+	"github.com/cosmos/cosmos-sdk/codec"
+	"github.com/nymtech/nym/validator/nym/x/nym/types"
+	"github.com/spf13/cobra"
+)
+
+const flagActive = "active"
+
+func GetCmdTopology(queryRoute string, cdc *codec.Codec) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "topology",
+		Short: "Query the network topology in the directory format",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cliCtx := context.NewCLIContext().WithCodec(cdc)
+
+			query := types.QueryTopology
+			if active, _ := cmd.Flags().GetBool(flagActive); active {
+				query = types.QueryActiveTopology
+			}
+
+			res, _, err := cliCtx.QueryWithData(fmt.Sprintf("custom/%s/%s", queryRoute, query), nil)
+			if err != nil {
+				fmt.Printf("could not resolve topology\n%s\n", err.Error())
+				return nil
+			}
+
+			// the topology is already encoded in the directory's json format
+			fmt.Println(string(res))
+			return nil
+		},
+	}
+
+	cmd.Flags().Bool(flagActive, false, "only include nodes whose reputation reached the reputation threshold")
+	return cmd
+}
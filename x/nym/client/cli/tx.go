@@ -43,6 +43,9 @@ func GetTxCmd(cdc *codec.Codec) *cobra.Command {
 		GetCmdCreateMixnode(cdc),
 		GetCmdSetMixnode(cdc),
 		GetCmdDeleteMixnode(cdc),
+		GetCmdDelegate(cdc),
+		GetCmdUndelegate(cdc),
+		GetCmdSubmitUptimeReport(cdc),
 	)...)
 
 	return nymTxCmd
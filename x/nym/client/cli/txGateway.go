@@ -29,21 +29,25 @@ import (
 
 func GetCmdCreateGateway(cdc *codec.Codec) *cobra.Command {
 	return &cobra.Command{
-		Use:   "create-gateway [identityKey] [sphinxKey] [clientListener] [mixnetListener] [location]",
+		Use:   "create-gateway [identityKey] [sphinxKey] [clientListener] [mixnetListener] [location] [bond]",
 		Short: "Creates a new gateway",
-		Args:  cobra.MinimumNArgs(5),
+		Args:  cobra.ExactArgs(6),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			argsIdentityKey := string(args[0])
 			argsSphinxKey := string(args[1])
 			argsClientListener := string(args[2])
 			argsMixnetListener := string(args[3])
 			argsLocation := string(args[4])
+			argsBond, err := sdk.ParseCoin(args[5])
+			if err != nil {
+				return err
+			}
 
 			cliCtx := context.NewCLIContext().WithCodec(cdc)
 			inBuf := bufio.NewReader(cmd.InOrStdin())
 			txBldr := auth.NewTxBuilderFromCLI(inBuf).WithTxEncoder(utils.GetTxEncoder(cdc))
-			msg := types.NewMsgCreateGateway(cliCtx.GetFromAddress(), string(argsIdentityKey), string(argsSphinxKey), string(argsClientListener), string(argsMixnetListener), string(argsLocation))
-			err := msg.ValidateBasic()
+			msg := types.NewMsgCreateGateway(cliCtx.GetFromAddress(), string(argsIdentityKey), string(argsSphinxKey), string(argsClientListener), string(argsMixnetListener), string(argsLocation), argsBond)
+			err = msg.ValidateBasic()
 			if err != nil {
 				return err
 			}
@@ -54,9 +58,9 @@ func GetCmdCreateGateway(cdc *codec.Codec) *cobra.Command {
 
 func GetCmdSetGateway(cdc *codec.Codec) *cobra.Command {
 	return &cobra.Command{
-		Use:   "set-gateway [id]  [identityKey] [sphinxKey] [clientListener] [mixnetListener] [location]",
+		Use:   "set-gateway [id] [identityKey] [sphinxKey] [clientListener] [mixnetListener] [location]",
 		Short: "Set a new gateway",
-		Args:  cobra.MinimumNArgs(5),
+		Args:  cobra.ExactArgs(6),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			id := args[0]
 			argsIdentityKey := string(args[1])
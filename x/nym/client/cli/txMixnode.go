@@ -16,6 +16,7 @@ package cli
 
 import (
 	"bufio"
+	"fmt"
 	"strconv"
 
 	"github.com/spf13/cobra"
@@ -30,22 +31,29 @@ import (
 
 func GetCmdCreateMixnode(cdc *codec.Codec) *cobra.Command {
 	return &cobra.Command{
-		Use:   "create-mixnode [pubKey] [layer] [version] [host] [location] [reputation]",
+		Use:   "create-mixnode [pubKey] [sphinxKey] [layer] [version] [host] [location] [bond]",
 		Short: "Creates a new mixnode",
-		Args:  cobra.MinimumNArgs(6),
+		Args:  cobra.ExactArgs(7),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			argsPubKey := string(args[0])
-			argsLayer, _ := strconv.ParseInt(args[1], 10, 64)
-			argsVersion := string(args[2])
-			argsHost := string(args[3])
-			argsLocation := string(args[4])
-			argsReputation, _ := strconv.ParseInt(args[5], 10, 64)
+			argsSphinxKey := string(args[1])
+			argsLayer, err := strconv.ParseInt(args[2], 10, 32)
+			if err != nil {
+				return fmt.Errorf("invalid layer %q: %w", args[2], err)
+			}
+			argsVersion := string(args[3])
+			argsHost := string(args[4])
+			argsLocation := string(args[5])
+			argsBond, err := sdk.ParseCoin(args[6])
+			if err != nil {
+				return err
+			}
 
 			cliCtx := context.NewCLIContext().WithCodec(cdc)
 			inBuf := bufio.NewReader(cmd.InOrStdin())
 			txBldr := auth.NewTxBuilderFromCLI(inBuf).WithTxEncoder(utils.GetTxEncoder(cdc))
-			msg := types.NewMsgCreateMixnode(cliCtx.GetFromAddress(), string(argsPubKey), int32(argsLayer), string(argsVersion), string(argsHost), string(argsLocation), int32(argsReputation))
-			err := msg.ValidateBasic()
+			msg := types.NewMsgCreateMixnode(cliCtx.GetFromAddress(), string(argsPubKey), string(argsSphinxKey), int32(argsLayer), string(argsVersion), string(argsHost), string(argsLocation), argsBond)
+			err = msg.ValidateBasic()
 			if err != nil {
 				return err
 			}
@@ -56,23 +64,26 @@ func GetCmdCreateMixnode(cdc *codec.Codec) *cobra.Command {
 
 func GetCmdSetMixnode(cdc *codec.Codec) *cobra.Command {
 	return &cobra.Command{
-		Use:   "set-mixnode [id]  [pubKey] [layer] [version] [host] [location] [reputation]",
+		Use:   "set-mixnode [id] [pubKey] [sphinxKey] [layer] [version] [host] [location]",
 		Short: "Set a new mixnode",
-		Args:  cobra.MinimumNArgs(6),
+		Args:  cobra.ExactArgs(7),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			id := args[0]
 			argsPubKey := string(args[1])
-			argsLayer, _ := strconv.ParseInt(args[2], 10, 64)
-			argsVersion := string(args[3])
-			argsHost := string(args[4])
-			argsLocation := string(args[5])
-			argsReputation, _ := strconv.ParseInt(args[6], 10, 64)
+			argsSphinxKey := string(args[2])
+			argsLayer, err := strconv.ParseInt(args[3], 10, 32)
+			if err != nil {
+				return fmt.Errorf("invalid layer %q: %w", args[3], err)
+			}
+			argsVersion := string(args[4])
+			argsHost := string(args[5])
+			argsLocation := string(args[6])
 
 			cliCtx := context.NewCLIContext().WithCodec(cdc)
 			inBuf := bufio.NewReader(cmd.InOrStdin())
 			txBldr := auth.NewTxBuilderFromCLI(inBuf).WithTxEncoder(utils.GetTxEncoder(cdc))
-			msg := types.NewMsgSetMixnode(cliCtx.GetFromAddress(), id, string(argsPubKey), int32(argsLayer), string(argsVersion), string(argsHost), string(argsLocation), int32(argsReputation))
-			err := msg.ValidateBasic()
+			msg := types.NewMsgSetMixnode(cliCtx.GetFromAddress(), id, string(argsPubKey), string(argsSphinxKey), int32(argsLayer), string(argsVersion), string(argsHost), string(argsLocation))
+			err = msg.ValidateBasic()
 			if err != nil {
 				return err
 			}
@@ -0,0 +1,75 @@
+// Copyright 2020 Nym Technologies SA
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"bufio"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/cosmos/cosmos-sdk/client/context"
+	"github.com/cosmos/cosmos-sdk/codec"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/x/auth"
+	"github.com/cosmos/cosmos-sdk/x/auth/client/utils"
+	"github.com/nymtech/nym/validator/nym/x/nym/types"
+)
+
+func GetCmdSubmitUptimeReport(cdc *codec.Codec) *cobra.Command {
+	return &cobra.Command{
+		Use:   "submit-uptime-report [nodeId:ipv4Uptime:ipv6Uptime]...",
+		Short: "Submit the uptime of nodes measured during the current epoch",
+		Args:  cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			reports := make([]types.NodeUptime, 0, len(args))
+			for _, arg := range args {
+				report, err := parseNodeUptime(arg)
+				if err != nil {
+					return err
+				}
+				reports = append(reports, report)
+			}
+
+			cliCtx := context.NewCLIContext().WithCodec(cdc)
+			inBuf := bufio.NewReader(cmd.InOrStdin())
+			txBldr := auth.NewTxBuilderFromCLI(inBuf).WithTxEncoder(utils.GetTxEncoder(cdc))
+			msg := types.NewMsgSubmitUptimeReport(cliCtx.GetFromAddress(), reports)
+			err := msg.ValidateBasic()
+			if err != nil {
+				return err
+			}
+			return utils.GenerateOrBroadcastMsgs(cliCtx, txBldr, []sdk.Msg{msg})
+		},
+	}
+}
+
+func parseNodeUptime(arg string) (types.NodeUptime, error) {
+	parts := strings.Split(arg, ":")
+	if len(parts) != 3 {
+		return types.NodeUptime{}, fmt.Errorf("invalid uptime report %q, expected nodeId:ipv4Uptime:ipv6Uptime", arg)
+	}
+	ipv4Uptime, err := strconv.ParseInt(parts[1], 10, 32)
+	if err != nil {
+		return types.NodeUptime{}, fmt.Errorf("invalid ipv4 uptime in %q: %w", arg, err)
+	}
+	ipv6Uptime, err := strconv.ParseInt(parts[2], 10, 32)
+	if err != nil {
+		return types.NodeUptime{}, fmt.Errorf("invalid ipv6 uptime in %q: %w", arg, err)
+	}
+	return types.NodeUptime{NodeID: parts[0], IPV4Uptime: int32(ipv4Uptime), IPV6Uptime: int32(ipv6Uptime)}, nil
+}
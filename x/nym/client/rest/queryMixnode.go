@@ -17,15 +17,46 @@ package rest
 import (
 	"fmt"
 	"net/http"
+	"strconv"
 
 	"github.com/cosmos/cosmos-sdk/client/context"
+	sdk "github.com/cosmos/cosmos-sdk/types"
 	"github.com/cosmos/cosmos-sdk/types/rest"
 	"github.com/gorilla/mux"
+	"github.com/nymtech/nym/validator/nym/x/nym/types"
 )
 
 func listMixnodeHandler(cliCtx context.CLIContext, storeName string) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		res, _, err := cliCtx.QueryWithData(fmt.Sprintf("custom/%s/list-mixnode", storeName), nil)
+		_, page, limit, err := rest.ParseHTTPArgsWithLimit(r, 0)
+		if err != nil {
+			rest.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		var layer int64
+		if v := r.URL.Query().Get("layer"); v != "" {
+			layer, err = strconv.ParseInt(v, 10, 32)
+			if err != nil {
+				rest.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
+				return
+			}
+		}
+
+		creator, err := parseCreatorArg(r)
+		if err != nil {
+			rest.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		params := types.NewQueryMixnodesParams(page, limit, int32(layer), r.URL.Query().Get("location"), r.URL.Query().Get("version"), creator)
+		bz, err := cliCtx.Codec.MarshalJSON(params)
+		if err != nil {
+			rest.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		res, _, err := cliCtx.QueryWithData(fmt.Sprintf("custom/%s/list-mixnode", storeName), bz)
 		if err != nil {
 			rest.WriteErrorResponse(w, http.StatusNotFound, err.Error())
 			return
@@ -34,6 +65,15 @@ func listMixnodeHandler(cliCtx context.CLIContext, storeName string) http.Handle
 	}
 }
 
+// parseCreatorArg returns the address passed in the creator query argument, if any
+func parseCreatorArg(r *http.Request) (sdk.AccAddress, error) {
+	creator := r.URL.Query().Get("creator")
+	if creator == "" {
+		return nil, nil
+	}
+	return sdk.AccAddressFromBech32(creator)
+}
+
 func getMixnodeHandler(cliCtx context.CLIContext, storeName string) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		vars := mux.Vars(r)
@@ -47,3 +87,17 @@ func getMixnodeHandler(cliCtx context.CLIContext, storeName string) http.Handler
 		rest.PostProcessResponse(w, cliCtx, res)
 	}
 }
+
+func getMixnodeByIdentityHandler(cliCtx context.CLIContext, storeName string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		vars := mux.Vars(r)
+		key := vars["key"]
+
+		res, _, err := cliCtx.QueryWithData(fmt.Sprintf("custom/%s/get-mixnode-by-identity/%s", storeName, key), nil)
+		if err != nil {
+			rest.WriteErrorResponse(w, http.StatusNotFound, err.Error())
+			return
+		}
+		rest.PostProcessResponse(w, cliCtx, res)
+	}
+}
@@ -18,6 +18,7 @@ import (
 	"github.com/gorilla/mux"
 
 	"github.com/cosmos/cosmos-sdk/client/context"
+	"github.com/nymtech/nym/validator/nym/x/nym/types"
 )
 
 // RegisterRoutes registers nym-related REST handlers to a router
@@ -26,13 +27,32 @@ func RegisterRoutes(cliCtx context.CLIContext, r *mux.Router) {
 	r.HandleFunc("/nym/gateway", createGatewayHandler(cliCtx)).Methods("POST")
 	r.HandleFunc("/nym/gateway", listGatewayHandler(cliCtx, "nym")).Methods("GET")
 	r.HandleFunc("/nym/gateway/{key}", getGatewayHandler(cliCtx, "nym")).Methods("GET")
+	r.HandleFunc("/nym/gateway/identity/{key}", getGatewayByIdentityHandler(cliCtx, "nym")).Methods("GET")
 	r.HandleFunc("/nym/gateway", setGatewayHandler(cliCtx)).Methods("PUT")
 	r.HandleFunc("/nym/gateway", deleteGatewayHandler(cliCtx)).Methods("DELETE")
 
 	r.HandleFunc("/nym/mixnode", createMixnodeHandler(cliCtx)).Methods("POST")
 	r.HandleFunc("/nym/mixnode", listMixnodeHandler(cliCtx, "nym")).Methods("GET")
 	r.HandleFunc("/nym/mixnode/{key}", getMixnodeHandler(cliCtx, "nym")).Methods("GET")
+	r.HandleFunc("/nym/mixnode/identity/{key}", getMixnodeByIdentityHandler(cliCtx, "nym")).Methods("GET")
 	r.HandleFunc("/nym/mixnode", setMixnodeHandler(cliCtx)).Methods("PUT")
 	r.HandleFunc("/nym/mixnode", deleteMixnodeHandler(cliCtx)).Methods("DELETE")
 
+	r.HandleFunc("/nym/delegation", delegateHandler(cliCtx)).Methods("POST")
+	r.HandleFunc("/nym/delegation", undelegateHandler(cliCtx)).Methods("DELETE")
+	r.HandleFunc("/nym/mixnode/{key}/delegations", listMixnodeDelegationsHandler(cliCtx, "nym")).Methods("GET")
+	r.HandleFunc("/nym/delegator/{address}/delegations", listDelegatorDelegationsHandler(cliCtx, "nym")).Methods("GET")
+
+	r.HandleFunc("/nym/uptime", submitUptimeReportHandler(cliCtx)).Methods("POST")
+	r.HandleFunc("/nym/uptime/{key}", getUptimeHandler(cliCtx, "nym")).Methods("GET")
+	r.HandleFunc("/nym/uptime/{key}/{epoch}", getUptimeHandler(cliCtx, "nym")).Methods("GET")
+
+	r.HandleFunc("/nym/reputation/{key}", getReputationHandler(cliCtx, "nym")).Methods("GET")
+
+	r.HandleFunc("/nym/topology", topologyHandler(cliCtx, "nym", types.QueryTopology)).Methods("GET")
+	r.HandleFunc("/nym/topology/active", topologyHandler(cliCtx, "nym", types.QueryActiveTopology)).Methods("GET")
+	r.HandleFunc("/nym/layers/rebalance", layerRebalanceHandler(cliCtx, "nym")).Methods("GET")
+
+	r.HandleFunc("/nym/params", paramsHandler(cliCtx, "nym")).Methods("GET")
+
 }
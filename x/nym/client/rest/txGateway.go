@@ -18,6 +18,7 @@ type createGatewayRequest struct {
 	ClientListener string       `json:"clientListener"`
 	MixnetListener string       `json:"mixnetListener"`
 	Location       string       `json:"location"`
+	Bond           sdk.Coin     `json:"bond"`
 }
 
 func createGatewayHandler(cliCtx context.CLIContext) http.HandlerFunc {
@@ -36,7 +37,7 @@ func createGatewayHandler(cliCtx context.CLIContext) http.HandlerFunc {
 			rest.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
 			return
 		}
-		msg := types.NewMsgCreateGateway(creator, req.IdentityKey, req.SphinxKey, req.ClientListener, req.MixnetListener, req.Location)
+		msg := types.NewMsgCreateGateway(creator, req.IdentityKey, req.SphinxKey, req.ClientListener, req.MixnetListener, req.Location, req.Bond)
 
 		err = msg.ValidateBasic()
 		if err != nil {
@@ -25,14 +25,15 @@ import (
 )
 
 type createMixnodeRequest struct {
-	BaseReq    rest.BaseReq `json:"base_req"`
-	Creator    string       `json:"creator"`
-	PubKey     string       `json:"pubKey"`
-	Layer      int32        `json:"layer"`
-	Version    string       `json:"version"`
-	Host       string       `json:"host"`
-	Location   string       `json:"location"`
-	Reputation int32        `json:"reputation"`
+	BaseReq   rest.BaseReq `json:"base_req"`
+	Creator   string       `json:"creator"`
+	PubKey    string       `json:"pubKey"`
+	SphinxKey string       `json:"sphinxKey"`
+	Layer     int32        `json:"layer"`
+	Version   string       `json:"version"`
+	Host      string       `json:"host"`
+	Location  string       `json:"location"`
+	Bond      sdk.Coin     `json:"bond"`
 }
 
 func createMixnodeHandler(cliCtx context.CLIContext) http.HandlerFunc {
@@ -51,7 +52,7 @@ func createMixnodeHandler(cliCtx context.CLIContext) http.HandlerFunc {
 			rest.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
 			return
 		}
-		msg := types.NewMsgCreateMixnode(creator, req.PubKey, req.Layer, req.Version, req.Host, req.Location, req.Reputation)
+		msg := types.NewMsgCreateMixnode(creator, req.PubKey, req.SphinxKey, req.Layer, req.Version, req.Host, req.Location, req.Bond)
 
 		err = msg.ValidateBasic()
 		if err != nil {
@@ -64,15 +65,15 @@ func createMixnodeHandler(cliCtx context.CLIContext) http.HandlerFunc {
 }
 
 type setMixnodeRequest struct {
-	BaseReq    rest.BaseReq `json:"base_req"`
-	ID         string       `json:"id"`
-	Creator    string       `json:"creator"`
-	PubKey     string       `json:"pubKey"`
-	Layer      int32        `json:"layer"`
-	Version    string       `json:"version"`
-	Host       string       `json:"host"`
-	Location   string       `json:"location"`
-	Reputation int32        `json:"reputation"`
+	BaseReq   rest.BaseReq `json:"base_req"`
+	ID        string       `json:"id"`
+	Creator   string       `json:"creator"`
+	PubKey    string       `json:"pubKey"`
+	SphinxKey string       `json:"sphinxKey"`
+	Layer     int32        `json:"layer"`
+	Version   string       `json:"version"`
+	Host      string       `json:"host"`
+	Location  string       `json:"location"`
 }
 
 func setMixnodeHandler(cliCtx context.CLIContext) http.HandlerFunc {
@@ -91,7 +92,7 @@ func setMixnodeHandler(cliCtx context.CLIContext) http.HandlerFunc {
 			rest.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
 			return
 		}
-		msg := types.NewMsgSetMixnode(creator, req.ID, req.PubKey, req.Layer, req.Version, req.Host, req.Location, req.Reputation)
+		msg := types.NewMsgSetMixnode(creator, req.ID, req.PubKey, req.SphinxKey, req.Layer, req.Version, req.Host, req.Location)
 
 		err = msg.ValidateBasic()
 		if err != nil {
@@ -0,0 +1,122 @@
+// Copyright 2020 Nym Technologies SA
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nym
+
+import (
+	"testing"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/nymtech/nym/validator/nym/x/nym/types"
+)
+
+// handleWithEvents handles the message with an empty event manager, returning the events it emitted
+func handleWithEvents(t *testing.T, ctx sdk.Context, handler sdk.Handler, msg sdk.Msg) sdk.Events {
+	res, err := handler(ctx.WithEventManager(sdk.NewEventManager()), msg)
+	require.NoError(t, err)
+	return res.Events
+}
+
+func moduleMessageEvent(sender sdk.AccAddress) sdk.Event {
+	return sdk.NewEvent(
+		sdk.EventTypeMessage,
+		sdk.NewAttribute(sdk.AttributeKeyModule, types.ModuleName),
+		sdk.NewAttribute(sdk.AttributeKeySender, sender.String()),
+	)
+}
+
+func TestMixnodeEvents(t *testing.T) {
+	ctx, _, handler, _ := setupHandler(t)
+	nodeEvent := func(eventType string, host string) sdk.Event {
+		return sdk.NewEvent(eventType,
+			sdk.NewAttribute(types.AttributeKeyID, "node"),
+			sdk.NewAttribute(types.AttributeKeyIdentityKey, identityKey),
+			sdk.NewAttribute(types.AttributeKeyCreator, owner.String()),
+			sdk.NewAttribute(types.AttributeKeyHost, host),
+			sdk.NewAttribute(types.AttributeKeyLayer, "1"),
+		)
+	}
+
+	events := handleWithEvents(t, ctx, handler, createMixnodeMsg(owner, "node", identityKey))
+	assert.Equal(t, sdk.Events{nodeEvent(types.EventTypeCreateMixnode, "1.1.1.1:1789"), moduleMessageEvent(owner)}, events)
+
+	events = handleWithEvents(t, ctx, handler, types.NewMsgSetMixnode(owner, "node", identityKey, identityKey, 1, types.DefaultSystemVersion, "2.2.2.2:1789", "Neuchatel"))
+	assert.Equal(t, sdk.Events{nodeEvent(types.EventTypeUpdateMixnode, "2.2.2.2:1789"), moduleMessageEvent(owner)}, events)
+
+	events = handleWithEvents(t, ctx, handler, types.NewMsgDeleteMixnode("node", owner))
+	assert.Equal(t, sdk.Events{nodeEvent(types.EventTypeDeleteMixnode, "2.2.2.2:1789"), moduleMessageEvent(owner)}, events)
+}
+
+func TestGatewayEvents(t *testing.T) {
+	ctx, _, handler, _ := setupHandler(t)
+	nodeEvent := func(eventType string, host string) sdk.Event {
+		return sdk.NewEvent(eventType,
+			sdk.NewAttribute(types.AttributeKeyID, "node"),
+			sdk.NewAttribute(types.AttributeKeyIdentityKey, identityKey),
+			sdk.NewAttribute(types.AttributeKeyCreator, owner.String()),
+			sdk.NewAttribute(types.AttributeKeyHost, host),
+		)
+	}
+
+	events := handleWithEvents(t, ctx, handler, createGatewayMsg(owner, "node", identityKey))
+	assert.Equal(t, sdk.Events{nodeEvent(types.EventTypeCreateGateway, "1.1.1.1:1789"), moduleMessageEvent(owner)}, events)
+
+	events = handleWithEvents(t, ctx, handler, types.NewMsgSetGateway(owner, "node", identityKey, identityKey, "ws://1.1.1.1:9000", "2.2.2.2:1789", "Neuchatel"))
+	assert.Equal(t, sdk.Events{nodeEvent(types.EventTypeUpdateGateway, "2.2.2.2:1789"), moduleMessageEvent(owner)}, events)
+
+	events = handleWithEvents(t, ctx, handler, types.NewMsgDeleteGateway("node", owner))
+	assert.Equal(t, sdk.Events{nodeEvent(types.EventTypeDeleteGateway, "2.2.2.2:1789"), moduleMessageEvent(owner)}, events)
+}
+
+func TestDelegationEvents(t *testing.T) {
+	ctx, _, handler, _ := setupDelegation(t)
+	delegationEvent := func(eventType string, amount int64) sdk.Event {
+		return sdk.NewEvent(eventType,
+			sdk.NewAttribute(types.AttributeKeyMixnodeID, "node"),
+			sdk.NewAttribute(types.AttributeKeyDelegator, delegator.String()),
+			sdk.NewAttribute(sdk.AttributeKeyAmount, nymCoin(amount).String()),
+		)
+	}
+
+	events := handleWithEvents(t, ctx, handler, types.NewMsgDelegate("node", delegator, nymCoin(10)))
+	assert.Equal(t, sdk.Events{delegationEvent(types.EventTypeDelegate, 10), moduleMessageEvent(delegator)}, events)
+
+	events = handleWithEvents(t, ctx, handler, types.NewMsgUndelegate("node", delegator, nymCoin(10)))
+	assert.Equal(t, sdk.Events{delegationEvent(types.EventTypeUndelegate, 10), moduleMessageEvent(delegator)}, events)
+}
+
+func TestUptimeReportEvents(t *testing.T) {
+	ctx, k, handler, _ := setupHandler(t)
+	params := k.GetParams(ctx)
+	params.NetworkMonitors = []sdk.AccAddress{monitor}
+	k.SetParams(ctx, params)
+	_, err := handler(ctx, createMixnodeMsg(owner, "node", identityKey))
+	require.NoError(t, err)
+
+	events := handleWithEvents(t, ctx.WithBlockHeight(params.EpochLength), handler,
+		types.NewMsgSubmitUptimeReport(monitor, []types.NodeUptime{{NodeID: "node", IPV4Uptime: 100, IPV6Uptime: 20}}))
+	assert.Equal(t, sdk.Events{
+		sdk.NewEvent(types.EventTypeUptimeReport,
+			sdk.NewAttribute(types.AttributeKeyID, "node"),
+			sdk.NewAttribute(types.AttributeKeyEpoch, "1"),
+			sdk.NewAttribute(types.AttributeKeyIPV4Uptime, "100"),
+			sdk.NewAttribute(types.AttributeKeyIPV6Uptime, "20"),
+			sdk.NewAttribute(types.AttributeKeyReputation, "1"),
+		),
+		moduleMessageEvent(monitor),
+	}, events)
+}
@@ -15,22 +15,54 @@
 package nym
 
 import (
+	"fmt"
+
 	sdk "github.com/cosmos/cosmos-sdk/types"
 	"github.com/nymtech/nym/validator/nym/x/nym/keeper"
 	"github.com/nymtech/nym/validator/nym/x/nym/types"
-	// abci "github.com/tendermint/tendermint/abci/types"
 )
 
 // InitGenesis initialize default parameters
 // and the keeper's address to pubkey map
-func InitGenesis(ctx sdk.Context, k keeper.Keeper /* TODO: Define what keepers the module needs */, data types.GenesisState) {
-	// TODO: Define logic for when you would like to initalize a new genesis
+func InitGenesis(ctx sdk.Context, k keeper.Keeper, data types.GenesisState) {
+	k.SetParams(ctx, data.Params)
+
+	bonded := sdk.NewCoins()
+	for _, mixnode := range data.Mixnodes {
+		k.CreateMixnode(ctx, mixnode)
+		bonded = bonded.Add(mixnode.Bond)
+	}
+	for _, gateway := range data.Gateways {
+		k.CreateGateway(ctx, gateway)
+		bonded = bonded.Add(gateway.Bond)
+	}
+	for _, report := range data.UptimeReports {
+		k.SetUptimeReport(ctx, report)
+	}
+	for _, delegation := range data.Delegations {
+		k.SetDelegation(ctx, delegation)
+		bonded = bonded.Add(delegation.Amount)
+	}
+
+	moduleAcc := k.GetModuleAccount(ctx)
+	if moduleAcc == nil {
+		panic(fmt.Sprintf("%s module account has not been set", types.ModuleName))
+	}
+
+	// add coins if not provided on genesis
+	if moduleAcc.GetCoins().IsZero() {
+		if err := moduleAcc.SetCoins(bonded); err != nil {
+			panic(err)
+		}
+		k.SupplyKeeper.SetModuleAccount(ctx, moduleAcc)
+	} else if !moduleAcc.GetCoins().IsEqual(bonded) {
+		panic(fmt.Sprintf("%s module account balance (%s) does not match the sum of bonds and delegations (%s)", types.ModuleName, moduleAcc.GetCoins(), bonded))
+	}
 }
 
 // ExportGenesis writes the current store values
 // to a genesis file, which can be imported again
-// with InitGenesis
+// with InitGenesis. Only the uptime reports of the current epoch are exported, as they are pruned at the end of the epoch.
 func ExportGenesis(ctx sdk.Context, k keeper.Keeper) (data types.GenesisState) {
-	// TODO: Define logic for exporting state
-	return types.NewGenesisState()
+	return types.NewGenesisState(k.GetParams(ctx), k.GetAllMixnodes(ctx), k.GetAllGateways(ctx), k.GetAllDelegations(ctx), k.GetEpochUptimeReports(ctx, k.CurrentEpoch(ctx)))
 }
@@ -0,0 +1,113 @@
+// Copyright 2020 Nym Technologies SA
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nym
+
+import (
+	"testing"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/x/supply"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/nymtech/nym/validator/nym/x/nym/types"
+)
+
+var moduleAddress = supply.NewModuleAddress(types.ModuleName)
+
+// genesisState returns a genesis state with a bonded mixnode and gateway and a delegation to the mixnode,
+// escrowing 210nym in total
+func genesisState() types.GenesisState {
+	genesis := types.DefaultGenesisState()
+	genesis.Mixnodes = []types.Mixnode{{
+		Creator:   owner,
+		ID:        "mixnode",
+		PubKey:    identityKey,
+		SphinxKey: identityKey,
+		Layer:     1,
+		Version:   types.DefaultSystemVersion,
+		Host:      "1.1.1.1:1789",
+		Location:  "Neuchatel",
+		Bond:      types.DefaultMinimumMixnodeBond,
+	}}
+	genesis.Gateways = []types.Gateway{{
+		Creator:        owner,
+		ID:             "gateway",
+		IdentityKey:    otherIdentityKey,
+		SphinxKey:      otherIdentityKey,
+		ClientListener: "ws://1.1.1.1:9000",
+		MixnetListener: "1.1.1.1:1789",
+		Location:       "Neuchatel",
+		Bond:           types.DefaultMinimumGatewayBond,
+	}}
+	genesis.Delegations = []types.Delegation{types.NewDelegation("mixnode", thief, sdk.NewInt64Coin(types.DefaultBondDenom, 10))}
+	return genesis
+}
+
+func TestInitGenesisFundsModuleAccountWithBondsAndDelegations(t *testing.T) {
+	ctx, k, _, supplyKeeper := setupHandler(t)
+	InitGenesis(ctx, k, genesisState())
+
+	assert.Equal(t, sdk.NewCoins(sdk.NewInt64Coin(types.DefaultBondDenom, 210)), supplyKeeper.balance(ctx, moduleAddress))
+	id, found := k.GetMixnodeIDByIdentity(ctx, identityKey)
+	assert.True(t, found)
+	assert.Equal(t, "mixnode", id)
+	id, found = k.GetGatewayIDByIdentity(ctx, otherIdentityKey)
+	assert.True(t, found)
+	assert.Equal(t, "gateway", id)
+	assert.Equal(t, sdk.NewCoins(sdk.NewInt64Coin(types.DefaultBondDenom, 10)), k.TotalDelegated(ctx, "mixnode"))
+}
+
+func TestInitGenesisAcceptsMatchingModuleBalance(t *testing.T) {
+	ctx, k, _, supplyKeeper := setupHandler(t)
+	supplyKeeper.setBalance(ctx, moduleAddress, sdk.NewCoins(sdk.NewInt64Coin(types.DefaultBondDenom, 210)))
+
+	assert.NotPanics(t, func() { InitGenesis(ctx, k, genesisState()) })
+	assert.Equal(t, sdk.NewCoins(sdk.NewInt64Coin(types.DefaultBondDenom, 210)), supplyKeeper.balance(ctx, moduleAddress))
+}
+
+func TestInitGenesisRejectsModuleBalanceNotMatchingBonds(t *testing.T) {
+	ctx, k, _, supplyKeeper := setupHandler(t)
+	supplyKeeper.setBalance(ctx, moduleAddress, sdk.NewCoins(sdk.NewInt64Coin(types.DefaultBondDenom, 200)))
+
+	assert.Panics(t, func() { InitGenesis(ctx, k, genesisState()) })
+}
+
+func TestExportGenesisReturnsImportedState(t *testing.T) {
+	ctx, k, _, _ := setupHandler(t)
+	genesis := genesisState()
+	InitGenesis(ctx, k, genesis)
+
+	exported := ExportGenesis(ctx, k)
+	require.NoError(t, types.ValidateGenesis(exported))
+	assert.Equal(t, genesis.Mixnodes, exported.Mixnodes)
+	assert.Equal(t, genesis.Gateways, exported.Gateways)
+	assert.Equal(t, genesis.Delegations, exported.Delegations)
+	assert.Equal(t, genesis.Params.String(), exported.Params.String())
+}
+
+func TestExportGenesisOnlyExportsTheReportsOfTheCurrentEpoch(t *testing.T) {
+	ctx, k, _, _ := setupHandler(t)
+	epochLength := k.GetParams(ctx).EpochLength
+	genesis := genesisState()
+	genesis.UptimeReports = []types.UptimeReport{
+		types.NewUptimeReport(0, monitor, types.NodeUptime{NodeID: "mixnode", IPV4Uptime: 100, IPV6Uptime: 100}),
+		types.NewUptimeReport(1, monitor, types.NodeUptime{NodeID: "mixnode", IPV4Uptime: 50, IPV6Uptime: 50}),
+	}
+	InitGenesis(ctx, k, genesis)
+
+	exported := ExportGenesis(ctx.WithBlockHeight(epochLength), k)
+	assert.Equal(t, genesis.UptimeReports[1:], exported.UptimeReports)
+}
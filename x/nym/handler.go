@@ -16,6 +16,7 @@ package nym
 
 import (
 	"fmt"
+	"strconv"
 
 	sdk "github.com/cosmos/cosmos-sdk/types"
 	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
@@ -41,9 +42,65 @@ func NewHandler(k keeper.Keeper) sdk.Handler {
 			return handleMsgSetMixnode(ctx, k, msg)
 		case types.MsgDeleteMixnode:
 			return handleMsgDeleteMixnode(ctx, k, msg)
+		case types.MsgDelegate:
+			return handleMsgDelegate(ctx, k, msg)
+		case types.MsgUndelegate:
+			return handleMsgUndelegate(ctx, k, msg)
+		case types.MsgSubmitUptimeReport:
+			return handleMsgSubmitUptimeReport(ctx, k, msg)
 		default:
 			errMsg := fmt.Sprintf("unrecognized %s message type: %T", types.ModuleName, msg)
 			return nil, sdkerrors.Wrap(sdkerrors.ErrUnknownRequest, errMsg)
 		}
 	}
 }
+
+// validateMixnodeParams checks whether the mixnode's layer and version conform to the current module parameters
+func validateMixnodeParams(params types.Params, layer int32, version string) error {
+	if layer < 1 || uint32(layer) > params.Layers {
+		return sdkerrors.Wrapf(types.ErrInvalidLayer, "layer must be between 1 and %d, got %d", params.Layers, layer)
+	}
+	if version != params.SystemVersion {
+		return sdkerrors.Wrapf(types.ErrInvalidVersion, "running non %s version", params.SystemVersion)
+	}
+	return nil
+}
+
+func validateBond(minimum, bond sdk.Coin) error {
+	if bond.Denom != minimum.Denom {
+		return sdkerrors.Wrapf(sdkerrors.ErrInvalidCoins, "bond must be denominated in %s, got %s", minimum.Denom, bond.Denom)
+	}
+	if bond.IsLT(minimum) {
+		return sdkerrors.Wrapf(types.ErrInsufficientBond, "bond of at least %s is required, got %s", minimum, bond)
+	}
+	return nil
+}
+
+func mixnodeEvent(eventType string, mixnode types.Mixnode) sdk.Event {
+	return sdk.NewEvent(
+		eventType,
+		sdk.NewAttribute(types.AttributeKeyID, mixnode.ID),
+		sdk.NewAttribute(types.AttributeKeyIdentityKey, mixnode.PubKey),
+		sdk.NewAttribute(types.AttributeKeyCreator, mixnode.Creator.String()),
+		sdk.NewAttribute(types.AttributeKeyHost, mixnode.Host),
+		sdk.NewAttribute(types.AttributeKeyLayer, strconv.Itoa(int(mixnode.Layer))),
+	)
+}
+
+func gatewayEvent(eventType string, gateway types.Gateway) sdk.Event {
+	return sdk.NewEvent(
+		eventType,
+		sdk.NewAttribute(types.AttributeKeyID, gateway.ID),
+		sdk.NewAttribute(types.AttributeKeyIdentityKey, gateway.IdentityKey),
+		sdk.NewAttribute(types.AttributeKeyCreator, gateway.Creator.String()),
+		sdk.NewAttribute(types.AttributeKeyHost, gateway.MixnetListener),
+	)
+}
+
+func messageEvent(sender sdk.AccAddress) sdk.Event {
+	return sdk.NewEvent(
+		sdk.EventTypeMessage,
+		sdk.NewAttribute(sdk.AttributeKeyModule, types.AttributeValueCategory),
+		sdk.NewAttribute(sdk.AttributeKeySender, sender.String()),
+	)
+}
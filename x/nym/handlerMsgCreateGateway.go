@@ -2,22 +2,47 @@ package nym
 
 import (
 	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
 
 	"github.com/nymtech/nym/validator/nym/x/nym/keeper"
 	"github.com/nymtech/nym/validator/nym/x/nym/types"
 )
 
 func handleMsgCreateGateway(ctx sdk.Context, k keeper.Keeper, msg types.MsgCreateGateway) (*sdk.Result, error) {
+	params := k.GetParams(ctx)
+	if k.GatewayCount(ctx) >= params.MaximumGateways {
+		return nil, sdkerrors.Wrapf(types.ErrNetworkAtCapacity, "maximum of %d gateways reached", params.MaximumGateways)
+	}
+	if k.MixnodeExists(ctx, msg.ID) || k.GatewayExists(ctx, msg.ID) {
+		return nil, sdkerrors.Wrap(types.ErrDuplicateID, msg.ID)
+	}
+	if _, found := k.GetGatewayIDByIdentity(ctx, msg.IdentityKey); found {
+		return nil, sdkerrors.Wrap(types.ErrDuplicateIdentity, msg.IdentityKey)
+	}
+	if err := validateBond(params.MinimumGatewayBond, msg.Bond); err != nil {
+		return nil, err
+	}
+	if err := k.Bond(ctx, msg.Creator, msg.Bond); err != nil {
+		return nil, err
+	}
+
 	var gateway = types.Gateway{
-		Creator:        msg.Creator,
-		ID:             msg.ID,
-		IdentityKey:    msg.IdentityKey,
-		SphinxKey:      msg.SphinxKey,
-		ClientListener: msg.ClientListener,
-		MixnetListener: msg.MixnetListener,
-		Location:       msg.Location,
+		Creator:          msg.Creator,
+		ID:               msg.ID,
+		IdentityKey:      msg.IdentityKey,
+		SphinxKey:        msg.SphinxKey,
+		ClientListener:   msg.ClientListener,
+		MixnetListener:   msg.MixnetListener,
+		Location:         msg.Location,
+		Bond:             msg.Bond,
+		RegistrationTime: ctx.BlockTime().UnixNano(),
 	}
 	k.CreateGateway(ctx, gateway)
 
+	ctx.EventManager().EmitEvents(sdk.Events{
+		gatewayEvent(types.EventTypeCreateGateway, gateway),
+		messageEvent(msg.Creator),
+	})
+
 	return &sdk.Result{Events: ctx.EventManager().Events()}, nil
 }
@@ -16,23 +16,51 @@ package nym
 
 import (
 	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
 
 	"github.com/nymtech/nym/validator/nym/x/nym/keeper"
 	"github.com/nymtech/nym/validator/nym/x/nym/types"
 )
 
 func handleMsgCreateMixnode(ctx sdk.Context, k keeper.Keeper, msg types.MsgCreateMixnode) (*sdk.Result, error) {
+	params := k.GetParams(ctx)
+	if k.MixnodeCount(ctx) >= params.MaximumMixnodes {
+		return nil, sdkerrors.Wrapf(types.ErrNetworkAtCapacity, "maximum of %d mixnodes reached", params.MaximumMixnodes)
+	}
+	if err := validateMixnodeParams(params, msg.Layer, msg.Version); err != nil {
+		return nil, err
+	}
+	if k.MixnodeExists(ctx, msg.ID) || k.GatewayExists(ctx, msg.ID) {
+		return nil, sdkerrors.Wrap(types.ErrDuplicateID, msg.ID)
+	}
+	if _, found := k.GetMixnodeIDByIdentity(ctx, msg.PubKey); found {
+		return nil, sdkerrors.Wrap(types.ErrDuplicateIdentity, msg.PubKey)
+	}
+	if err := validateBond(params.MinimumMixnodeBond, msg.Bond); err != nil {
+		return nil, err
+	}
+	if err := k.Bond(ctx, msg.Creator, msg.Bond); err != nil {
+		return nil, err
+	}
+
 	var mixnode = types.Mixnode{
-		Creator:    msg.Creator,
-		ID:         msg.ID,
-		PubKey:     msg.PubKey,
-		Layer:      msg.Layer,
-		Version:    msg.Version,
-		Host:       msg.Host,
-		Location:   msg.Location,
-		Reputation: msg.Reputation,
+		Creator:          msg.Creator,
+		ID:               msg.ID,
+		PubKey:           msg.PubKey,
+		SphinxKey:        msg.SphinxKey,
+		Layer:            msg.Layer,
+		Version:          msg.Version,
+		Host:             msg.Host,
+		Location:         msg.Location,
+		Bond:             msg.Bond,
+		RegistrationTime: ctx.BlockTime().UnixNano(),
 	}
 	k.CreateMixnode(ctx, mixnode)
 
+	ctx.EventManager().EmitEvents(sdk.Events{
+		mixnodeEvent(types.EventTypeCreateMixnode, mixnode),
+		messageEvent(msg.Creator),
+	})
+
 	return &sdk.Result{Events: ctx.EventManager().Events()}, nil
 }
@@ -0,0 +1,138 @@
+// Copyright 2020 Nym Technologies SA
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nym
+
+import (
+	"testing"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/nymtech/nym/validator/nym/x/nym/keeper"
+	"github.com/nymtech/nym/validator/nym/x/nym/types"
+)
+
+var delegator = sdk.AccAddress([]byte("delegator___________"))
+
+func nymCoin(amount int64) sdk.Coin {
+	return sdk.NewInt64Coin(types.DefaultBondDenom, amount)
+}
+
+func nymCoins(amount int64) sdk.Coins {
+	return sdk.NewCoins(nymCoin(amount))
+}
+
+// setupDelegation returns a handler with the mixnode "node" bonded by the owner, and 1000nym for the delegator
+func setupDelegation(t *testing.T) (sdk.Context, keeper.Keeper, sdk.Handler, *testSupplyKeeper) {
+	ctx, k, handler, supplyKeeper := setupHandler(t)
+	supplyKeeper.setBalance(ctx, delegator, nymCoins(1000))
+	_, err := handler(ctx, createMixnodeMsg(owner, "node", identityKey))
+	require.NoError(t, err)
+	return ctx, k, handler, supplyKeeper
+}
+
+func TestDelegateEscrowsAndAddsUpDelegations(t *testing.T) {
+	ctx, k, handler, supplyKeeper := setupDelegation(t)
+
+	_, err := handler(ctx, types.NewMsgDelegate("node", delegator, nymCoin(10)))
+	require.NoError(t, err)
+	_, err = handler(ctx, types.NewMsgDelegate("node", delegator, nymCoin(15)))
+	require.NoError(t, err)
+
+	delegation, found := k.GetDelegation(ctx, "node", delegator)
+	require.True(t, found)
+	assert.Equal(t, nymCoin(25), delegation.Amount)
+	assert.Equal(t, []types.Delegation{delegation}, k.GetMixnodeDelegations(ctx, "node"))
+	assert.Equal(t, []types.Delegation{delegation}, k.GetDelegatorDelegations(ctx, delegator))
+	assert.Equal(t, nymCoins(25), k.TotalDelegated(ctx, "node"))
+	assert.Equal(t, nymCoins(975), supplyKeeper.balance(ctx, delegator))
+	assert.Equal(t, nymCoins(125), supplyKeeper.balance(ctx, moduleAddress))
+}
+
+func TestDelegateRejectsInvalidDelegations(t *testing.T) {
+	ctx, k, handler, supplyKeeper := setupDelegation(t)
+
+	_, err := handler(ctx, types.NewMsgDelegate("unknown", delegator, nymCoin(10)))
+	assert.True(t, sdkerrors.ErrInvalidRequest.Is(err), err)
+	_, err = handler(ctx, types.NewMsgDelegate("node", delegator, sdk.NewInt64Coin("stake", 10)))
+	assert.True(t, sdkerrors.ErrInvalidCoins.Is(err), err)
+	_, err = handler(ctx, types.NewMsgDelegate("node", delegator, nymCoin(1001)))
+	assert.True(t, sdkerrors.ErrInsufficientFunds.Is(err), err)
+
+	_, found := k.GetDelegation(ctx, "node", delegator)
+	assert.False(t, found)
+	assert.Equal(t, nymCoins(1000), supplyKeeper.balance(ctx, delegator))
+}
+
+func TestUndelegatePartiallyThenFully(t *testing.T) {
+	ctx, k, handler, supplyKeeper := setupDelegation(t)
+	_, err := handler(ctx, types.NewMsgDelegate("node", delegator, nymCoin(25)))
+	require.NoError(t, err)
+
+	_, err = handler(ctx, types.NewMsgUndelegate("node", delegator, nymCoin(10)))
+	require.NoError(t, err)
+	delegation, found := k.GetDelegation(ctx, "node", delegator)
+	require.True(t, found)
+	assert.Equal(t, nymCoin(15), delegation.Amount)
+	assert.Equal(t, nymCoins(985), supplyKeeper.balance(ctx, delegator))
+
+	_, err = handler(ctx, types.NewMsgUndelegate("node", delegator, nymCoin(15)))
+	require.NoError(t, err)
+	_, found = k.GetDelegation(ctx, "node", delegator)
+	assert.False(t, found)
+	assert.Empty(t, k.GetDelegatorDelegations(ctx, delegator))
+	assert.Equal(t, nymCoins(1000), supplyKeeper.balance(ctx, delegator))
+	assert.Equal(t, nymCoins(100), supplyKeeper.balance(ctx, moduleAddress))
+}
+
+func TestUndelegateRejectsMoreThanDelegated(t *testing.T) {
+	ctx, k, handler, supplyKeeper := setupDelegation(t)
+
+	_, err := handler(ctx, types.NewMsgUndelegate("node", delegator, nymCoin(10)))
+	assert.True(t, types.ErrNoDelegation.Is(err), err)
+
+	_, err = handler(ctx, types.NewMsgDelegate("node", delegator, nymCoin(10)))
+	require.NoError(t, err)
+	_, err = handler(ctx, types.NewMsgUndelegate("node", delegator, nymCoin(11)))
+	assert.True(t, types.ErrInsufficientDelegation.Is(err), err)
+	_, err = handler(ctx, types.NewMsgUndelegate("node", delegator, sdk.NewInt64Coin("stake", 10)))
+	assert.True(t, types.ErrInsufficientDelegation.Is(err), err)
+
+	delegation, found := k.GetDelegation(ctx, "node", delegator)
+	require.True(t, found)
+	assert.Equal(t, nymCoin(10), delegation.Amount)
+	assert.Equal(t, nymCoins(990), supplyKeeper.balance(ctx, delegator))
+}
+
+func TestDeleteMixnodeRefundsDelegations(t *testing.T) {
+	ctx, k, handler, supplyKeeper := setupDelegation(t)
+	_, err := handler(ctx, types.NewMsgDelegate("node", delegator, nymCoin(10)))
+	require.NoError(t, err)
+	_, err = handler(ctx, types.NewMsgDelegate("node", thief, nymCoin(20)))
+	require.NoError(t, err)
+
+	_, err = handler(ctx, types.NewMsgDeleteMixnode("node", owner))
+	require.NoError(t, err)
+
+	assert.Empty(t, k.GetAllDelegations(ctx))
+	assert.Empty(t, k.GetDelegatorDelegations(ctx, delegator))
+	assert.Empty(t, k.GetDelegatorDelegations(ctx, thief))
+	assert.Equal(t, nymCoins(1000), supplyKeeper.balance(ctx, owner))
+	assert.Equal(t, nymCoins(1000), supplyKeeper.balance(ctx, delegator))
+	assert.Equal(t, nymCoins(1000), supplyKeeper.balance(ctx, thief))
+	assert.True(t, supplyKeeper.balance(ctx, moduleAddress).IsZero())
+}
@@ -14,10 +14,23 @@ func handleMsgDeleteGateway(ctx sdk.Context, k keeper.Keeper, msg types.MsgDelet
 		// replace with ErrKeyNotFound for 0.39+
 		return nil, sdkerrors.Wrap(sdkerrors.ErrInvalidRequest, msg.ID)
 	}
-	if !msg.Creator.Equals(k.GetGatewayOwner(ctx, msg.ID)) {
+	gateway, err := k.GetGateway(ctx, msg.ID)
+	if err != nil {
+		return nil, err
+	}
+	if !msg.Creator.Equals(gateway.Creator) {
 		return nil, sdkerrors.Wrap(sdkerrors.ErrUnauthorized, "Incorrect Owner")
 	}
+	if err := k.Unbond(ctx, gateway.Creator, gateway.Bond); err != nil {
+		return nil, err
+	}
 
 	k.DeleteGateway(ctx, msg.ID)
-	return &sdk.Result{}, nil
+
+	ctx.EventManager().EmitEvents(sdk.Events{
+		gatewayEvent(types.EventTypeDeleteGateway, gateway),
+		messageEvent(msg.Creator),
+	})
+
+	return &sdk.Result{Events: ctx.EventManager().Events()}, nil
 }
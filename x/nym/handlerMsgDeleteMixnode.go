@@ -28,10 +28,26 @@ func handleMsgDeleteMixnode(ctx sdk.Context, k keeper.Keeper, msg types.MsgDelet
 		// replace with ErrKeyNotFound for 0.39+
 		return nil, sdkerrors.Wrap(sdkerrors.ErrInvalidRequest, msg.ID)
 	}
-	if !msg.Creator.Equals(k.GetMixnodeOwner(ctx, msg.ID)) {
+	mixnode, err := k.GetMixnode(ctx, msg.ID)
+	if err != nil {
+		return nil, err
+	}
+	if !msg.Creator.Equals(mixnode.Creator) {
 		return nil, sdkerrors.Wrap(sdkerrors.ErrUnauthorized, "Incorrect Owner")
 	}
+	if err := k.Unbond(ctx, mixnode.Creator, mixnode.Bond); err != nil {
+		return nil, err
+	}
+	if err := k.RefundDelegations(ctx, msg.ID); err != nil {
+		return nil, err
+	}
 
 	k.DeleteMixnode(ctx, msg.ID)
-	return &sdk.Result{}, nil
+
+	ctx.EventManager().EmitEvents(sdk.Events{
+		mixnodeEvent(types.EventTypeDeleteMixnode, mixnode),
+		messageEvent(msg.Creator),
+	})
+
+	return &sdk.Result{Events: ctx.EventManager().Events()}, nil
 }
@@ -9,20 +9,38 @@ import (
 )
 
 func handleMsgSetGateway(ctx sdk.Context, k keeper.Keeper, msg types.MsgSetGateway) (*sdk.Result, error) {
+	if !k.GatewayExists(ctx, msg.ID) {
+		return nil, sdkerrors.Wrap(sdkerrors.ErrInvalidRequest, msg.ID)
+	}
+	existing, err := k.GetGateway(ctx, msg.ID)
+	if err != nil {
+		return nil, err
+	}
 	var gateway = types.Gateway{
-		Creator:        msg.Creator,
-		ID:             msg.ID,
-		IdentityKey:    msg.IdentityKey,
-		SphinxKey:      msg.SphinxKey,
-		ClientListener: msg.ClientListener,
-		MixnetListener: msg.MixnetListener,
-		Location:       msg.Location,
+		Creator:          msg.Creator,
+		ID:               msg.ID,
+		IdentityKey:      msg.IdentityKey,
+		SphinxKey:        msg.SphinxKey,
+		ClientListener:   msg.ClientListener,
+		MixnetListener:   msg.MixnetListener,
+		Location:         msg.Location,
+		Reputation:       existing.Reputation,
+		Bond:             existing.Bond, // the bond can only change by deleting and re-registering the node
+		RegistrationTime: existing.RegistrationTime,
 	}
-	if !msg.Creator.Equals(k.GetGatewayOwner(ctx, msg.ID)) { // Checks if the the msg sender is the same as the current owner
+	if !msg.Creator.Equals(existing.Creator) { // Checks if the the msg sender is the same as the current owner
 		return nil, sdkerrors.Wrap(sdkerrors.ErrUnauthorized, "Incorrect Owner") // If not, throw an error
 	}
+	if id, found := k.GetGatewayIDByIdentity(ctx, msg.IdentityKey); found && id != msg.ID {
+		return nil, sdkerrors.Wrap(types.ErrDuplicateIdentity, msg.IdentityKey)
+	}
 
 	k.SetGateway(ctx, gateway)
 
+	ctx.EventManager().EmitEvents(sdk.Events{
+		gatewayEvent(types.EventTypeUpdateGateway, gateway),
+		messageEvent(msg.Creator),
+	})
+
 	return &sdk.Result{Events: ctx.EventManager().Events()}, nil
 }
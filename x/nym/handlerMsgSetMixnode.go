@@ -23,21 +23,42 @@ import (
 )
 
 func handleMsgSetMixnode(ctx sdk.Context, k keeper.Keeper, msg types.MsgSetMixnode) (*sdk.Result, error) {
+	if !k.MixnodeExists(ctx, msg.ID) {
+		return nil, sdkerrors.Wrap(sdkerrors.ErrInvalidRequest, msg.ID)
+	}
+	existing, err := k.GetMixnode(ctx, msg.ID)
+	if err != nil {
+		return nil, err
+	}
 	var mixnode = types.Mixnode{
-		Creator:    msg.Creator,
-		ID:         msg.ID,
-		PubKey:     msg.PubKey,
-		Layer:      msg.Layer,
-		Version:    msg.Version,
-		Host:       msg.Host,
-		Location:   msg.Location,
-		Reputation: msg.Reputation,
+		Creator:          msg.Creator,
+		ID:               msg.ID,
+		PubKey:           msg.PubKey,
+		SphinxKey:        msg.SphinxKey,
+		Layer:            msg.Layer,
+		Version:          msg.Version,
+		Host:             msg.Host,
+		Location:         msg.Location,
+		Reputation:       existing.Reputation,
+		Bond:             existing.Bond, // the bond can only change by deleting and re-registering the node
+		RegistrationTime: existing.RegistrationTime,
 	}
-	if !msg.Creator.Equals(k.GetMixnodeOwner(ctx, msg.ID)) { // Checks if the the msg sender is the same as the current owner
+	if !msg.Creator.Equals(existing.Creator) { // Checks if the the msg sender is the same as the current owner
 		return nil, sdkerrors.Wrap(sdkerrors.ErrUnauthorized, "Incorrect Owner") // If not, throw an error
 	}
+	if id, found := k.GetMixnodeIDByIdentity(ctx, msg.PubKey); found && id != msg.ID {
+		return nil, sdkerrors.Wrap(types.ErrDuplicateIdentity, msg.PubKey)
+	}
+	if err := validateMixnodeParams(k.GetParams(ctx), msg.Layer, msg.Version); err != nil {
+		return nil, err
+	}
 
 	k.SetMixnode(ctx, mixnode)
 
+	ctx.EventManager().EmitEvents(sdk.Events{
+		mixnodeEvent(types.EventTypeUpdateMixnode, mixnode),
+		messageEvent(msg.Creator),
+	})
+
 	return &sdk.Result{Events: ctx.EventManager().Events()}, nil
 }
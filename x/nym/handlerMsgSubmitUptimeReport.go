@@ -0,0 +1,59 @@
+// Copyright 2020 Nym Technologies SA
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nym
+
+import (
+	"strconv"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+
+	"github.com/nymtech/nym/validator/nym/x/nym/keeper"
+	"github.com/nymtech/nym/validator/nym/x/nym/types"
+)
+
+func handleMsgSubmitUptimeReport(ctx sdk.Context, k keeper.Keeper, msg types.MsgSubmitUptimeReport) (*sdk.Result, error) {
+	if !k.GetParams(ctx).IsNetworkMonitor(msg.Monitor) {
+		return nil, sdkerrors.Wrap(types.ErrUnauthorizedMonitor, msg.Monitor.String())
+	}
+
+	epoch := k.CurrentEpoch(ctx)
+	for _, uptime := range msg.Reports {
+		if !k.MixnodeExists(ctx, uptime.NodeID) && !k.GatewayExists(ctx, uptime.NodeID) {
+			return nil, sdkerrors.Wrap(sdkerrors.ErrInvalidRequest, uptime.NodeID)
+		}
+		// the reputation of a node only changes once per monitor and epoch, however often its uptime gets submitted
+		if k.HasUptimeReport(ctx, epoch, uptime.NodeID, msg.Monitor) {
+			return nil, sdkerrors.Wrapf(types.ErrDuplicateUptimeReport, "node %s during epoch %d", uptime.NodeID, epoch)
+		}
+		k.SetUptimeReport(ctx, types.NewUptimeReport(epoch, msg.Monitor, uptime))
+		reputation, err := k.UpdateReputation(ctx, uptime.NodeID, types.ReputationChange(uptime))
+		if err != nil {
+			return nil, err
+		}
+
+		ctx.EventManager().EmitEvent(sdk.NewEvent(
+			types.EventTypeUptimeReport,
+			sdk.NewAttribute(types.AttributeKeyID, uptime.NodeID),
+			sdk.NewAttribute(types.AttributeKeyEpoch, strconv.FormatInt(epoch, 10)),
+			sdk.NewAttribute(types.AttributeKeyIPV4Uptime, strconv.Itoa(int(uptime.IPV4Uptime))),
+			sdk.NewAttribute(types.AttributeKeyIPV6Uptime, strconv.Itoa(int(uptime.IPV6Uptime))),
+			sdk.NewAttribute(types.AttributeKeyReputation, strconv.Itoa(int(reputation))),
+		))
+	}
+	ctx.EventManager().EmitEvent(messageEvent(msg.Monitor))
+
+	return &sdk.Result{Events: ctx.EventManager().Events()}, nil
+}
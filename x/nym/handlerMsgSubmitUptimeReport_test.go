@@ -0,0 +1,70 @@
+// Copyright 2020 Nym Technologies SA
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nym
+
+import (
+	"testing"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/nymtech/nym/validator/nym/x/nym/types"
+)
+
+var (
+	monitor      = sdk.AccAddress([]byte("monitor_____________"))
+	otherMonitor = sdk.AccAddress([]byte("other_monitor_______"))
+)
+
+func TestSubmitUptimeReportChangesReputationOncePerMonitorAndEpoch(t *testing.T) {
+	ctx, k, handler, _ := setupHandler(t)
+	params := k.GetParams(ctx)
+	params.NetworkMonitors = []sdk.AccAddress{monitor, otherMonitor}
+	k.SetParams(ctx, params)
+	_, err := handler(ctx, createMixnodeMsg(owner, "node", identityKey))
+	require.NoError(t, err)
+
+	uptime := types.NodeUptime{NodeID: "node", IPV4Uptime: 100, IPV6Uptime: 100}
+	report := types.NewMsgSubmitUptimeReport(monitor, []types.NodeUptime{uptime})
+	_, err = handler(ctx, report)
+	require.NoError(t, err)
+	reputation, err := k.GetReputation(ctx, "node")
+	require.NoError(t, err)
+	assert.Equal(t, 2*types.ReportSuccessReputationIncrease, reputation)
+
+	_, err = handler(ctx, report)
+	assert.True(t, types.ErrDuplicateUptimeReport.Is(err), err)
+	reputation, err = k.GetReputation(ctx, "node")
+	require.NoError(t, err)
+	assert.Equal(t, 2*types.ReportSuccessReputationIncrease, reputation)
+
+	// another monitor's report is kept alongside the first one
+	_, err = handler(ctx, types.NewMsgSubmitUptimeReport(otherMonitor, []types.NodeUptime{{NodeID: "node", IPV4Uptime: 0, IPV6Uptime: 100}}))
+	require.NoError(t, err)
+	reputation, err = k.GetReputation(ctx, "node")
+	require.NoError(t, err)
+	assert.Equal(t, 3*types.ReportSuccessReputationIncrease+types.ReportFailureReputationDecrease, reputation)
+	assert.Len(t, k.GetUptimeReports(ctx, k.CurrentEpoch(ctx), "node"), 2)
+	assert.Equal(t, sdk.NewDecWithPrec(75, 2), k.MixnodeUptime(ctx, k.CurrentEpoch(ctx), "node"))
+
+	// and the uptime can be reported again during the next epoch
+	ctx = ctx.WithBlockHeight(params.EpochLength)
+	_, err = handler(ctx, report)
+	require.NoError(t, err)
+	reputation, err = k.GetReputation(ctx, "node")
+	require.NoError(t, err)
+	assert.Equal(t, 5*types.ReportSuccessReputationIncrease+types.ReportFailureReputationDecrease, reputation)
+}
@@ -0,0 +1,40 @@
+// Copyright 2020 Nym Technologies SA
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nym
+
+import (
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/nymtech/nym/validator/nym/x/nym/keeper"
+	"github.com/nymtech/nym/validator/nym/x/nym/types"
+)
+
+func handleMsgUndelegate(ctx sdk.Context, k keeper.Keeper, msg types.MsgUndelegate) (*sdk.Result, error) {
+	if _, err := k.Undelegate(ctx, msg.MixnodeID, msg.Delegator, msg.Amount); err != nil {
+		return nil, err
+	}
+
+	ctx.EventManager().EmitEvents(sdk.Events{
+		sdk.NewEvent(
+			types.EventTypeUndelegate,
+			sdk.NewAttribute(types.AttributeKeyMixnodeID, msg.MixnodeID),
+			sdk.NewAttribute(types.AttributeKeyDelegator, msg.Delegator.String()),
+			sdk.NewAttribute(sdk.AttributeKeyAmount, msg.Amount.String()),
+		),
+		messageEvent(msg.Delegator),
+	})
+
+	return &sdk.Result{Events: ctx.EventManager().Events()}, nil
+}
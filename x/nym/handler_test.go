@@ -0,0 +1,210 @@
+// Copyright 2020 Nym Technologies SA
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nym
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/cosmos/cosmos-sdk/codec"
+	"github.com/cosmos/cosmos-sdk/store"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+	"github.com/cosmos/cosmos-sdk/x/params"
+	"github.com/cosmos/cosmos-sdk/x/supply"
+	supplyexported "github.com/cosmos/cosmos-sdk/x/supply/exported"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	abci "github.com/tendermint/tendermint/abci/types"
+	"github.com/tendermint/tendermint/libs/log"
+	dbm "github.com/tendermint/tm-db"
+
+	"github.com/nymtech/nym/validator/nym/x/nym/keeper"
+	"github.com/nymtech/nym/validator/nym/x/nym/types"
+)
+
+const (
+	identityKey      = "BnLYqQjb8K6TmW5oFdNZrUTocGxa3rgzBvapQrf8XUbF"
+	otherIdentityKey = "FioFa8nMmPpQnYi7JyojoTuwGLeyNS8BF4ChPr29zUML"
+)
+
+var (
+	owner = sdk.AccAddress([]byte("owner_______________"))
+	thief = sdk.AccAddress([]byte("thief_______________"))
+)
+
+// testSupplyKeeper keeps the balances of accounts and module accounts in its own store, so that they get rolled
+// back along with the rest of the state of cached contexts
+type testSupplyKeeper struct {
+	key sdk.StoreKey
+	cdc *codec.Codec
+	// failingRecipient is an account to which sending coins fails
+	failingRecipient sdk.AccAddress
+}
+
+func (sk *testSupplyKeeper) GetModuleAddress(name string) sdk.AccAddress {
+	return supply.NewModuleAddress(name)
+}
+
+func (sk *testSupplyKeeper) GetModuleAccount(ctx sdk.Context, name string) supplyexported.ModuleAccountI {
+	macc := supply.NewEmptyModuleAccount(name, supply.Minter)
+	if err := macc.SetCoins(sk.balance(ctx, macc.GetAddress())); err != nil {
+		panic(err)
+	}
+	return macc
+}
+
+func (sk *testSupplyKeeper) SetModuleAccount(ctx sdk.Context, macc supplyexported.ModuleAccountI) {
+	sk.setBalance(ctx, macc.GetAddress(), macc.GetCoins())
+}
+
+func (sk *testSupplyKeeper) SendCoinsFromAccountToModule(ctx sdk.Context, senderAddr sdk.AccAddress, recipientModule string, amt sdk.Coins) error {
+	return sk.send(ctx, senderAddr, supply.NewModuleAddress(recipientModule), amt)
+}
+
+func (sk *testSupplyKeeper) SendCoinsFromModuleToAccount(ctx sdk.Context, senderModule string, recipientAddr sdk.AccAddress, amt sdk.Coins) error {
+	return sk.send(ctx, supply.NewModuleAddress(senderModule), recipientAddr, amt)
+}
+
+func (sk *testSupplyKeeper) MintCoins(ctx sdk.Context, moduleName string, amt sdk.Coins) error {
+	module := supply.NewModuleAddress(moduleName)
+	sk.setBalance(ctx, module, sk.balance(ctx, module).Add(amt...))
+	return nil
+}
+
+func (sk *testSupplyKeeper) balance(ctx sdk.Context, addr sdk.AccAddress) sdk.Coins {
+	var coins sdk.Coins
+	if bz := ctx.KVStore(sk.key).Get(addr); bz != nil {
+		sk.cdc.MustUnmarshalBinaryBare(bz, &coins)
+	}
+	return coins
+}
+
+func (sk *testSupplyKeeper) setBalance(ctx sdk.Context, addr sdk.AccAddress, coins sdk.Coins) {
+	if coins.IsZero() {
+		ctx.KVStore(sk.key).Delete(addr)
+		return
+	}
+	ctx.KVStore(sk.key).Set(addr, sk.cdc.MustMarshalBinaryBare(coins))
+}
+
+func (sk *testSupplyKeeper) send(ctx sdk.Context, from sdk.AccAddress, to sdk.AccAddress, amt sdk.Coins) error {
+	if to.Equals(sk.failingRecipient) {
+		return sdkerrors.Wrapf(sdkerrors.ErrUnauthorized, "%s can't receive coins", to)
+	}
+	remaining, negative := sk.balance(ctx, from).SafeSub(amt)
+	if negative {
+		return sdkerrors.Wrapf(sdkerrors.ErrInsufficientFunds, "%s is smaller than %s", sk.balance(ctx, from), amt)
+	}
+	sk.setBalance(ctx, from, remaining)
+	sk.setBalance(ctx, to, sk.balance(ctx, to).Add(amt...))
+	return nil
+}
+
+// setupHandler returns a handler backed by an in-memory store with the default params, along with the supply keeper
+// holding the balances, in which the owner and the thief both start with 1000nym
+func setupHandler(t *testing.T) (sdk.Context, keeper.Keeper, sdk.Handler, *testSupplyKeeper) {
+	keyNym := sdk.NewKVStoreKey(types.StoreKey)
+	keySupply := sdk.NewKVStoreKey("testsupply")
+	keyParams := sdk.NewKVStoreKey(params.StoreKey)
+	tkeyParams := sdk.NewTransientStoreKey(params.TStoreKey)
+
+	db := dbm.NewMemDB()
+	ms := store.NewCommitMultiStore(db)
+	ms.MountStoreWithDB(keyNym, sdk.StoreTypeIAVL, db)
+	ms.MountStoreWithDB(keySupply, sdk.StoreTypeIAVL, db)
+	ms.MountStoreWithDB(keyParams, sdk.StoreTypeIAVL, db)
+	ms.MountStoreWithDB(tkeyParams, sdk.StoreTypeTransient, db)
+	require.NoError(t, ms.LoadLatestVersion())
+	ctx := sdk.NewContext(ms, abci.Header{}, false, log.NewNopLogger())
+
+	cdc := codec.New()
+	types.RegisterCodec(cdc)
+	supplyKeeper := &testSupplyKeeper{key: keySupply, cdc: cdc}
+	supplyKeeper.setBalance(ctx, owner, sdk.NewCoins(sdk.NewInt64Coin(types.DefaultBondDenom, 1000)))
+	supplyKeeper.setBalance(ctx, thief, sdk.NewCoins(sdk.NewInt64Coin(types.DefaultBondDenom, 1000)))
+	k := keeper.NewKeeper(nil, supplyKeeper, cdc, keyNym, params.NewSubspace(cdc, keyParams, tkeyParams, types.DefaultParamspace))
+	k.SetParams(ctx, types.DefaultParams())
+	return ctx, k, NewHandler(k), supplyKeeper
+}
+
+func createMixnodeMsg(creator sdk.AccAddress, id string, pubKey string) types.MsgCreateMixnode {
+	msg := types.NewMsgCreateMixnode(creator, pubKey, pubKey, 1, types.DefaultSystemVersion, "1.1.1.1:1789", "Neuchatel", types.DefaultMinimumMixnodeBond)
+	msg.ID = id
+	return msg
+}
+
+func createGatewayMsg(creator sdk.AccAddress, id string, identityKey string) types.MsgCreateGateway {
+	msg := types.NewMsgCreateGateway(creator, identityKey, identityKey, "ws://1.1.1.1:9000", "1.1.1.1:1789", "Neuchatel", types.DefaultMinimumGatewayBond)
+	msg.ID = id
+	return msg
+}
+
+func TestCreateMixnodeRejectsExistingID(t *testing.T) {
+	ctx, k, handler, supplyKeeper := setupHandler(t)
+	_, err := handler(ctx, createMixnodeMsg(owner, "node", identityKey))
+	require.NoError(t, err)
+
+	_, err = handler(ctx, createMixnodeMsg(thief, "node", otherIdentityKey))
+	assert.True(t, types.ErrDuplicateID.Is(err), err)
+
+	mixnode, err := k.GetMixnode(ctx, "node")
+	require.NoError(t, err)
+	assert.Equal(t, owner, mixnode.Creator)
+	assert.Equal(t, identityKey, mixnode.PubKey)
+	_, found := k.GetMixnodeIDByIdentity(ctx, otherIdentityKey)
+	assert.False(t, found)
+	assert.Equal(t, sdk.NewCoins(sdk.NewInt64Coin(types.DefaultBondDenom, 1000)), supplyKeeper.balance(ctx, thief))
+	assert.Equal(t, sdk.NewCoins(types.DefaultMinimumMixnodeBond), supplyKeeper.balance(ctx, supply.NewModuleAddress(types.ModuleName)))
+}
+
+func TestCreateGatewayRejectsExistingID(t *testing.T) {
+	ctx, k, handler, supplyKeeper := setupHandler(t)
+	_, err := handler(ctx, createGatewayMsg(owner, "node", identityKey))
+	require.NoError(t, err)
+
+	_, err = handler(ctx, createGatewayMsg(thief, "node", otherIdentityKey))
+	assert.True(t, types.ErrDuplicateID.Is(err), err)
+
+	gateway, err := k.GetGateway(ctx, "node")
+	require.NoError(t, err)
+	assert.Equal(t, owner, gateway.Creator)
+	assert.Equal(t, identityKey, gateway.IdentityKey)
+	_, found := k.GetGatewayIDByIdentity(ctx, otherIdentityKey)
+	assert.False(t, found)
+	assert.Equal(t, sdk.NewCoins(sdk.NewInt64Coin(types.DefaultBondDenom, 1000)), supplyKeeper.balance(ctx, thief))
+	assert.Equal(t, sdk.NewCoins(types.DefaultMinimumGatewayBond), supplyKeeper.balance(ctx, supply.NewModuleAddress(types.ModuleName)))
+}
+
+func TestCreateNodeRejectsIDOfOtherNodeType(t *testing.T) {
+	ctx, _, handler, _ := setupHandler(t)
+	_, err := handler(ctx, createMixnodeMsg(owner, "node", identityKey))
+	require.NoError(t, err)
+
+	_, err = handler(ctx, createGatewayMsg(thief, "node", otherIdentityKey))
+	assert.True(t, types.ErrDuplicateID.Is(err), err)
+}
+
+func TestCreateNodeRejectsInvalidID(t *testing.T) {
+	for _, id := range []string{"", "node/", "node/delegator", "node node", strings.Repeat("a", types.MaxIDLength+1)} {
+		err := createMixnodeMsg(owner, id, identityKey).ValidateBasic()
+		assert.True(t, types.ErrInvalidID.Is(err), "mixnode id %q: %v", id, err)
+		err = createGatewayMsg(owner, id, identityKey).ValidateBasic()
+		assert.True(t, types.ErrInvalidID.Is(err), "gateway id %q: %v", id, err)
+	}
+
+	assert.NoError(t, types.NewMsgCreateMixnode(owner, identityKey, identityKey, 1, types.DefaultSystemVersion, "1.1.1.1:1789", "Neuchatel", types.DefaultMinimumMixnodeBond).ValidateBasic())
+	assert.NoError(t, types.NewMsgCreateGateway(owner, identityKey, identityKey, "ws://1.1.1.1:9000", "1.1.1.1:1789", "Neuchatel", types.DefaultMinimumGatewayBond).ValidateBasic())
+}
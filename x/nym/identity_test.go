@@ -0,0 +1,82 @@
+// Copyright 2020 Nym Technologies SA
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nym
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/nymtech/nym/validator/nym/x/nym/types"
+)
+
+func TestMixnodeIdentityIndexFollowsUpdatesAndDeletion(t *testing.T) {
+	ctx, k, handler, _ := setupHandler(t)
+	_, err := handler(ctx, createMixnodeMsg(owner, "node", identityKey))
+	require.NoError(t, err)
+
+	_, err = handler(ctx, types.NewMsgSetMixnode(owner, "node", otherIdentityKey, otherIdentityKey, 1, types.DefaultSystemVersion, "1.1.1.1:1789", "Neuchatel"))
+	require.NoError(t, err)
+	_, found := k.GetMixnodeIDByIdentity(ctx, identityKey)
+	assert.False(t, found)
+	id, found := k.GetMixnodeIDByIdentity(ctx, otherIdentityKey)
+	assert.True(t, found)
+	assert.Equal(t, "node", id)
+
+	// the previous identity key is free again while the new one can't be taken
+	_, err = handler(ctx, createMixnodeMsg(thief, "other", identityKey))
+	require.NoError(t, err)
+	_, err = handler(ctx, types.NewMsgSetMixnode(thief, "other", otherIdentityKey, otherIdentityKey, 1, types.DefaultSystemVersion, "1.1.1.1:1789", "Neuchatel"))
+	assert.True(t, types.ErrDuplicateIdentity.Is(err), err)
+	id, _ = k.GetMixnodeIDByIdentity(ctx, identityKey)
+	assert.Equal(t, "other", id)
+
+	_, err = handler(ctx, types.NewMsgDeleteMixnode("node", owner))
+	require.NoError(t, err)
+	_, found = k.GetMixnodeIDByIdentity(ctx, otherIdentityKey)
+	assert.False(t, found)
+	id, _ = k.GetMixnodeIDByIdentity(ctx, identityKey)
+	assert.Equal(t, "other", id)
+}
+
+func TestGatewayIdentityIndexFollowsUpdatesAndDeletion(t *testing.T) {
+	ctx, k, handler, _ := setupHandler(t)
+	_, err := handler(ctx, createGatewayMsg(owner, "node", identityKey))
+	require.NoError(t, err)
+
+	_, err = handler(ctx, types.NewMsgSetGateway(owner, "node", otherIdentityKey, otherIdentityKey, "ws://1.1.1.1:9000", "1.1.1.1:1789", "Neuchatel"))
+	require.NoError(t, err)
+	_, found := k.GetGatewayIDByIdentity(ctx, identityKey)
+	assert.False(t, found)
+	id, found := k.GetGatewayIDByIdentity(ctx, otherIdentityKey)
+	assert.True(t, found)
+	assert.Equal(t, "node", id)
+
+	// the previous identity key is free again while the new one can't be taken
+	_, err = handler(ctx, createGatewayMsg(thief, "other", identityKey))
+	require.NoError(t, err)
+	_, err = handler(ctx, types.NewMsgSetGateway(thief, "other", otherIdentityKey, otherIdentityKey, "ws://1.1.1.1:9000", "1.1.1.1:1789", "Neuchatel"))
+	assert.True(t, types.ErrDuplicateIdentity.Is(err), err)
+	id, _ = k.GetGatewayIDByIdentity(ctx, identityKey)
+	assert.Equal(t, "other", id)
+
+	_, err = handler(ctx, types.NewMsgDeleteGateway("node", owner))
+	require.NoError(t, err)
+	_, found = k.GetGatewayIDByIdentity(ctx, otherIdentityKey)
+	assert.False(t, found)
+	id, _ = k.GetGatewayIDByIdentity(ctx, identityKey)
+	assert.Equal(t, "other", id)
+}
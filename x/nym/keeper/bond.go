@@ -0,0 +1,42 @@
+// Copyright 2020 Nym Technologies SA
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package keeper
+
+import (
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	supplyexported "github.com/cosmos/cosmos-sdk/x/supply/exported"
+	"github.com/nymtech/nym/validator/nym/x/nym/types"
+)
+
+// GetModuleAccount returns the nym module account holding all escrowed bonds
+func (k Keeper) GetModuleAccount(ctx sdk.Context) supplyexported.ModuleAccountI {
+	return k.SupplyKeeper.GetModuleAccount(ctx, types.ModuleName)
+}
+
+// Bond escrows the provided amount from the given account into the nym module account
+func (k Keeper) Bond(ctx sdk.Context, from sdk.AccAddress, amount sdk.Coin) error {
+	if amount.IsZero() {
+		return nil
+	}
+	return k.SupplyKeeper.SendCoinsFromAccountToModule(ctx, from, types.ModuleName, sdk.NewCoins(amount))
+}
+
+// Unbond returns the provided amount from the nym module account back to the given account
+func (k Keeper) Unbond(ctx sdk.Context, to sdk.AccAddress, amount sdk.Coin) error {
+	if amount.IsZero() {
+		return nil
+	}
+	return k.SupplyKeeper.SendCoinsFromModuleToAccount(ctx, types.ModuleName, to, sdk.NewCoins(amount))
+}
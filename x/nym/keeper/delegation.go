@@ -0,0 +1,164 @@
+// Copyright 2020 Nym Technologies SA
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package keeper
+
+import (
+	"github.com/cosmos/cosmos-sdk/codec"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+
+	"github.com/nymtech/nym/validator/nym/x/nym/types"
+)
+
+// GetDelegation returns the delegation made by delegator to the given mixnode
+func (k Keeper) GetDelegation(ctx sdk.Context, mixnodeID string, delegator sdk.AccAddress) (types.Delegation, bool) {
+	store := ctx.KVStore(k.storeKey)
+	var delegation types.Delegation
+	bz := store.Get(types.DelegationKey(mixnodeID, delegator))
+	if bz == nil {
+		return delegation, false
+	}
+	k.cdc.MustUnmarshalBinaryLengthPrefixed(bz, &delegation)
+	return delegation, true
+}
+
+// SetDelegation stores the delegation and indexes it by its delegator
+func (k Keeper) SetDelegation(ctx sdk.Context, delegation types.Delegation) {
+	store := ctx.KVStore(k.storeKey)
+	key := types.DelegationKey(delegation.MixnodeID, delegation.Delegator)
+	store.Set(key, k.cdc.MustMarshalBinaryLengthPrefixed(delegation))
+	store.Set(types.DelegatorDelegationKey(delegation.Delegator, delegation.MixnodeID), key)
+}
+
+// RemoveDelegation removes the delegation together with its delegator index entry
+func (k Keeper) RemoveDelegation(ctx sdk.Context, delegation types.Delegation) {
+	store := ctx.KVStore(k.storeKey)
+	store.Delete(types.DelegationKey(delegation.MixnodeID, delegation.Delegator))
+	store.Delete(types.DelegatorDelegationKey(delegation.Delegator, delegation.MixnodeID))
+}
+
+// GetMixnodeDelegations returns all delegations made to the given mixnode
+func (k Keeper) GetMixnodeDelegations(ctx sdk.Context, mixnodeID string) []types.Delegation {
+	return k.iterateDelegations(ctx, types.MixnodeDelegationsKey(mixnodeID))
+}
+
+// GetAllDelegations returns every delegation in the store
+func (k Keeper) GetAllDelegations(ctx sdk.Context) []types.Delegation {
+	return k.iterateDelegations(ctx, []byte(types.DelegationPrefix))
+}
+
+func (k Keeper) iterateDelegations(ctx sdk.Context, prefix []byte) []types.Delegation {
+	delegations := []types.Delegation{}
+	store := ctx.KVStore(k.storeKey)
+	iterator := sdk.KVStorePrefixIterator(store, prefix)
+	defer iterator.Close()
+	for ; iterator.Valid(); iterator.Next() {
+		var delegation types.Delegation
+		k.cdc.MustUnmarshalBinaryLengthPrefixed(iterator.Value(), &delegation)
+		delegations = append(delegations, delegation)
+	}
+	return delegations
+}
+
+// GetDelegatorDelegations returns all delegations made by the given delegator
+func (k Keeper) GetDelegatorDelegations(ctx sdk.Context, delegator sdk.AccAddress) []types.Delegation {
+	delegations := []types.Delegation{}
+	store := ctx.KVStore(k.storeKey)
+	iterator := sdk.KVStorePrefixIterator(store, types.DelegatorDelegationsKey(delegator))
+	defer iterator.Close()
+	for ; iterator.Valid(); iterator.Next() {
+		var delegation types.Delegation
+		k.cdc.MustUnmarshalBinaryLengthPrefixed(store.Get(iterator.Value()), &delegation)
+		delegations = append(delegations, delegation)
+	}
+	return delegations
+}
+
+// TotalDelegated returns the sum of all coins delegated to the given mixnode
+func (k Keeper) TotalDelegated(ctx sdk.Context, mixnodeID string) sdk.Coins {
+	total := sdk.NewCoins()
+	for _, delegation := range k.GetMixnodeDelegations(ctx, mixnodeID) {
+		total = total.Add(delegation.Amount)
+	}
+	return total
+}
+
+// Delegate escrows amount from the delegator and adds it to their delegation to the given mixnode
+func (k Keeper) Delegate(ctx sdk.Context, mixnodeID string, delegator sdk.AccAddress, amount sdk.Coin) (types.Delegation, error) {
+	if err := k.Bond(ctx, delegator, amount); err != nil {
+		return types.Delegation{}, err
+	}
+
+	delegation, found := k.GetDelegation(ctx, mixnodeID, delegator)
+	if found {
+		delegation.Amount = delegation.Amount.Add(amount)
+	} else {
+		delegation = types.NewDelegation(mixnodeID, delegator, amount)
+	}
+	k.SetDelegation(ctx, delegation)
+	return delegation, nil
+}
+
+// Undelegate returns amount from the delegation to the given mixnode back to the delegator.
+// The delegation is removed once nothing is left in it.
+func (k Keeper) Undelegate(ctx sdk.Context, mixnodeID string, delegator sdk.AccAddress, amount sdk.Coin) (types.Delegation, error) {
+	delegation, found := k.GetDelegation(ctx, mixnodeID, delegator)
+	if !found {
+		return delegation, sdkerrors.Wrapf(types.ErrNoDelegation, "%s has not delegated to mixnode %s", delegator, mixnodeID)
+	}
+	if delegation.Amount.Denom != amount.Denom || delegation.Amount.IsLT(amount) {
+		return delegation, sdkerrors.Wrapf(types.ErrInsufficientDelegation, "cannot undelegate %s out of %s", amount, delegation.Amount)
+	}
+	if err := k.Unbond(ctx, delegator, amount); err != nil {
+		return delegation, err
+	}
+
+	delegation.Amount = delegation.Amount.Sub(amount)
+	if delegation.Amount.IsZero() {
+		k.RemoveDelegation(ctx, delegation)
+	} else {
+		k.SetDelegation(ctx, delegation)
+	}
+	return delegation, nil
+}
+
+// RefundDelegations returns every delegation made to the given mixnode back to its delegator
+func (k Keeper) RefundDelegations(ctx sdk.Context, mixnodeID string) error {
+	for _, delegation := range k.GetMixnodeDelegations(ctx, mixnodeID) {
+		if err := k.Unbond(ctx, delegation.Delegator, delegation.Amount); err != nil {
+			return err
+		}
+		k.RemoveDelegation(ctx, delegation)
+	}
+	return nil
+}
+
+//
+// Functions used by querier
+//
+
+func listMixnodeDelegations(ctx sdk.Context, path []string, k Keeper) ([]byte, error) {
+	res := codec.MustMarshalJSONIndent(k.cdc, k.GetMixnodeDelegations(ctx, path[0]))
+	return res, nil
+}
+
+func listDelegatorDelegations(ctx sdk.Context, path []string, k Keeper) ([]byte, error) {
+	delegator, err := sdk.AccAddressFromBech32(path[0])
+	if err != nil {
+		return nil, sdkerrors.Wrap(sdkerrors.ErrInvalidAddress, err.Error())
+	}
+	res := codec.MustMarshalJSONIndent(k.cdc, k.GetDelegatorDelegations(ctx, delegator))
+	return res, nil
+}
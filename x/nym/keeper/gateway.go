@@ -3,6 +3,7 @@ package keeper
 import (
 	sdk "github.com/cosmos/cosmos-sdk/types"
 	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+	abci "github.com/tendermint/tendermint/abci/types"
 
 	"github.com/cosmos/cosmos-sdk/codec"
 	"github.com/nymtech/nym/validator/nym/x/nym/types"
@@ -14,6 +15,7 @@ func (k Keeper) CreateGateway(ctx sdk.Context, gateway types.Gateway) {
 	key := []byte(types.GatewayPrefix + gateway.ID)
 	value := k.cdc.MustMarshalBinaryLengthPrefixed(gateway)
 	store.Set(key, value)
+	store.Set([]byte(types.GatewayIdentityPrefix+gateway.IdentityKey), []byte(gateway.ID))
 }
 
 // GetGateway returns the gateway information
@@ -32,6 +34,10 @@ func (k Keeper) GetGateway(ctx sdk.Context, key string) (types.Gateway, error) {
 func (k Keeper) SetGateway(ctx sdk.Context, gateway types.Gateway) {
 	gatewayKey := gateway.ID
 	store := ctx.KVStore(k.storeKey)
+	if existing, err := k.GetGateway(ctx, gatewayKey); err == nil && existing.IdentityKey != gateway.IdentityKey {
+		store.Delete([]byte(types.GatewayIdentityPrefix + existing.IdentityKey))
+	}
+	store.Set([]byte(types.GatewayIdentityPrefix+gateway.IdentityKey), []byte(gatewayKey))
 	bz := k.cdc.MustMarshalBinaryLengthPrefixed(gateway)
 	key := []byte(types.GatewayPrefix + gatewayKey)
 	store.Set(key, bz)
@@ -40,23 +46,77 @@ func (k Keeper) SetGateway(ctx sdk.Context, gateway types.Gateway) {
 // DeleteGateway deletes a gateway
 func (k Keeper) DeleteGateway(ctx sdk.Context, key string) {
 	store := ctx.KVStore(k.storeKey)
+	if gateway, err := k.GetGateway(ctx, key); err == nil {
+		store.Delete([]byte(types.GatewayIdentityPrefix + gateway.IdentityKey))
+	}
 	store.Delete([]byte(types.GatewayPrefix + key))
 }
 
+// GetAllGateways returns every gateway in the store
+func (k Keeper) GetAllGateways(ctx sdk.Context) []types.Gateway {
+	gatewayList := []types.Gateway{}
+	store := ctx.KVStore(k.storeKey)
+	iterator := sdk.KVStorePrefixIterator(store, []byte(types.GatewayPrefix))
+	defer iterator.Close()
+	for ; iterator.Valid(); iterator.Next() {
+		var gateway types.Gateway
+		k.cdc.MustUnmarshalBinaryLengthPrefixed(iterator.Value(), &gateway)
+		gatewayList = append(gatewayList, gateway)
+	}
+	return gatewayList
+}
+
+// GetGatewayIDByIdentity returns the ID of the gateway registered with the given identity key
+func (k Keeper) GetGatewayIDByIdentity(ctx sdk.Context, identityKey string) (string, bool) {
+	store := ctx.KVStore(k.storeKey)
+	id := store.Get([]byte(types.GatewayIdentityPrefix + identityKey))
+	if id == nil {
+		return "", false
+	}
+	return string(id), true
+}
+
 //
 // Functions used by querier
 //
 
-func listGateway(ctx sdk.Context, k Keeper) ([]byte, error) {
-	var gatewayList []types.Gateway
+func listGateway(ctx sdk.Context, req abci.RequestQuery, k Keeper) ([]byte, error) {
+	var params types.QueryGatewaysParams
+	if len(req.Data) != 0 {
+		if err := k.cdc.UnmarshalJSON(req.Data, &params); err != nil {
+			return nil, sdkerrors.Wrap(sdkerrors.ErrJSONUnmarshal, err.Error())
+		}
+	}
+
+	gateways := []types.Gateway{}
+	offset, count, ok := pageRange(params.Page, params.Limit)
+	if !ok {
+		return codec.MustMarshalJSONIndent(k.cdc, gateways), nil
+	}
+
+	// page through the store rather than loading every gateway, stopping as soon as the page is full
 	store := ctx.KVStore(k.storeKey)
 	iterator := sdk.KVStorePrefixIterator(store, []byte(types.GatewayPrefix))
-	for ; iterator.Valid(); iterator.Next() {
+	defer iterator.Close()
+	for ; iterator.Valid() && len(gateways) < count; iterator.Next() {
+		// without filters, every gateway matches and skipped ones need not be unmarshalled
+		if offset > 0 && !params.IsFiltered() {
+			offset--
+			continue
+		}
 		var gateway types.Gateway
-		k.cdc.MustUnmarshalBinaryLengthPrefixed(store.Get(iterator.Key()), &gateway)
-		gatewayList = append(gatewayList, gateway)
+		k.cdc.MustUnmarshalBinaryLengthPrefixed(iterator.Value(), &gateway)
+		if !params.Matches(gateway) {
+			continue
+		}
+		if offset > 0 {
+			offset--
+			continue
+		}
+		gateways = append(gateways, gateway)
 	}
-	res := codec.MustMarshalJSONIndent(k.cdc, gatewayList)
+
+	res := codec.MustMarshalJSONIndent(k.cdc, gateways)
 	return res, nil
 }
 
@@ -75,6 +135,14 @@ func getGateway(ctx sdk.Context, path []string, k Keeper) (res []byte, sdkError
 	return res, nil
 }
 
+func getGatewayByIdentity(ctx sdk.Context, path []string, k Keeper) ([]byte, error) {
+	id, found := k.GetGatewayIDByIdentity(ctx, path[0])
+	if !found {
+		return nil, sdkerrors.Wrapf(sdkerrors.ErrUnknownRequest, "no gateway with identity key %s", path[0])
+	}
+	return getGateway(ctx, []string{id}, k)
+}
+
 // Get creator of the item
 func (k Keeper) GetGatewayOwner(ctx sdk.Context, key string) sdk.AccAddress {
 	gateway, err := k.GetGateway(ctx, key)
@@ -84,6 +152,18 @@ func (k Keeper) GetGatewayOwner(ctx sdk.Context, key string) sdk.AccAddress {
 	return gateway.Creator
 }
 
+// GatewayCount returns the number of gateways currently in the store
+func (k Keeper) GatewayCount(ctx sdk.Context) uint32 {
+	count := uint32(0)
+	store := ctx.KVStore(k.storeKey)
+	iterator := sdk.KVStorePrefixIterator(store, []byte(types.GatewayPrefix))
+	defer iterator.Close()
+	for ; iterator.Valid(); iterator.Next() {
+		count++
+	}
+	return count
+}
+
 // Check if the key exists in the store
 func (k Keeper) GatewayExists(ctx sdk.Context, key string) bool {
 	store := ctx.KVStore(k.storeKey)
@@ -27,19 +27,26 @@ import (
 
 // Keeper of the nym store
 type Keeper struct {
-	CoinKeeper bank.Keeper
-	storeKey   sdk.StoreKey
-	cdc        *codec.Codec
-	// paramspace types.ParamSubspace
+	CoinKeeper   bank.Keeper
+	SupplyKeeper types.SupplyKeeper
+	storeKey     sdk.StoreKey
+	cdc          *codec.Codec
+	paramspace   types.ParamSubspace
 }
 
 // NewKeeper creates a nym keeper
-func NewKeeper(coinKeeper bank.Keeper, cdc *codec.Codec, key sdk.StoreKey) Keeper {
+func NewKeeper(coinKeeper bank.Keeper, supplyKeeper types.SupplyKeeper, cdc *codec.Codec, key sdk.StoreKey, paramspace types.ParamSubspace) Keeper {
+	// ensure the nym module account is set
+	if addr := supplyKeeper.GetModuleAddress(types.ModuleName); addr == nil {
+		panic(fmt.Sprintf("%s module account has not been set", types.ModuleName))
+	}
+
 	keeper := Keeper{
-		CoinKeeper: coinKeeper,
-		storeKey:   key,
-		cdc:        cdc,
-		// paramspace: paramspace.WithKeyTable(types.ParamKeyTable()),
+		CoinKeeper:   coinKeeper,
+		SupplyKeeper: supplyKeeper,
+		storeKey:     key,
+		cdc:          cdc,
+		paramspace:   paramspace.WithKeyTable(types.ParamKeyTable()),
 	}
 	return keeper
 }
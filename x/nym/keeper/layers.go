@@ -0,0 +1,80 @@
+// Copyright 2020 Nym Technologies SA
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package keeper
+
+import (
+	"strconv"
+
+	"github.com/cosmos/cosmos-sdk/codec"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/nymtech/nym/validator/nym/x/nym/types"
+)
+
+// ProposeLayerRebalance returns the moves that would balance the stake of the mixnodes across the layers,
+// without applying them
+func (k Keeper) ProposeLayerRebalance(ctx sdk.Context) types.LayerRebalance {
+	return types.NewLayerRebalance(k.layerNodes(ctx), k.GetParams(ctx).Layers)
+}
+
+// RebalanceLayers moves mixnodes between layers to balance their number and stake, returning the moves made
+func (k Keeper) RebalanceLayers(ctx sdk.Context) []types.LayerMove {
+	moves := types.BalanceLayers(k.layerNodes(ctx), k.GetParams(ctx).Layers)
+	for _, move := range moves {
+		mixnode, err := k.GetMixnode(ctx, move.ID)
+		if err != nil {
+			continue
+		}
+		mixnode.Layer = int32(move.To)
+		k.SetMixnode(ctx, mixnode)
+
+		ctx.EventManager().EmitEvent(sdk.NewEvent(
+			types.EventTypeChangeLayer,
+			sdk.NewAttribute(types.AttributeKeyID, mixnode.ID),
+			sdk.NewAttribute(types.AttributeKeyIdentityKey, mixnode.PubKey),
+			sdk.NewAttribute(types.AttributeKeyPreviousLayer, strconv.FormatUint(uint64(move.From), 10)),
+			sdk.NewAttribute(types.AttributeKeyLayer, strconv.FormatUint(uint64(move.To), 10)),
+		))
+	}
+	return moves
+}
+
+// layerNodes returns the mixnodes weighted by their total stake
+func (k Keeper) layerNodes(ctx sdk.Context) []types.LayerNode {
+	denom := k.GetParams(ctx).MinimumMixnodeBond.Denom
+	mixnodes := k.GetAllMixnodes(ctx)
+	nodes := make([]types.LayerNode, len(mixnodes))
+	for i, mixnode := range mixnodes {
+		layer := uint32(0) // invalid layers are always rebalanced
+		if mixnode.Layer > 0 {
+			layer = uint32(mixnode.Layer)
+		}
+		nodes[i] = types.LayerNode{
+			ID:     mixnode.ID,
+			Layer:  layer,
+			Weight: k.MixnodeStake(ctx, mixnode, denom),
+		}
+	}
+	return nodes
+}
+
+//
+// Functions used by querier
+//
+
+func getLayerRebalance(ctx sdk.Context, k Keeper) ([]byte, error) {
+	res := codec.MustMarshalJSONIndent(k.cdc, k.ProposeLayerRebalance(ctx))
+	return res, nil
+}
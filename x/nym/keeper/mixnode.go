@@ -17,6 +17,7 @@ package keeper
 import (
 	sdk "github.com/cosmos/cosmos-sdk/types"
 	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+	abci "github.com/tendermint/tendermint/abci/types"
 
 	"github.com/cosmos/cosmos-sdk/codec"
 	"github.com/nymtech/nym/validator/nym/x/nym/types"
@@ -28,6 +29,7 @@ func (k Keeper) CreateMixnode(ctx sdk.Context, mixnode types.Mixnode) {
 	key := []byte(types.MixnodePrefix + mixnode.ID)
 	value := k.cdc.MustMarshalBinaryLengthPrefixed(mixnode)
 	store.Set(key, value)
+	store.Set([]byte(types.MixnodeIdentityPrefix+mixnode.PubKey), []byte(mixnode.ID))
 }
 
 // GetMixnode returns the mixnode information
@@ -46,6 +48,10 @@ func (k Keeper) GetMixnode(ctx sdk.Context, key string) (types.Mixnode, error) {
 func (k Keeper) SetMixnode(ctx sdk.Context, mixnode types.Mixnode) {
 	mixnodeKey := mixnode.ID
 	store := ctx.KVStore(k.storeKey)
+	if existing, err := k.GetMixnode(ctx, mixnodeKey); err == nil && existing.PubKey != mixnode.PubKey {
+		store.Delete([]byte(types.MixnodeIdentityPrefix + existing.PubKey))
+	}
+	store.Set([]byte(types.MixnodeIdentityPrefix+mixnode.PubKey), []byte(mixnodeKey))
 	bz := k.cdc.MustMarshalBinaryLengthPrefixed(mixnode)
 	key := []byte(types.MixnodePrefix + mixnodeKey)
 	store.Set(key, bz)
@@ -54,23 +60,77 @@ func (k Keeper) SetMixnode(ctx sdk.Context, mixnode types.Mixnode) {
 // DeleteMixnode deletes a mixnode
 func (k Keeper) DeleteMixnode(ctx sdk.Context, key string) {
 	store := ctx.KVStore(k.storeKey)
+	if mixnode, err := k.GetMixnode(ctx, key); err == nil {
+		store.Delete([]byte(types.MixnodeIdentityPrefix + mixnode.PubKey))
+	}
 	store.Delete([]byte(types.MixnodePrefix + key))
 }
 
+// GetAllMixnodes returns every mixnode in the store
+func (k Keeper) GetAllMixnodes(ctx sdk.Context) []types.Mixnode {
+	mixnodeList := []types.Mixnode{}
+	store := ctx.KVStore(k.storeKey)
+	iterator := sdk.KVStorePrefixIterator(store, []byte(types.MixnodePrefix))
+	defer iterator.Close()
+	for ; iterator.Valid(); iterator.Next() {
+		var mixnode types.Mixnode
+		k.cdc.MustUnmarshalBinaryLengthPrefixed(iterator.Value(), &mixnode)
+		mixnodeList = append(mixnodeList, mixnode)
+	}
+	return mixnodeList
+}
+
+// GetMixnodeIDByIdentity returns the ID of the mixnode registered with the given identity key
+func (k Keeper) GetMixnodeIDByIdentity(ctx sdk.Context, identityKey string) (string, bool) {
+	store := ctx.KVStore(k.storeKey)
+	id := store.Get([]byte(types.MixnodeIdentityPrefix + identityKey))
+	if id == nil {
+		return "", false
+	}
+	return string(id), true
+}
+
 //
 // Functions used by querier
 //
 
-func listMixnode(ctx sdk.Context, k Keeper) ([]byte, error) {
-	var mixnodeList []types.Mixnode
+func listMixnode(ctx sdk.Context, req abci.RequestQuery, k Keeper) ([]byte, error) {
+	var params types.QueryMixnodesParams
+	if len(req.Data) != 0 {
+		if err := k.cdc.UnmarshalJSON(req.Data, &params); err != nil {
+			return nil, sdkerrors.Wrap(sdkerrors.ErrJSONUnmarshal, err.Error())
+		}
+	}
+
+	mixnodes := []types.Mixnode{}
+	offset, count, ok := pageRange(params.Page, params.Limit)
+	if !ok {
+		return codec.MustMarshalJSONIndent(k.cdc, mixnodes), nil
+	}
+
+	// page through the store rather than loading every mixnode, stopping as soon as the page is full
 	store := ctx.KVStore(k.storeKey)
 	iterator := sdk.KVStorePrefixIterator(store, []byte(types.MixnodePrefix))
-	for ; iterator.Valid(); iterator.Next() {
+	defer iterator.Close()
+	for ; iterator.Valid() && len(mixnodes) < count; iterator.Next() {
+		// without filters, every mixnode matches and skipped ones need not be unmarshalled
+		if offset > 0 && !params.IsFiltered() {
+			offset--
+			continue
+		}
 		var mixnode types.Mixnode
-		k.cdc.MustUnmarshalBinaryLengthPrefixed(store.Get(iterator.Key()), &mixnode)
-		mixnodeList = append(mixnodeList, mixnode)
+		k.cdc.MustUnmarshalBinaryLengthPrefixed(iterator.Value(), &mixnode)
+		if !params.Matches(mixnode) {
+			continue
+		}
+		if offset > 0 {
+			offset--
+			continue
+		}
+		mixnodes = append(mixnodes, mixnode)
 	}
-	res := codec.MustMarshalJSONIndent(k.cdc, mixnodeList)
+
+	res := codec.MustMarshalJSONIndent(k.cdc, mixnodes)
 	return res, nil
 }
 
@@ -89,6 +149,14 @@ func getMixnode(ctx sdk.Context, path []string, k Keeper) (res []byte, sdkError
 	return res, nil
 }
 
+func getMixnodeByIdentity(ctx sdk.Context, path []string, k Keeper) ([]byte, error) {
+	id, found := k.GetMixnodeIDByIdentity(ctx, path[0])
+	if !found {
+		return nil, sdkerrors.Wrapf(sdkerrors.ErrUnknownRequest, "no mixnode with identity key %s", path[0])
+	}
+	return getMixnode(ctx, []string{id}, k)
+}
+
 // Get creator of the item
 func (k Keeper) GetMixnodeOwner(ctx sdk.Context, key string) sdk.AccAddress {
 	mixnode, err := k.GetMixnode(ctx, key)
@@ -98,6 +166,18 @@ func (k Keeper) GetMixnodeOwner(ctx sdk.Context, key string) sdk.AccAddress {
 	return mixnode.Creator
 }
 
+// MixnodeCount returns the number of mixnodes currently in the store
+func (k Keeper) MixnodeCount(ctx sdk.Context) uint32 {
+	count := uint32(0)
+	store := ctx.KVStore(k.storeKey)
+	iterator := sdk.KVStorePrefixIterator(store, []byte(types.MixnodePrefix))
+	defer iterator.Close()
+	for ; iterator.Valid(); iterator.Next() {
+		count++
+	}
+	return count
+}
+
 // Check if the key exists in the store
 func (k Keeper) MixnodeExists(ctx sdk.Context, key string) bool {
 	store := ctx.KVStore(k.storeKey)
@@ -30,15 +30,49 @@ func NewQuerier(k Keeper) sdk.Querier {
 		switch path[0] {
 		// this line is used by starport scaffolding # 2
 		case types.QueryListGateway:
-			return listGateway(ctx, k)
+			return listGateway(ctx, req, k)
 		case types.QueryGetGateway:
 			return getGateway(ctx, path[1:], k)
+		case types.QueryGetGatewayByIdentity:
+			return getGatewayByIdentity(ctx, path[1:], k)
 		case types.QueryListMixnode:
-			return listMixnode(ctx, k)
+			return listMixnode(ctx, req, k)
 		case types.QueryGetMixnode:
 			return getMixnode(ctx, path[1:], k)
+		case types.QueryGetMixnodeByIdentity:
+			return getMixnodeByIdentity(ctx, path[1:], k)
+		case types.QueryParams:
+			return queryParams(ctx, k)
+		case types.QueryListMixnodeDelegations:
+			return listMixnodeDelegations(ctx, path[1:], k)
+		case types.QueryListDelegatorDelegations:
+			return listDelegatorDelegations(ctx, path[1:], k)
+		case types.QueryGetUptime:
+			return getUptime(ctx, path[1:], k)
+		case types.QueryGetReputation:
+			return getReputation(ctx, path[1:], k)
+		case types.QueryTopology:
+			return getTopology(ctx, k)
+		case types.QueryActiveTopology:
+			return getActiveTopology(ctx, k)
+		case types.QueryLayerRebalance:
+			return getLayerRebalance(ctx, k)
 		default:
 			return nil, sdkerrors.Wrap(sdkerrors.ErrUnknownRequest, "unknown nym query endpoint")
 		}
 	}
 }
+
+// pageRange returns the number of matching nodes to skip and to return for the given page, starting at 1, and limit,
+// which defaults to types.DefaultQueryLimit and is capped at types.MaxQueryLimit. ok is false for invalid pages.
+func pageRange(page, limit int) (offset int, count int, ok bool) {
+	if page < 1 || limit < 0 {
+		return 0, 0, false
+	}
+	if limit == 0 {
+		limit = types.DefaultQueryLimit
+	} else if limit > types.MaxQueryLimit {
+		limit = types.MaxQueryLimit
+	}
+	return (page - 1) * limit, limit, true
+}
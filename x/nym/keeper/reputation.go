@@ -0,0 +1,84 @@
+// Copyright 2020 Nym Technologies SA
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package keeper
+
+import (
+	"github.com/cosmos/cosmos-sdk/codec"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+
+	"github.com/nymtech/nym/validator/nym/x/nym/types"
+)
+
+// GetReputation returns the reputation of the mixnode or gateway with the given ID
+func (k Keeper) GetReputation(ctx sdk.Context, nodeID string) (int32, error) {
+	if k.MixnodeExists(ctx, nodeID) {
+		mixnode, err := k.GetMixnode(ctx, nodeID)
+		if err != nil {
+			return 0, err
+		}
+		return mixnode.Reputation, nil
+	}
+	if k.GatewayExists(ctx, nodeID) {
+		gateway, err := k.GetGateway(ctx, nodeID)
+		if err != nil {
+			return 0, err
+		}
+		return gateway.Reputation, nil
+	}
+	return 0, sdkerrors.Wrap(sdkerrors.ErrInvalidRequest, nodeID)
+}
+
+// UpdateReputation changes the reputation of the mixnode or gateway with the given ID,
+// never letting it become negative. It returns the new reputation.
+func (k Keeper) UpdateReputation(ctx sdk.Context, nodeID string, change int32) (int32, error) {
+	if k.MixnodeExists(ctx, nodeID) {
+		mixnode, err := k.GetMixnode(ctx, nodeID)
+		if err != nil {
+			return 0, err
+		}
+		mixnode.Reputation = types.ApplyReputationChange(mixnode.Reputation, change)
+		k.SetMixnode(ctx, mixnode)
+		return mixnode.Reputation, nil
+	}
+	if k.GatewayExists(ctx, nodeID) {
+		gateway, err := k.GetGateway(ctx, nodeID)
+		if err != nil {
+			return 0, err
+		}
+		gateway.Reputation = types.ApplyReputationChange(gateway.Reputation, change)
+		k.SetGateway(ctx, gateway)
+		return gateway.Reputation, nil
+	}
+	return 0, sdkerrors.Wrap(sdkerrors.ErrInvalidRequest, nodeID)
+}
+
+//
+// Functions used by querier
+//
+
+func getReputation(ctx sdk.Context, path []string, k Keeper) ([]byte, error) {
+	nodeID := path[0]
+	reputation, err := k.GetReputation(ctx, nodeID)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := codec.MarshalJSONIndent(k.cdc, types.NodeReputation{NodeID: nodeID, Reputation: reputation})
+	if err != nil {
+		return nil, sdkerrors.Wrap(sdkerrors.ErrJSONMarshal, err.Error())
+	}
+	return res, nil
+}
@@ -0,0 +1,132 @@
+// Copyright 2020 Nym Technologies SA
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package keeper
+
+import (
+	"strconv"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/nymtech/nym/validator/nym/x/nym/types"
+)
+
+type payout struct {
+	mixnodeID string
+	recipient sdk.AccAddress
+	amount    sdk.Int
+}
+
+// CurrentEpoch returns the reward epoch the current block belongs to
+func (k Keeper) CurrentEpoch(ctx sdk.Context) int64 {
+	return ctx.BlockHeight() / k.GetParams(ctx).EpochLength
+}
+
+// IsEpochEnd returns whether the current block is the last block of its epoch
+func (k Keeper) IsEpochEnd(ctx sdk.Context) bool {
+	return (ctx.BlockHeight()+1)%k.GetParams(ctx).EpochLength == 0
+}
+
+// MixnodeUptime returns the fraction of the given epoch during which the mixnode was available,
+// averaged over the reports of the network monitors. Mixnodes without a report are considered to have been down.
+func (k Keeper) MixnodeUptime(ctx sdk.Context, epoch int64, mixnodeID string) sdk.Dec {
+	reports := k.GetUptimeReports(ctx, epoch, mixnodeID)
+	if len(reports) == 0 {
+		return sdk.ZeroDec()
+	}
+	uptime := sdk.ZeroDec()
+	for _, report := range reports {
+		uptime = uptime.Add(report.Uptime())
+	}
+	return uptime.QuoInt64(int64(len(reports)))
+}
+
+// DistributeRewards mints the epoch reward and pays it out to bonded mixnodes and their delegators.
+// Each mixnode receives a share proportional to its total stake weighted by its uptime during the epoch,
+// which is then split between the operator and the delegators proportionally to their stake.
+func (k Keeper) DistributeRewards(ctx sdk.Context, epoch int64) error {
+	params := k.GetParams(ctx)
+	if params.EpochReward.IsZero() {
+		return nil
+	}
+	stakeDenom := params.MinimumMixnodeBond.Denom
+
+	mixnodes := k.GetAllMixnodes(ctx)
+	stakes := make([]sdk.Int, len(mixnodes))
+	weights := make([]sdk.Dec, len(mixnodes))
+	totalWeight := sdk.ZeroDec()
+	for i, mixnode := range mixnodes {
+		stakes[i] = k.MixnodeStake(ctx, mixnode, stakeDenom)
+		weights[i] = k.MixnodeUptime(ctx, epoch, mixnode.ID).MulInt(stakes[i])
+		totalWeight = totalWeight.Add(weights[i])
+	}
+	if !totalWeight.IsPositive() {
+		return nil
+	}
+
+	var payouts []payout
+	total := sdk.ZeroInt()
+	addPayout := func(mixnodeID string, recipient sdk.AccAddress, amount sdk.Int) {
+		if amount.IsPositive() {
+			payouts = append(payouts, payout{mixnodeID: mixnodeID, recipient: recipient, amount: amount})
+			total = total.Add(amount)
+		}
+	}
+	for i, mixnode := range mixnodes {
+		if !weights[i].IsPositive() {
+			continue
+		}
+		reward := params.EpochReward.Amount.ToDec().Mul(weights[i]).Quo(totalWeight)
+		addPayout(mixnode.ID, mixnode.Creator, reward.MulInt(stakeOf(mixnode.Bond, stakeDenom)).QuoInt(stakes[i]).TruncateInt())
+		for _, delegation := range k.GetMixnodeDelegations(ctx, mixnode.ID) {
+			addPayout(mixnode.ID, delegation.Delegator, reward.MulInt(stakeOf(delegation.Amount, stakeDenom)).QuoInt(stakes[i]).TruncateInt())
+		}
+	}
+	if !total.IsPositive() {
+		return nil
+	}
+
+	// only mint what is actually paid out so that no rounding dust is left in the module account
+	if err := k.SupplyKeeper.MintCoins(ctx, types.ModuleName, sdk.NewCoins(sdk.NewCoin(params.EpochReward.Denom, total))); err != nil {
+		return err
+	}
+	for _, p := range payouts {
+		amount := sdk.NewCoin(params.EpochReward.Denom, p.amount)
+		if err := k.SupplyKeeper.SendCoinsFromModuleToAccount(ctx, types.ModuleName, p.recipient, sdk.NewCoins(amount)); err != nil {
+			return err
+		}
+		ctx.EventManager().EmitEvent(sdk.NewEvent(
+			types.EventTypeReward,
+			sdk.NewAttribute(types.AttributeKeyEpoch, strconv.FormatInt(epoch, 10)),
+			sdk.NewAttribute(types.AttributeKeyMixnodeID, p.mixnodeID),
+			sdk.NewAttribute(types.AttributeKeyRecipient, p.recipient.String()),
+			sdk.NewAttribute(sdk.AttributeKeyAmount, amount.String()),
+		))
+	}
+
+	return nil
+}
+
+// MixnodeStake returns the total stake, bond and delegations, of the mixnode in the given denomination
+func (k Keeper) MixnodeStake(ctx sdk.Context, mixnode types.Mixnode, denom string) sdk.Int {
+	return k.TotalDelegated(ctx, mixnode.ID).AmountOf(denom).Add(stakeOf(mixnode.Bond, denom))
+}
+
+// stakeOf returns the amount of the coin counted towards stake, i.e. zero unless it is of the staking denomination
+func stakeOf(coin sdk.Coin, denom string) sdk.Int {
+	if coin.Denom != denom {
+		return sdk.ZeroInt()
+	}
+	return coin.Amount
+}
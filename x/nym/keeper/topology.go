@@ -0,0 +1,70 @@
+// Copyright 2020 Nym Technologies SA
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package keeper
+
+import (
+	"encoding/json"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+
+	"github.com/nymtech/nym/validator/nym/x/nym/types"
+)
+
+// GetTopology returns the topology of all registered mixnodes and gateways
+func (k Keeper) GetTopology(ctx sdk.Context) types.Topology {
+	return types.NewTopology(k.GetAllMixnodes(ctx), k.GetAllGateways(ctx))
+}
+
+// GetActiveTopology returns the topology of the mixnodes and gateways whose reputation
+// reached the reputation threshold
+func (k Keeper) GetActiveTopology(ctx sdk.Context) types.Topology {
+	threshold := k.GetParams(ctx).ReputationThreshold
+
+	var mixnodes []types.Mixnode
+	for _, mixnode := range k.GetAllMixnodes(ctx) {
+		if int64(mixnode.Reputation) >= threshold {
+			mixnodes = append(mixnodes, mixnode)
+		}
+	}
+	var gateways []types.Gateway
+	for _, gateway := range k.GetAllGateways(ctx) {
+		if int64(gateway.Reputation) >= threshold {
+			gateways = append(gateways, gateway)
+		}
+	}
+	return types.NewTopology(mixnodes, gateways)
+}
+
+//
+// Functions used by querier
+//
+
+func getTopology(ctx sdk.Context, k Keeper) ([]byte, error) {
+	return marshalTopology(k.GetTopology(ctx))
+}
+
+func getActiveTopology(ctx sdk.Context, k Keeper) ([]byte, error) {
+	return marshalTopology(k.GetActiveTopology(ctx))
+}
+
+// marshalTopology uses encoding/json so the response is identical to the one of the directory
+func marshalTopology(topology types.Topology) ([]byte, error) {
+	res, err := json.MarshalIndent(topology, "", "  ")
+	if err != nil {
+		return nil, sdkerrors.Wrap(sdkerrors.ErrJSONMarshal, err.Error())
+	}
+	return res, nil
+}
@@ -0,0 +1,106 @@
+// Copyright 2020 Nym Technologies SA
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package keeper
+
+import (
+	"strconv"
+
+	"github.com/cosmos/cosmos-sdk/codec"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+
+	"github.com/nymtech/nym/validator/nym/x/nym/types"
+)
+
+// SetUptimeReport stores the uptime report, alongside the reports of the node made by other monitors during the same epoch
+func (k Keeper) SetUptimeReport(ctx sdk.Context, report types.UptimeReport) {
+	store := ctx.KVStore(k.storeKey)
+	store.Set(types.UptimeKey(report.Epoch, report.NodeID, report.Monitor), k.cdc.MustMarshalBinaryLengthPrefixed(report))
+}
+
+// HasUptimeReport returns whether monitor already reported the uptime of the given node during the given epoch
+func (k Keeper) HasUptimeReport(ctx sdk.Context, epoch int64, nodeID string, monitor sdk.AccAddress) bool {
+	store := ctx.KVStore(k.storeKey)
+	return store.Has(types.UptimeKey(epoch, nodeID, monitor))
+}
+
+// GetUptimeReports returns the uptime reports of the given node made by every monitor during the given epoch
+func (k Keeper) GetUptimeReports(ctx sdk.Context, epoch int64, nodeID string) []types.UptimeReport {
+	reports := []types.UptimeReport{}
+	store := ctx.KVStore(k.storeKey)
+	iterator := sdk.KVStorePrefixIterator(store, types.NodeUptimesKey(epoch, nodeID))
+	defer iterator.Close()
+	for ; iterator.Valid(); iterator.Next() {
+		var report types.UptimeReport
+		k.cdc.MustUnmarshalBinaryLengthPrefixed(iterator.Value(), &report)
+		reports = append(reports, report)
+	}
+	return reports
+}
+
+// GetEpochUptimeReports returns every uptime report made during the given epoch
+func (k Keeper) GetEpochUptimeReports(ctx sdk.Context, epoch int64) []types.UptimeReport {
+	reports := []types.UptimeReport{}
+	store := ctx.KVStore(k.storeKey)
+	iterator := sdk.KVStorePrefixIterator(store, types.EpochUptimesKey(epoch))
+	defer iterator.Close()
+	for ; iterator.Valid(); iterator.Next() {
+		var report types.UptimeReport
+		k.cdc.MustUnmarshalBinaryLengthPrefixed(iterator.Value(), &report)
+		reports = append(reports, report)
+	}
+	return reports
+}
+
+// RemoveEpochUptimeReports deletes every uptime report made during the given epoch
+func (k Keeper) RemoveEpochUptimeReports(ctx sdk.Context, epoch int64) {
+	store := ctx.KVStore(k.storeKey)
+	// collect the keys first as deleting them while iterating would invalidate the iterator
+	var keys [][]byte
+	iterator := sdk.KVStorePrefixIterator(store, types.EpochUptimesKey(epoch))
+	for ; iterator.Valid(); iterator.Next() {
+		keys = append(keys, iterator.Key())
+	}
+	iterator.Close()
+	for _, key := range keys {
+		store.Delete(key)
+	}
+}
+
+//
+// Functions used by querier
+//
+
+func getUptime(ctx sdk.Context, path []string, k Keeper) ([]byte, error) {
+	nodeID := path[0]
+	epoch := k.CurrentEpoch(ctx)
+	if len(path) > 1 {
+		var err error
+		if epoch, err = strconv.ParseInt(path[1], 10, 64); err != nil {
+			return nil, sdkerrors.Wrap(sdkerrors.ErrInvalidRequest, err.Error())
+		}
+	}
+
+	reports := k.GetUptimeReports(ctx, epoch, nodeID)
+	if len(reports) == 0 {
+		return nil, sdkerrors.Wrapf(sdkerrors.ErrUnknownRequest, "no uptime report for node %s during epoch %d", nodeID, epoch)
+	}
+
+	res, err := codec.MarshalJSONIndent(k.cdc, reports)
+	if err != nil {
+		return nil, sdkerrors.Wrap(sdkerrors.ErrJSONMarshal, err.Error())
+	}
+	return res, nil
+}
@@ -26,7 +26,6 @@ import (
 	sdk "github.com/cosmos/cosmos-sdk/types"
 	"github.com/cosmos/cosmos-sdk/types/module"
 	"github.com/cosmos/cosmos-sdk/x/bank"
-	directoryServer "github.com/nymtech/nym/validator/nym/directory"
 	"github.com/nymtech/nym/validator/nym/x/nym/client/cli"
 	"github.com/nymtech/nym/validator/nym/x/nym/client/rest"
 	"github.com/nymtech/nym/validator/nym/x/nym/keeper"
@@ -72,11 +71,8 @@ func (AppModuleBasic) ValidateGenesis(bz json.RawMessage) error {
 }
 
 // RegisterRESTRoutes registers the REST routes for the nym module.
-// This is also the point where the very primitive integration of the old directory server wires in.
+// The directory server runs on its own, see `nymcli directory serve`.
 func (AppModuleBasic) RegisterRESTRoutes(ctx context.CLIContext, rtr *mux.Router) {
-	// TODO: can I just move context like that?
-	directory := directoryServer.New(ctx)
-	go directory.Run(":8081")
 	rest.RegisterRoutes(ctx, rtr)
 }
 
@@ -163,6 +159,7 @@ func (am AppModule) BeginBlock(ctx sdk.Context, req abci.RequestBeginBlock) {
 
 // EndBlock returns the end blocker for the nym module. It returns no validator
 // updates.
-func (AppModule) EndBlock(_ sdk.Context, _ abci.RequestEndBlock) []abci.ValidatorUpdate {
+func (am AppModule) EndBlock(ctx sdk.Context, _ abci.RequestEndBlock) []abci.ValidatorUpdate {
+	EndBlocker(ctx, am.keeper)
 	return []abci.ValidatorUpdate{}
 }
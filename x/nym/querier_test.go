@@ -0,0 +1,110 @@
+// Copyright 2020 Nym Technologies SA
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nym
+
+import (
+	"fmt"
+	"testing"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	abci "github.com/tendermint/tendermint/abci/types"
+
+	"github.com/nymtech/nym/validator/nym/x/nym/keeper"
+	"github.com/nymtech/nym/validator/nym/x/nym/types"
+)
+
+// createNodes stores count mixnodes and gateways with IDs node-0000, node-0001..., those with odd IDs being in Paris
+// and on the second layer while the others are in Neuchatel and on the first layer
+func createNodes(ctx sdk.Context, k keeper.Keeper, count int) {
+	for i := 0; i < count; i++ {
+		id := fmt.Sprintf("node-%04d", i)
+		location, layer := "Neuchatel", int32(1)
+		if i%2 == 1 {
+			location, layer = "Paris", 2
+		}
+		k.CreateMixnode(ctx, types.Mixnode{Creator: owner, ID: id, PubKey: id, Layer: layer, Location: location})
+		k.CreateGateway(ctx, types.Gateway{Creator: owner, ID: id, IdentityKey: id, Location: location})
+	}
+}
+
+func listMixnodes(t *testing.T, ctx sdk.Context, k keeper.Keeper, params types.QueryMixnodesParams) []string {
+	res, err := keeper.NewQuerier(k)(ctx, []string{types.QueryListMixnode}, abci.RequestQuery{Data: types.ModuleCdc.MustMarshalJSON(params)})
+	require.NoError(t, err)
+	var mixnodes []types.Mixnode
+	types.ModuleCdc.MustUnmarshalJSON(res, &mixnodes)
+	ids := []string{}
+	for _, mixnode := range mixnodes {
+		ids = append(ids, mixnode.ID)
+	}
+	return ids
+}
+
+func listGateways(t *testing.T, ctx sdk.Context, k keeper.Keeper, params types.QueryGatewaysParams) []string {
+	res, err := keeper.NewQuerier(k)(ctx, []string{types.QueryListGateway}, abci.RequestQuery{Data: types.ModuleCdc.MustMarshalJSON(params)})
+	require.NoError(t, err)
+	var gateways []types.Gateway
+	types.ModuleCdc.MustUnmarshalJSON(res, &gateways)
+	ids := []string{}
+	for _, gateway := range gateways {
+		ids = append(ids, gateway.ID)
+	}
+	return ids
+}
+
+func TestListMixnodePages(t *testing.T) {
+	ctx, k, _, _ := setupHandler(t)
+	createNodes(ctx, k, 5)
+
+	assert.Equal(t, []string{"node-0000", "node-0001"}, listMixnodes(t, ctx, k, types.NewQueryMixnodesParams(1, 2, 0, "", "", nil)))
+	assert.Equal(t, []string{"node-0002", "node-0003"}, listMixnodes(t, ctx, k, types.NewQueryMixnodesParams(2, 2, 0, "", "", nil)))
+	assert.Equal(t, []string{"node-0004"}, listMixnodes(t, ctx, k, types.NewQueryMixnodesParams(3, 2, 0, "", "", nil)))
+	assert.Empty(t, listMixnodes(t, ctx, k, types.NewQueryMixnodesParams(4, 2, 0, "", "", nil)))
+	assert.Empty(t, listMixnodes(t, ctx, k, types.NewQueryMixnodesParams(0, 2, 0, "", "", nil)))
+	assert.Len(t, listMixnodes(t, ctx, k, types.NewQueryMixnodesParams(1, 0, 0, "", "", nil)), 5)
+}
+
+func TestListMixnodeCapsTheLimit(t *testing.T) {
+	ctx, k, _, _ := setupHandler(t)
+	createNodes(ctx, k, types.MaxQueryLimit+1)
+
+	assert.Len(t, listMixnodes(t, ctx, k, types.NewQueryMixnodesParams(1, 0, 0, "", "", nil)), types.DefaultQueryLimit)
+	assert.Len(t, listMixnodes(t, ctx, k, types.NewQueryMixnodesParams(1, 2*types.MaxQueryLimit, 0, "", "", nil)), types.MaxQueryLimit)
+	assert.Equal(t, []string{fmt.Sprintf("node-%04d", types.MaxQueryLimit)},
+		listMixnodes(t, ctx, k, types.NewQueryMixnodesParams(2, 2*types.MaxQueryLimit, 0, "", "", nil)))
+}
+
+func TestListMixnodePagesFilteredMixnodes(t *testing.T) {
+	ctx, k, _, _ := setupHandler(t)
+	createNodes(ctx, k, 15)
+
+	assert.Equal(t, []string{"node-0007", "node-0009", "node-0011"}, listMixnodes(t, ctx, k, types.NewQueryMixnodesParams(2, 3, 0, "Paris", "", nil)))
+	assert.Equal(t, []string{"node-0013"}, listMixnodes(t, ctx, k, types.NewQueryMixnodesParams(3, 3, 2, "", "", owner)))
+	assert.Empty(t, listMixnodes(t, ctx, k, types.NewQueryMixnodesParams(4, 3, 2, "", "", nil)))
+	assert.Empty(t, listMixnodes(t, ctx, k, types.NewQueryMixnodesParams(1, 3, 1, "Paris", "", nil)))
+	assert.Empty(t, listMixnodes(t, ctx, k, types.NewQueryMixnodesParams(1, 3, 0, "", "", thief)))
+}
+
+func TestListGatewayPages(t *testing.T) {
+	ctx, k, _, _ := setupHandler(t)
+	createNodes(ctx, k, 5)
+
+	assert.Equal(t, []string{"node-0002", "node-0003"}, listGateways(t, ctx, k, types.NewQueryGatewaysParams(2, 2, "", nil)))
+	assert.Equal(t, []string{"node-0004"}, listGateways(t, ctx, k, types.NewQueryGatewaysParams(3, 2, "", nil)))
+	assert.Empty(t, listGateways(t, ctx, k, types.NewQueryGatewaysParams(4, 2, "", nil)))
+	assert.Equal(t, []string{"node-0003"}, listGateways(t, ctx, k, types.NewQueryGatewaysParams(2, 1, "Paris", owner)))
+	assert.Empty(t, listGateways(t, ctx, k, types.NewQueryGatewaysParams(3, 1, "Paris", nil)))
+}
@@ -0,0 +1,103 @@
+// Copyright 2020 Nym Technologies SA
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nym
+
+import (
+	"encoding/json"
+	"testing"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	abci "github.com/tendermint/tendermint/abci/types"
+
+	"github.com/nymtech/nym/validator/nym/x/nym/keeper"
+	"github.com/nymtech/nym/validator/nym/x/nym/types"
+)
+
+// createTopology stores the mixnodes "first" on layer 2 with the threshold reputation and "second" on layer 1 just below it,
+// and the gateway "gateway" with the threshold reputation
+func createTopology(ctx sdk.Context, k keeper.Keeper) {
+	threshold := int32(k.GetParams(ctx).ReputationThreshold)
+	k.CreateMixnode(ctx, types.Mixnode{Creator: owner, ID: "first", PubKey: identityKey, SphinxKey: otherIdentityKey, Layer: 2,
+		Version: types.DefaultSystemVersion, Host: "1.1.1.1:1789", Location: "Neuchatel", Reputation: threshold, RegistrationTime: 1600000000000000000})
+	k.CreateMixnode(ctx, types.Mixnode{Creator: owner, ID: "second", PubKey: otherIdentityKey, SphinxKey: identityKey, Layer: 1,
+		Version: types.DefaultSystemVersion, Host: "2.2.2.2:1789", Location: "Neuchatel", Reputation: threshold - 1})
+	k.CreateGateway(ctx, types.Gateway{Creator: owner, ID: "gateway", IdentityKey: identityKey, SphinxKey: otherIdentityKey,
+		ClientListener: "ws://1.1.1.1:9000", MixnetListener: "1.1.1.1:1789", Location: "Neuchatel", Reputation: threshold})
+}
+
+// queryTopology runs the given topology query and decodes its response without assuming its types
+func queryTopology(t *testing.T, ctx sdk.Context, k keeper.Keeper, query string) map[string]interface{} {
+	res, err := keeper.NewQuerier(k)(ctx, []string{query}, abci.RequestQuery{})
+	require.NoError(t, err)
+	var topology map[string]interface{}
+	require.NoError(t, json.Unmarshal(res, &topology))
+	return topology
+}
+
+func TestTopologyHasTheShapeOfTheDirectoryTopology(t *testing.T) {
+	ctx, k, _, _ := setupHandler(t)
+	createTopology(ctx, k)
+
+	topology := queryTopology(t, ctx, k, types.QueryTopology)
+
+	assert.Equal(t, map[string]interface{}{"block_height": float64(0), "validators": []interface{}{}}, topology["validators"])
+	mixnodes := topology["mixNodes"].([]interface{})
+	require.Len(t, mixnodes, 2)
+	// mixnodes are sorted by layer and numbers are encoded as such rather than amino strings
+	assert.Equal(t, "2.2.2.2:1789", mixnodes[0].(map[string]interface{})["mixHost"])
+	assert.Equal(t, map[string]interface{}{
+		"mixHost":           "1.1.1.1:1789",
+		"identityKey":       identityKey,
+		"sphinxKey":         otherIdentityKey,
+		"version":           types.DefaultSystemVersion,
+		"location":          "Neuchatel",
+		"incentivesAddress": owner.String(),
+		"layer":             float64(2),
+		"registrationTime":  float64(1600000000000000000),
+		"reputation":        float64(types.DefaultReputationThreshold),
+	}, mixnodes[1])
+	assert.Equal(t, []interface{}{map[string]interface{}{
+		"mixHost":           "1.1.1.1:1789",
+		"identityKey":       identityKey,
+		"sphinxKey":         otherIdentityKey,
+		"version":           "",
+		"location":          "Neuchatel",
+		"incentivesAddress": owner.String(),
+		"clientsHost":       "ws://1.1.1.1:9000",
+		"registrationTime":  float64(0),
+		"reputation":        float64(types.DefaultReputationThreshold),
+	}}, topology["gateways"])
+}
+
+func TestActiveTopologyOnlyHasNodesReachingTheReputationThreshold(t *testing.T) {
+	ctx, k, _, _ := setupHandler(t)
+	createTopology(ctx, k)
+
+	topology := queryTopology(t, ctx, k, types.QueryActiveTopology)
+	mixnodes := topology["mixNodes"].([]interface{})
+	require.Len(t, mixnodes, 1)
+	assert.Equal(t, identityKey, mixnodes[0].(map[string]interface{})["identityKey"])
+	assert.Len(t, topology["gateways"], 1)
+
+	params := k.GetParams(ctx)
+	params.ReputationThreshold++
+	k.SetParams(ctx, params)
+	topology = queryTopology(t, ctx, k, types.QueryActiveTopology)
+	// empty lists are still encoded as arrays like the directory does
+	assert.Equal(t, []interface{}{}, topology["mixNodes"])
+	assert.Equal(t, []interface{}{}, topology["gateways"])
+}
@@ -17,10 +17,11 @@ type MsgCreateGateway struct {
 	ClientListener string         `json:"clientListener" yaml:"clientListener"`
 	MixnetListener string         `json:"mixnetListener" yaml:"mixnetListener"`
 	Location       string         `json:"location" yaml:"location"`
+	Bond           sdk.Coin       `json:"bond" yaml:"bond"`
 }
 
 // NewMsgCreateGateway ...
-func NewMsgCreateGateway(creator sdk.AccAddress, identityKey string, sphinxKey string, clientListener string, mixnetListener string, location string) MsgCreateGateway {
+func NewMsgCreateGateway(creator sdk.AccAddress, identityKey string, sphinxKey string, clientListener string, mixnetListener string, location string, bond sdk.Coin) MsgCreateGateway {
 	return MsgCreateGateway{
 		ID:             uuid.New().String(),
 		Creator:        creator,
@@ -29,6 +30,7 @@ func NewMsgCreateGateway(creator sdk.AccAddress, identityKey string, sphinxKey s
 		ClientListener: clientListener,
 		MixnetListener: mixnetListener,
 		Location:       location,
+		Bond:           bond,
 	}
 }
 
@@ -58,5 +60,14 @@ func (msg MsgCreateGateway) ValidateBasic() error {
 	if msg.Creator.Empty() {
 		return sdkerrors.Wrap(sdkerrors.ErrInvalidAddress, "creator can't be empty")
 	}
+	if err := validateID(msg.ID); err != nil {
+		return sdkerrors.Wrap(ErrInvalidID, err.Error())
+	}
+	if err := validateGateway(msg.IdentityKey, msg.SphinxKey, msg.ClientListener, msg.MixnetListener, msg.Location); err != nil {
+		return err
+	}
+	if !msg.Bond.IsValid() || !msg.Bond.IsPositive() {
+		return sdkerrors.Wrap(sdkerrors.ErrInvalidCoins, "bond must be positive")
+	}
 	return nil
 }
@@ -24,27 +24,29 @@ var _ sdk.Msg = &MsgCreateMixnode{}
 
 // MsgCreateMixnode ...
 type MsgCreateMixnode struct {
-	ID         string
-	Creator    sdk.AccAddress `json:"creator" yaml:"creator"`
-	PubKey     string         `json:"pubKey" yaml:"pubKey"`
-	Layer      int32          `json:"layer" yaml:"layer"`
-	Version    string         `json:"version" yaml:"version"`
-	Host       string         `json:"host" yaml:"host"`
-	Location   string         `json:"location" yaml:"location"`
-	Reputation int32          `json:"reputation" yaml:"reputation"`
+	ID        string
+	Creator   sdk.AccAddress `json:"creator" yaml:"creator"`
+	PubKey    string         `json:"pubKey" yaml:"pubKey"`
+	SphinxKey string         `json:"sphinxKey" yaml:"sphinxKey"`
+	Layer     int32          `json:"layer" yaml:"layer"`
+	Version   string         `json:"version" yaml:"version"`
+	Host      string         `json:"host" yaml:"host"`
+	Location  string         `json:"location" yaml:"location"`
+	Bond      sdk.Coin       `json:"bond" yaml:"bond"`
 }
 
 // NewMsgCreateMixnode constructor for MsgCreateMixnode
-func NewMsgCreateMixnode(creator sdk.AccAddress, pubKey string, layer int32, version string, host string, location string, reputation int32) MsgCreateMixnode {
+func NewMsgCreateMixnode(creator sdk.AccAddress, pubKey string, sphinxKey string, layer int32, version string, host string, location string, bond sdk.Coin) MsgCreateMixnode {
 	return MsgCreateMixnode{
-		ID:         uuid.New().String(),
-		Creator:    creator,
-		PubKey:     pubKey,
-		Layer:      layer,
-		Version:    version,
-		Host:       host,
-		Location:   location,
-		Reputation: reputation,
+		ID:        uuid.New().String(),
+		Creator:   creator,
+		PubKey:    pubKey,
+		SphinxKey: sphinxKey,
+		Layer:     layer,
+		Version:   version,
+		Host:      host,
+		Location:  location,
+		Bond:      bond,
 	}
 }
 
@@ -74,5 +76,14 @@ func (msg MsgCreateMixnode) ValidateBasic() error {
 	if msg.Creator.Empty() {
 		return sdkerrors.Wrap(sdkerrors.ErrInvalidAddress, "creator can't be empty")
 	}
+	if err := validateID(msg.ID); err != nil {
+		return sdkerrors.Wrap(ErrInvalidID, err.Error())
+	}
+	if err := validateMixnode(msg.PubKey, msg.SphinxKey, msg.Layer, msg.Version, msg.Host, msg.Location); err != nil {
+		return err
+	}
+	if !msg.Bond.IsValid() || !msg.Bond.IsPositive() {
+		return sdkerrors.Wrap(sdkerrors.ErrInvalidCoins, "bond must be positive")
+	}
 	return nil
 }
@@ -47,5 +47,8 @@ func (msg MsgDeleteGateway) ValidateBasic() error {
 	if msg.Creator.Empty() {
 		return sdkerrors.Wrap(sdkerrors.ErrInvalidAddress, "creator can't be empty")
 	}
+	if msg.ID == "" {
+		return sdkerrors.Wrap(sdkerrors.ErrInvalidRequest, "id can't be empty")
+	}
 	return nil
 }
@@ -61,5 +61,8 @@ func (msg MsgDeleteMixnode) ValidateBasic() error {
 	if msg.Creator.Empty() {
 		return sdkerrors.Wrap(sdkerrors.ErrInvalidAddress, "creator can't be empty")
 	}
+	if msg.ID == "" {
+		return sdkerrors.Wrap(sdkerrors.ErrInvalidRequest, "id can't be empty")
+	}
 	return nil
 }
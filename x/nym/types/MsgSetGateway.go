@@ -57,5 +57,11 @@ func (msg MsgSetGateway) ValidateBasic() error {
 	if msg.Creator.Empty() {
 		return sdkerrors.Wrap(sdkerrors.ErrInvalidAddress, "creator can't be empty")
 	}
+	if msg.ID == "" {
+		return sdkerrors.Wrap(sdkerrors.ErrInvalidRequest, "id can't be empty")
+	}
+	if err := validateGateway(msg.IdentityKey, msg.SphinxKey, msg.ClientListener, msg.MixnetListener, msg.Location); err != nil {
+		return err
+	}
 	return nil
 }
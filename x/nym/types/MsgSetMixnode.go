@@ -23,27 +23,27 @@ var _ sdk.Msg = &MsgSetMixnode{}
 
 // MsgSetMixnode ...
 type MsgSetMixnode struct {
-	ID         string         `json:"id" yaml:"id"`
-	Creator    sdk.AccAddress `json:"creator" yaml:"creator"`
-	PubKey     string         `json:"pubKey" yaml:"pubKey"`
-	Layer      int32          `json:"layer" yaml:"layer"`
-	Version    string         `json:"version" yaml:"version"`
-	Host       string         `json:"host" yaml:"host"`
-	Location   string         `json:"location" yaml:"location"`
-	Reputation int32          `json:"reputation" yaml:"reputation"`
+	ID        string         `json:"id" yaml:"id"`
+	Creator   sdk.AccAddress `json:"creator" yaml:"creator"`
+	PubKey    string         `json:"pubKey" yaml:"pubKey"`
+	SphinxKey string         `json:"sphinxKey" yaml:"sphinxKey"`
+	Layer     int32          `json:"layer" yaml:"layer"`
+	Version   string         `json:"version" yaml:"version"`
+	Host      string         `json:"host" yaml:"host"`
+	Location  string         `json:"location" yaml:"location"`
 }
 
 // NewMsgSetMixnode constructor
-func NewMsgSetMixnode(creator sdk.AccAddress, id string, pubKey string, layer int32, version string, host string, location string, reputation int32) MsgSetMixnode {
+func NewMsgSetMixnode(creator sdk.AccAddress, id string, pubKey string, sphinxKey string, layer int32, version string, host string, location string) MsgSetMixnode {
 	return MsgSetMixnode{
-		ID:         id,
-		Creator:    creator,
-		PubKey:     pubKey,
-		Layer:      layer,
-		Version:    version,
-		Host:       host,
-		Location:   location,
-		Reputation: reputation,
+		ID:        id,
+		Creator:   creator,
+		PubKey:    pubKey,
+		SphinxKey: sphinxKey,
+		Layer:     layer,
+		Version:   version,
+		Host:      host,
+		Location:  location,
 	}
 }
 
@@ -73,5 +73,11 @@ func (msg MsgSetMixnode) ValidateBasic() error {
 	if msg.Creator.Empty() {
 		return sdkerrors.Wrap(sdkerrors.ErrInvalidAddress, "creator can't be empty")
 	}
+	if msg.ID == "" {
+		return sdkerrors.Wrap(sdkerrors.ErrInvalidRequest, "id can't be empty")
+	}
+	if err := validateMixnode(msg.PubKey, msg.SphinxKey, msg.Layer, msg.Version, msg.Host, msg.Location); err != nil {
+		return err
+	}
 	return nil
 }
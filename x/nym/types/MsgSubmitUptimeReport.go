@@ -0,0 +1,71 @@
+// Copyright 2020 Nym Technologies SA
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package types
+
+import (
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+)
+
+var _ sdk.Msg = &MsgSubmitUptimeReport{}
+
+type MsgSubmitUptimeReport struct {
+	Monitor sdk.AccAddress `json:"monitor" yaml:"monitor"`
+	Reports []NodeUptime   `json:"reports" yaml:"reports"`
+}
+
+func NewMsgSubmitUptimeReport(monitor sdk.AccAddress, reports []NodeUptime) MsgSubmitUptimeReport {
+	return MsgSubmitUptimeReport{
+		Monitor: monitor,
+		Reports: reports,
+	}
+}
+
+func (msg MsgSubmitUptimeReport) Route() string {
+	return RouterKey
+}
+
+func (msg MsgSubmitUptimeReport) Type() string {
+	return "SubmitUptimeReport"
+}
+
+func (msg MsgSubmitUptimeReport) GetSigners() []sdk.AccAddress {
+	return []sdk.AccAddress{sdk.AccAddress(msg.Monitor)}
+}
+
+func (msg MsgSubmitUptimeReport) GetSignBytes() []byte {
+	bz := ModuleCdc.MustMarshalJSON(msg)
+	return sdk.MustSortJSON(bz)
+}
+
+func (msg MsgSubmitUptimeReport) ValidateBasic() error {
+	if msg.Monitor.Empty() {
+		return sdkerrors.Wrap(sdkerrors.ErrInvalidAddress, "monitor can't be empty")
+	}
+	if len(msg.Reports) == 0 {
+		return sdkerrors.Wrap(sdkerrors.ErrInvalidRequest, "reports can't be empty")
+	}
+	seen := make(map[string]bool, len(msg.Reports))
+	for _, report := range msg.Reports {
+		if err := report.Validate(); err != nil {
+			return sdkerrors.Wrap(sdkerrors.ErrInvalidRequest, err.Error())
+		}
+		if seen[report.NodeID] {
+			return sdkerrors.Wrapf(sdkerrors.ErrInvalidRequest, "duplicate report for node %s", report.NodeID)
+		}
+		seen[report.NodeID] = true
+	}
+	return nil
+}
@@ -0,0 +1,66 @@
+// Copyright 2020 Nym Technologies SA
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package types
+
+import (
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+)
+
+var _ sdk.Msg = &MsgUndelegate{}
+
+type MsgUndelegate struct {
+	MixnodeID string         `json:"mixnodeId" yaml:"mixnodeId"`
+	Delegator sdk.AccAddress `json:"delegator" yaml:"delegator"`
+	Amount    sdk.Coin       `json:"amount" yaml:"amount"`
+}
+
+func NewMsgUndelegate(mixnodeID string, delegator sdk.AccAddress, amount sdk.Coin) MsgUndelegate {
+	return MsgUndelegate{
+		MixnodeID: mixnodeID,
+		Delegator: delegator,
+		Amount:    amount,
+	}
+}
+
+func (msg MsgUndelegate) Route() string {
+	return RouterKey
+}
+
+func (msg MsgUndelegate) Type() string {
+	return "Undelegate"
+}
+
+func (msg MsgUndelegate) GetSigners() []sdk.AccAddress {
+	return []sdk.AccAddress{sdk.AccAddress(msg.Delegator)}
+}
+
+func (msg MsgUndelegate) GetSignBytes() []byte {
+	bz := ModuleCdc.MustMarshalJSON(msg)
+	return sdk.MustSortJSON(bz)
+}
+
+func (msg MsgUndelegate) ValidateBasic() error {
+	if msg.Delegator.Empty() {
+		return sdkerrors.Wrap(sdkerrors.ErrInvalidAddress, "delegator can't be empty")
+	}
+	if msg.MixnodeID == "" {
+		return sdkerrors.Wrap(sdkerrors.ErrInvalidRequest, "mixnode id can't be empty")
+	}
+	if !msg.Amount.IsValid() || !msg.Amount.IsPositive() {
+		return sdkerrors.Wrap(sdkerrors.ErrInvalidCoins, "undelegated amount must be positive")
+	}
+	return nil
+}
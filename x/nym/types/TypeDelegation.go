@@ -0,0 +1,45 @@
+// Copyright 2020 Nym Technologies SA
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package types
+
+import (
+	"fmt"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+// Delegation represents coins delegated by a token holder to a mixnode
+type Delegation struct {
+	MixnodeID string         `json:"mixnodeId" yaml:"mixnodeId"`
+	Delegator sdk.AccAddress `json:"delegator" yaml:"delegator"`
+	Amount    sdk.Coin       `json:"amount" yaml:"amount"`
+}
+
+// NewDelegation creates a new Delegation instance
+func NewDelegation(mixnodeID string, delegator sdk.AccAddress, amount sdk.Coin) Delegation {
+	return Delegation{
+		MixnodeID: mixnodeID,
+		Delegator: delegator,
+		Amount:    amount,
+	}
+}
+
+// String implements the stringer interface for Delegation
+func (d Delegation) String() string {
+	return fmt.Sprintf(`Delegation:
+  Mixnode:   %s
+  Delegator: %s
+  Amount:    %s`, d.MixnodeID, d.Delegator, d.Amount)
+}
@@ -6,11 +6,14 @@ import (
 
 // Gateway models a Nym gateway, which acts as an entry/exit point and also provides storage for offline nodes.
 type Gateway struct {
-	Creator        sdk.AccAddress `json:"creator" yaml:"creator"`
-	ID             string         `json:"id" yaml:"id"`
-	IdentityKey    string         `json:"identityKey" yaml:"identityKey"`
-	SphinxKey      string         `json:"sphinxKey" yaml:"sphinxKey"`
-	ClientListener string         `json:"clientListener" yaml:"clientListener"`
-	MixnetListener string         `json:"mixnetListener" yaml:"mixnetListener"`
-	Location       string         `json:"location" yaml:"location"`
+	Creator          sdk.AccAddress `json:"creator" yaml:"creator"`
+	ID               string         `json:"id" yaml:"id"`
+	IdentityKey      string         `json:"identityKey" yaml:"identityKey"`
+	SphinxKey        string         `json:"sphinxKey" yaml:"sphinxKey"`
+	ClientListener   string         `json:"clientListener" yaml:"clientListener"`
+	MixnetListener   string         `json:"mixnetListener" yaml:"mixnetListener"`
+	Location         string         `json:"location" yaml:"location"`
+	Reputation       int32          `json:"reputation" yaml:"reputation"`
+	Bond             sdk.Coin       `json:"bond" yaml:"bond"`
+	RegistrationTime int64          `json:"registrationTime" yaml:"registrationTime"` // unix nanoseconds of the block the node was created in
 }
@@ -20,12 +20,15 @@ import (
 
 // Mixnode models a Nym mixnode, which shuffles Sphinx packets together inside itself to provide network privacy for users.
 type Mixnode struct {
-	Creator    sdk.AccAddress `json:"creator" yaml:"creator"`
-	ID         string         `json:"id" yaml:"id"`
-	PubKey     string         `json:"pubKey" yaml:"pubKey"`
-	Layer      int32          `json:"layer" yaml:"layer"`
-	Version    string         `json:"version" yaml:"version"`
-	Host       string         `json:"host" yaml:"host"`
-	Location   string         `json:"location" yaml:"location"`
-	Reputation int32          `json:"reputation" yaml:"reputation"`
+	Creator          sdk.AccAddress `json:"creator" yaml:"creator"`
+	ID               string         `json:"id" yaml:"id"`
+	PubKey           string         `json:"pubKey" yaml:"pubKey"`
+	SphinxKey        string         `json:"sphinxKey" yaml:"sphinxKey"`
+	Layer            int32          `json:"layer" yaml:"layer"`
+	Version          string         `json:"version" yaml:"version"`
+	Host             string         `json:"host" yaml:"host"`
+	Location         string         `json:"location" yaml:"location"`
+	Reputation       int32          `json:"reputation" yaml:"reputation"`
+	Bond             sdk.Coin       `json:"bond" yaml:"bond"`
+	RegistrationTime int64          `json:"registrationTime" yaml:"registrationTime"` // unix nanoseconds of the block the node was created in
 }
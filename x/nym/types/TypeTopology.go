@@ -0,0 +1,100 @@
+// Copyright 2020 Nym Technologies SA
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package types
+
+import (
+	"sort"
+
+	"github.com/cosmos/cosmos-sdk/client/rpc"
+)
+
+// TopologyMixnode is a mixnode in the format served by the directory's `/api/mixmining/topology`
+// endpoint (`models.RegisteredMix`), so clients can use the same parser for both.
+type TopologyMixnode struct {
+	MixHost           string `json:"mixHost"`
+	IdentityKey       string `json:"identityKey"`
+	SphinxKey         string `json:"sphinxKey"`
+	Version           string `json:"version"`
+	Location          string `json:"location"`
+	IncentivesAddress string `json:"incentivesAddress"`
+	Layer             uint   `json:"layer"`
+	RegistrationTime  int64  `json:"registrationTime"`
+	Reputation        int64  `json:"reputation"`
+}
+
+// TopologyGateway is a gateway in the format served by the directory (`models.RegisteredGateway`).
+type TopologyGateway struct {
+	MixHost           string `json:"mixHost"`
+	IdentityKey       string `json:"identityKey"`
+	SphinxKey         string `json:"sphinxKey"`
+	Version           string `json:"version"`
+	Location          string `json:"location"`
+	IncentivesAddress string `json:"incentivesAddress"`
+	ClientsHost       string `json:"clientsHost"`
+	RegistrationTime  int64  `json:"registrationTime"`
+	Reputation        int64  `json:"reputation"`
+}
+
+// Topology mirrors the directory's `models.Topology`. It must be encoded with encoding/json rather than
+// amino, which would turn the 64 bit integers into strings and break directory clients.
+type Topology struct {
+	MixNodes   []TopologyMixnode          `json:"mixNodes"`
+	Gateways   []TopologyGateway          `json:"gateways"`
+	Validators rpc.ResultValidatorsOutput `json:"validators"`
+}
+
+// NewTopology builds the directory-style topology of the given nodes, with mixnodes grouped by layer.
+// Validators are left empty as they are not part of the nym module state.
+func NewTopology(mixnodes []Mixnode, gateways []Gateway) Topology {
+	topology := Topology{
+		MixNodes: make([]TopologyMixnode, 0, len(mixnodes)),
+		Gateways: make([]TopologyGateway, 0, len(gateways)),
+		Validators: rpc.ResultValidatorsOutput{
+			Validators: []rpc.ValidatorOutput{},
+		},
+	}
+
+	for _, mixnode := range mixnodes {
+		topology.MixNodes = append(topology.MixNodes, TopologyMixnode{
+			MixHost:           mixnode.Host,
+			IdentityKey:       mixnode.PubKey,
+			SphinxKey:         mixnode.SphinxKey,
+			Version:           mixnode.Version,
+			Location:          mixnode.Location,
+			IncentivesAddress: mixnode.Creator.String(),
+			Layer:             uint(mixnode.Layer),
+			RegistrationTime:  mixnode.RegistrationTime,
+			Reputation:        int64(mixnode.Reputation),
+		})
+	}
+	sort.SliceStable(topology.MixNodes, func(i, j int) bool {
+		return topology.MixNodes[i].Layer < topology.MixNodes[j].Layer
+	})
+
+	for _, gateway := range gateways {
+		topology.Gateways = append(topology.Gateways, TopologyGateway{
+			MixHost:           gateway.MixnetListener,
+			IdentityKey:       gateway.IdentityKey,
+			SphinxKey:         gateway.SphinxKey,
+			Location:          gateway.Location,
+			IncentivesAddress: gateway.Creator.String(),
+			ClientsHost:       gateway.ClientListener,
+			RegistrationTime:  gateway.RegistrationTime,
+			Reputation:        int64(gateway.Reputation),
+		})
+	}
+
+	return topology
+}
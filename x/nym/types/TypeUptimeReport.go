@@ -0,0 +1,88 @@
+// Copyright 2020 Nym Technologies SA
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package types
+
+import (
+	"fmt"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+// NodeUptime is the uptime of a single node, in percent, as measured by a network monitor
+type NodeUptime struct {
+	NodeID     string `json:"nodeId" yaml:"nodeId"`
+	IPV4Uptime int32  `json:"ipv4Uptime" yaml:"ipv4Uptime"`
+	IPV6Uptime int32  `json:"ipv6Uptime" yaml:"ipv6Uptime"`
+}
+
+// Validate checks the node uptime is well formed
+func (u NodeUptime) Validate() error {
+	if u.NodeID == "" {
+		return fmt.Errorf("node id can't be empty")
+	}
+	if u.IPV4Uptime < 0 || u.IPV4Uptime > 100 {
+		return fmt.Errorf("ipv4 uptime of node %s must be between 0 and 100, got %d", u.NodeID, u.IPV4Uptime)
+	}
+	if u.IPV6Uptime < 0 || u.IPV6Uptime > 100 {
+		return fmt.Errorf("ipv6 uptime of node %s must be between 0 and 100, got %d", u.NodeID, u.IPV6Uptime)
+	}
+	return nil
+}
+
+// UptimeReport is the uptime of a node during an epoch as accepted from a network monitor
+type UptimeReport struct {
+	Epoch      int64          `json:"epoch" yaml:"epoch"`
+	NodeID     string         `json:"nodeId" yaml:"nodeId"`
+	Monitor    sdk.AccAddress `json:"monitor" yaml:"monitor"`
+	IPV4Uptime int32          `json:"ipv4Uptime" yaml:"ipv4Uptime"`
+	IPV6Uptime int32          `json:"ipv6Uptime" yaml:"ipv6Uptime"`
+}
+
+// NewUptimeReport creates a new UptimeReport instance
+func NewUptimeReport(epoch int64, monitor sdk.AccAddress, uptime NodeUptime) UptimeReport {
+	return UptimeReport{
+		Epoch:      epoch,
+		NodeID:     uptime.NodeID,
+		Monitor:    monitor,
+		IPV4Uptime: uptime.IPV4Uptime,
+		IPV6Uptime: uptime.IPV6Uptime,
+	}
+}
+
+// Validate checks the uptime report is well formed
+func (r UptimeReport) Validate() error {
+	if r.Epoch < 0 {
+		return fmt.Errorf("uptime report of node %s has a negative epoch: %d", r.NodeID, r.Epoch)
+	}
+	if r.Monitor.Empty() {
+		return fmt.Errorf("uptime report of node %s has an empty monitor", r.NodeID)
+	}
+	return NodeUptime{NodeID: r.NodeID, IPV4Uptime: r.IPV4Uptime, IPV6Uptime: r.IPV6Uptime}.Validate()
+}
+
+// String implements the stringer interface for UptimeReport
+func (r UptimeReport) String() string {
+	return fmt.Sprintf(`UptimeReport:
+  Epoch:       %d
+  Node:        %s
+  Monitor:     %s
+  IPv4 Uptime: %d
+  IPv6 Uptime: %d`, r.Epoch, r.NodeID, r.Monitor, r.IPV4Uptime, r.IPV6Uptime)
+}
+
+// Uptime returns the overall uptime of the node during the epoch as a fraction between 0 and 1
+func (r UptimeReport) Uptime() sdk.Dec {
+	return sdk.NewDec(int64(r.IPV4Uptime) + int64(r.IPV6Uptime)).QuoInt64(200)
+}
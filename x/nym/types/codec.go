@@ -27,6 +27,9 @@ func RegisterCodec(cdc *codec.Codec) {
 	cdc.RegisterConcrete(MsgCreateMixnode{}, "nym/CreateMixnode", nil)
 	cdc.RegisterConcrete(MsgSetMixnode{}, "nym/SetMixnode", nil)
 	cdc.RegisterConcrete(MsgDeleteMixnode{}, "nym/DeleteMixnode", nil)
+	cdc.RegisterConcrete(MsgDelegate{}, "nym/Delegate", nil)
+	cdc.RegisterConcrete(MsgUndelegate{}, "nym/Undelegate", nil)
+	cdc.RegisterConcrete(MsgSubmitUptimeReport{}, "nym/SubmitUptimeReport", nil)
 }
 
 // ModuleCdc defines the module codec
@@ -21,4 +21,32 @@ import (
 var (
 	// ErrInvalid ...
 	ErrInvalid = sdkerrors.Register(ModuleName, 1, "custom error message")
+	// ErrNetworkAtCapacity is returned when the maximum number of nodes of given type has already been reached
+	ErrNetworkAtCapacity = sdkerrors.Register(ModuleName, 2, "network is already at capacity")
+	// ErrInvalidLayer is returned when a mixnode declares a layer outside the configured range
+	ErrInvalidLayer = sdkerrors.Register(ModuleName, 3, "invalid mixnode layer")
+	// ErrInvalidVersion is returned when a node is not running the required system version
+	ErrInvalidVersion = sdkerrors.Register(ModuleName, 4, "invalid node version")
+	// ErrInsufficientBond is returned when a node is registered with less than the minimum bond
+	ErrInsufficientBond = sdkerrors.Register(ModuleName, 5, "insufficient bond")
+	// ErrNoDelegation is returned when a delegation to the given mixnode by the given account does not exist
+	ErrNoDelegation = sdkerrors.Register(ModuleName, 6, "no delegation found")
+	// ErrInsufficientDelegation is returned when undelegating more than was delegated
+	ErrInsufficientDelegation = sdkerrors.Register(ModuleName, 7, "insufficient delegation")
+	// ErrUnauthorizedMonitor is returned when an uptime report is sent by an account that is not a network monitor
+	ErrUnauthorizedMonitor = sdkerrors.Register(ModuleName, 8, "account is not an authorized network monitor")
+	// ErrDuplicateIdentity is returned when a node is registered with an identity key that is already in use
+	ErrDuplicateIdentity = sdkerrors.Register(ModuleName, 9, "identity key is already registered")
+	// ErrInvalidKey is returned when a node key is not a base58 encoded key of the expected length
+	ErrInvalidKey = sdkerrors.Register(ModuleName, 10, "invalid node key")
+	// ErrInvalidHost is returned when a node address is not of the form host:port
+	ErrInvalidHost = sdkerrors.Register(ModuleName, 11, "invalid node host")
+	// ErrInvalidLocation is returned when a node location is too long
+	ErrInvalidLocation = sdkerrors.Register(ModuleName, 12, "invalid node location")
+	// ErrDuplicateID is returned when a node is registered with an id that is already in use
+	ErrDuplicateID = sdkerrors.Register(ModuleName, 13, "node id is already registered")
+	// ErrInvalidID is returned when a node id is empty, too long or contains characters other than letters, digits and hyphens
+	ErrInvalidID = sdkerrors.Register(ModuleName, 14, "invalid node id")
+	// ErrDuplicateUptimeReport is returned when a monitor reports the uptime of a node it already reported during the same epoch
+	ErrDuplicateUptimeReport = sdkerrors.Register(ModuleName, 15, "uptime already reported during this epoch")
 )
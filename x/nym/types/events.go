@@ -16,14 +16,31 @@ package types
 
 // nym module event types
 const (
-	// TODO: Create your event types
-	// EventType<Action>    		= "action"
+	EventTypeCreateMixnode = "create_mixnode"
+	EventTypeUpdateMixnode = "update_mixnode"
+	EventTypeDeleteMixnode = "delete_mixnode"
+	EventTypeCreateGateway = "create_gateway"
+	EventTypeUpdateGateway = "update_gateway"
+	EventTypeDeleteGateway = "delete_gateway"
+	EventTypeDelegate      = "delegate"
+	EventTypeUndelegate    = "undelegate"
+	EventTypeUptimeReport  = "uptime_report"
+	EventTypeReward        = "reward"
+	EventTypeChangeLayer   = "change_layer"
 
-	// TODO: Create keys fo your events, the values will be derivided from the msg
-	// AttributeKeyAddress  		= "address"
-
-	// TODO: Some events may not have values for that reason you want to emit that something happened.
-	// AttributeValueDoubleSign = "double_sign"
+	AttributeKeyID            = "id"
+	AttributeKeyIdentityKey   = "identity_key"
+	AttributeKeyCreator       = "creator"
+	AttributeKeyHost          = "host"
+	AttributeKeyLayer         = "layer"
+	AttributeKeyMixnodeID     = "mixnode_id"
+	AttributeKeyDelegator     = "delegator"
+	AttributeKeyIPV4Uptime    = "ipv4_uptime"
+	AttributeKeyIPV6Uptime    = "ipv6_uptime"
+	AttributeKeyReputation    = "reputation"
+	AttributeKeyRecipient     = "recipient"
+	AttributeKeyEpoch         = "epoch"
+	AttributeKeyPreviousLayer = "previous_layer"
 
 	AttributeValueCategory = ModuleName
 )
@@ -14,27 +14,129 @@
 
 package types
 
+import (
+	"fmt"
+)
+
 // GenesisState - all nym state that must be provided at genesis
 type GenesisState struct {
-	// TODO: Fill out what is needed by the module for genesis
+	Params        Params         `json:"params" yaml:"params"`
+	Mixnodes      []Mixnode      `json:"mixnodes" yaml:"mixnodes"`
+	Gateways      []Gateway      `json:"gateways" yaml:"gateways"`
+	Delegations   []Delegation   `json:"delegations" yaml:"delegations"`
+	UptimeReports []UptimeReport `json:"uptimeReports" yaml:"uptimeReports"`
 }
 
 // NewGenesisState creates a new GenesisState object
-func NewGenesisState( /* TODO: Fill out with what is needed for genesis state */ ) GenesisState {
+func NewGenesisState(params Params, mixnodes []Mixnode, gateways []Gateway, delegations []Delegation, uptimeReports []UptimeReport) GenesisState {
 	return GenesisState{
-		// TODO: Fill out according to your genesis state
+		Params:        params,
+		Mixnodes:      mixnodes,
+		Gateways:      gateways,
+		Delegations:   delegations,
+		UptimeReports: uptimeReports,
 	}
 }
 
 // DefaultGenesisState - default GenesisState used by Cosmos Hub
 func DefaultGenesisState() GenesisState {
 	return GenesisState{
-		// TODO: Fill out according to your genesis state, these values will be initialized but empty
+		Params:        DefaultParams(),
+		Mixnodes:      []Mixnode{},
+		Gateways:      []Gateway{},
+		Delegations:   []Delegation{},
+		UptimeReports: []UptimeReport{},
 	}
 }
 
 // ValidateGenesis validates the nym genesis parameters
 func ValidateGenesis(data GenesisState) error {
-	// TODO: Create a sanity check to make sure the state conforms to the modules needs
+	if err := data.Params.Validate(); err != nil {
+		return err
+	}
+
+	mixnodeIDs := make(map[string]bool, len(data.Mixnodes))
+	mixnodeIdentities := make(map[string]bool, len(data.Mixnodes))
+	for _, mixnode := range data.Mixnodes {
+		if err := validateID(mixnode.ID); err != nil {
+			return fmt.Errorf("mixnode with invalid id found in genesis state: %w", err)
+		}
+		if mixnodeIDs[mixnode.ID] {
+			return fmt.Errorf("duplicate mixnode id %s found in genesis state", mixnode.ID)
+		}
+		mixnodeIDs[mixnode.ID] = true
+		if mixnodeIdentities[mixnode.PubKey] {
+			return fmt.Errorf("duplicate mixnode identity key %s found in genesis state", mixnode.PubKey)
+		}
+		mixnodeIdentities[mixnode.PubKey] = true
+
+		if mixnode.Creator.Empty() {
+			return fmt.Errorf("mixnode %s has an empty creator", mixnode.ID)
+		}
+		if !mixnode.Bond.IsValid() {
+			return fmt.Errorf("mixnode %s has an invalid bond: %s", mixnode.ID, mixnode.Bond)
+		}
+		if err := validateMixnode(mixnode.PubKey, mixnode.SphinxKey, mixnode.Layer, mixnode.Version, mixnode.Host, mixnode.Location); err != nil {
+			return fmt.Errorf("mixnode %s is invalid: %w", mixnode.ID, err)
+		}
+	}
+
+	gatewayIDs := make(map[string]bool, len(data.Gateways))
+	gatewayIdentities := make(map[string]bool, len(data.Gateways))
+	for _, gateway := range data.Gateways {
+		if err := validateID(gateway.ID); err != nil {
+			return fmt.Errorf("gateway with invalid id found in genesis state: %w", err)
+		}
+		if gatewayIDs[gateway.ID] {
+			return fmt.Errorf("duplicate gateway id %s found in genesis state", gateway.ID)
+		}
+		gatewayIDs[gateway.ID] = true
+		if gatewayIdentities[gateway.IdentityKey] {
+			return fmt.Errorf("duplicate gateway identity key %s found in genesis state", gateway.IdentityKey)
+		}
+		gatewayIdentities[gateway.IdentityKey] = true
+
+		if gateway.Creator.Empty() {
+			return fmt.Errorf("gateway %s has an empty creator", gateway.ID)
+		}
+		if !gateway.Bond.IsValid() {
+			return fmt.Errorf("gateway %s has an invalid bond: %s", gateway.ID, gateway.Bond)
+		}
+		if err := validateGateway(gateway.IdentityKey, gateway.SphinxKey, gateway.ClientListener, gateway.MixnetListener, gateway.Location); err != nil {
+			return fmt.Errorf("gateway %s is invalid: %w", gateway.ID, err)
+		}
+	}
+
+	delegations := make(map[string]bool, len(data.Delegations))
+	for _, delegation := range data.Delegations {
+		if !mixnodeIDs[delegation.MixnodeID] {
+			return fmt.Errorf("delegation to unknown mixnode %s found in genesis state", delegation.MixnodeID)
+		}
+		if delegation.Delegator.Empty() {
+			return fmt.Errorf("delegation to mixnode %s has an empty delegator", delegation.MixnodeID)
+		}
+		key := string(DelegationKey(delegation.MixnodeID, delegation.Delegator))
+		if delegations[key] {
+			return fmt.Errorf("duplicate delegation by %s to mixnode %s found in genesis state", delegation.Delegator, delegation.MixnodeID)
+		}
+		delegations[key] = true
+
+		if !delegation.Amount.IsValid() || !delegation.Amount.IsPositive() {
+			return fmt.Errorf("delegation by %s to mixnode %s has an invalid amount: %s", delegation.Delegator, delegation.MixnodeID, delegation.Amount)
+		}
+	}
+
+	uptimeReports := make(map[string]bool, len(data.UptimeReports))
+	for _, report := range data.UptimeReports {
+		if err := report.Validate(); err != nil {
+			return err
+		}
+		key := string(UptimeKey(report.Epoch, report.NodeID, report.Monitor))
+		if uptimeReports[key] {
+			return fmt.Errorf("duplicate uptime report for node %s by %s during epoch %d found in genesis state", report.NodeID, report.Monitor, report.Epoch)
+		}
+		uptimeReports[key] = true
+	}
+
 	return nil
 }
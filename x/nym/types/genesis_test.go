@@ -0,0 +1,136 @@
+// Copyright 2020 Nym Technologies SA
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package types
+
+import (
+	"testing"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	identityKey      = "BnLYqQjb8K6TmW5oFdNZrUTocGxa3rgzBvapQrf8XUbF"
+	otherIdentityKey = "FioFa8nMmPpQnYi7JyojoTuwGLeyNS8BF4ChPr29zUML"
+)
+
+var (
+	creator   = sdk.AccAddress([]byte("creator_____________"))
+	delegator = sdk.AccAddress([]byte("delegator___________"))
+	monitor   = sdk.AccAddress([]byte("monitor_____________"))
+)
+
+func genesisMixnode(id string, pubKey string) Mixnode {
+	return Mixnode{
+		Creator:   creator,
+		ID:        id,
+		PubKey:    pubKey,
+		SphinxKey: pubKey,
+		Layer:     1,
+		Version:   DefaultSystemVersion,
+		Host:      "1.1.1.1:1789",
+		Location:  "Neuchatel",
+		Bond:      DefaultMinimumMixnodeBond,
+	}
+}
+
+func genesisGateway(id string, identityKey string) Gateway {
+	return Gateway{
+		Creator:        creator,
+		ID:             id,
+		IdentityKey:    identityKey,
+		SphinxKey:      identityKey,
+		ClientListener: "ws://1.1.1.1:9000",
+		MixnetListener: "1.1.1.1:1789",
+		Location:       "Neuchatel",
+		Bond:           DefaultMinimumGatewayBond,
+	}
+}
+
+// validGenesis returns a genesis state with a node of every type, a delegation and an uptime report
+func validGenesis() GenesisState {
+	genesis := DefaultGenesisState()
+	genesis.Mixnodes = []Mixnode{genesisMixnode("mixnode", identityKey)}
+	genesis.Gateways = []Gateway{genesisGateway("gateway", identityKey)}
+	genesis.Delegations = []Delegation{NewDelegation("mixnode", delegator, sdk.NewInt64Coin(DefaultBondDenom, 10))}
+	genesis.UptimeReports = []UptimeReport{NewUptimeReport(0, monitor, NodeUptime{NodeID: "mixnode", IPV4Uptime: 100, IPV6Uptime: 100})}
+	return genesis
+}
+
+func TestValidateGenesis(t *testing.T) {
+	assert.NoError(t, ValidateGenesis(DefaultGenesisState()))
+	assert.NoError(t, ValidateGenesis(validGenesis()))
+
+	for name, invalidate := range map[string]func(*GenesisState){
+		"invalid params": func(g *GenesisState) {
+			g.Params.Layers = 0
+		},
+		"invalid mixnode id": func(g *GenesisState) {
+			g.Mixnodes[0].ID = "mix/node"
+		},
+		"duplicate mixnode id": func(g *GenesisState) {
+			g.Mixnodes = append(g.Mixnodes, genesisMixnode("mixnode", otherIdentityKey))
+		},
+		"duplicate mixnode identity": func(g *GenesisState) {
+			g.Mixnodes = append(g.Mixnodes, genesisMixnode("other", identityKey))
+		},
+		"mixnode without creator": func(g *GenesisState) {
+			g.Mixnodes[0].Creator = nil
+		},
+		"mixnode with invalid bond": func(g *GenesisState) {
+			g.Mixnodes[0].Bond = sdk.Coin{Denom: "NYM!", Amount: sdk.NewInt(100)}
+		},
+		"mixnode with invalid host": func(g *GenesisState) {
+			g.Mixnodes[0].Host = "1.1.1.1"
+		},
+		"invalid gateway id": func(g *GenesisState) {
+			g.Gateways[0].ID = ""
+		},
+		"duplicate gateway id": func(g *GenesisState) {
+			g.Gateways = append(g.Gateways, genesisGateway("gateway", otherIdentityKey))
+		},
+		"duplicate gateway identity": func(g *GenesisState) {
+			g.Gateways = append(g.Gateways, genesisGateway("other", identityKey))
+		},
+		"gateway without creator": func(g *GenesisState) {
+			g.Gateways[0].Creator = nil
+		},
+		"gateway with invalid sphinx key": func(g *GenesisState) {
+			g.Gateways[0].SphinxKey = "key"
+		},
+		"delegation to unknown mixnode": func(g *GenesisState) {
+			g.Delegations[0].MixnodeID = "gateway"
+		},
+		"delegation without delegator": func(g *GenesisState) {
+			g.Delegations[0].Delegator = nil
+		},
+		"duplicate delegation": func(g *GenesisState) {
+			g.Delegations = append(g.Delegations, g.Delegations[0])
+		},
+		"empty delegation": func(g *GenesisState) {
+			g.Delegations[0].Amount = sdk.NewInt64Coin(DefaultBondDenom, 0)
+		},
+		"invalid uptime report": func(g *GenesisState) {
+			g.UptimeReports[0].IPV4Uptime = 101
+		},
+		"duplicate uptime report": func(g *GenesisState) {
+			g.UptimeReports = append(g.UptimeReports, g.UptimeReports[0])
+		},
+	} {
+		genesis := validGenesis()
+		invalidate(&genesis)
+		assert.Error(t, ValidateGenesis(genesis), name)
+	}
+}
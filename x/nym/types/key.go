@@ -14,6 +14,12 @@
 
 package types
 
+import (
+	"fmt"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
 const (
 	// ModuleName is the name of the module
 	ModuleName = "nym"
@@ -35,3 +41,57 @@ const (
 const (
 	GatewayPrefix = "gateway-"
 )
+
+const (
+	// DelegationPrefix prefixes delegations keyed by mixnode ID and delegator address
+	DelegationPrefix = "delegation-"
+	// DelegatorPrefix prefixes the index of delegations keyed by delegator address and mixnode ID
+	DelegatorPrefix = "delegator-"
+)
+
+const (
+	// MixnodeIdentityPrefix prefixes the index of mixnode IDs keyed by their identity key
+	MixnodeIdentityPrefix = "identity-mixnode-"
+	// GatewayIdentityPrefix prefixes the index of gateway IDs keyed by their identity key
+	GatewayIdentityPrefix = "identity-gateway-"
+)
+
+const (
+	// UptimePrefix prefixes uptime reports keyed by epoch, node ID and monitor address
+	UptimePrefix = "uptime-"
+)
+
+// UptimeKey returns the store key of the uptime report of the given node made by monitor during the given epoch
+func UptimeKey(epoch int64, nodeID string, monitor sdk.AccAddress) []byte {
+	return append(NodeUptimesKey(epoch, nodeID), monitor.Bytes()...)
+}
+
+// NodeUptimesKey returns the key prefix of all uptime reports of the given node during the given epoch
+func NodeUptimesKey(epoch int64, nodeID string) []byte {
+	return append(EpochUptimesKey(epoch), []byte(nodeID+"/")...)
+}
+
+// EpochUptimesKey returns the key prefix of all uptime reports made during the given epoch
+func EpochUptimesKey(epoch int64) []byte {
+	return []byte(fmt.Sprintf("%s%d/", UptimePrefix, epoch))
+}
+
+// DelegationKey returns the store key of the delegation made by delegator to the given mixnode
+func DelegationKey(mixnodeID string, delegator sdk.AccAddress) []byte {
+	return append(MixnodeDelegationsKey(mixnodeID), delegator.Bytes()...)
+}
+
+// MixnodeDelegationsKey returns the key prefix of all delegations made to the given mixnode
+func MixnodeDelegationsKey(mixnodeID string) []byte {
+	return []byte(DelegationPrefix + mixnodeID + "/")
+}
+
+// DelegatorDelegationKey returns the index key of the delegation made by delegator to the given mixnode
+func DelegatorDelegationKey(delegator sdk.AccAddress, mixnodeID string) []byte {
+	return append(DelegatorDelegationsKey(delegator), []byte(mixnodeID)...)
+}
+
+// DelegatorDelegationsKey returns the index key prefix of all delegations made by the given delegator
+func DelegatorDelegationsKey(delegator sdk.AccAddress) []byte {
+	return []byte(DelegatorPrefix + delegator.String() + "/")
+}
@@ -0,0 +1,156 @@
+// Copyright 2020 Nym Technologies SA
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package types
+
+import (
+	"sort"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+// LayerNode is a mixnode taken into account when assigning layers. Its weight is whatever
+// should be spread evenly across the layers, such as stake or reputation.
+type LayerNode struct {
+	ID     string
+	Layer  uint32
+	Weight sdk.Int
+}
+
+// LayerMove is a mixnode moved to another layer by a rebalance
+type LayerMove struct {
+	ID   string `json:"id" yaml:"id"`
+	From uint32 `json:"from" yaml:"from"`
+	To   uint32 `json:"to" yaml:"to"`
+}
+
+// LayerLoad is the number of mixnodes in a layer and their total weight
+type LayerLoad struct {
+	Layer  uint32  `json:"layer" yaml:"layer"`
+	Nodes  int     `json:"nodes" yaml:"nodes"`
+	Weight sdk.Int `json:"weight" yaml:"weight"`
+}
+
+// LayerRebalance is a rebalance of the mixnet layers, with the load of the layers before and after it
+type LayerRebalance struct {
+	Moves  []LayerMove `json:"moves" yaml:"moves"`
+	Before []LayerLoad `json:"before" yaml:"before"`
+	After  []LayerLoad `json:"after" yaml:"after"`
+}
+
+// NewLayerRebalance computes the rebalance of the given mixnodes across the layers
+func NewLayerRebalance(nodes []LayerNode, layers uint32) LayerRebalance {
+	moves := BalanceLayers(nodes, layers)
+	return LayerRebalance{
+		Moves:  moves,
+		Before: LayerLoads(nodes, layers),
+		After:  LayerLoads(ApplyLayerMoves(nodes, moves), layers),
+	}
+}
+
+// LayerLoads returns the load of each of the layers, ignoring nodes outside of them
+func LayerLoads(nodes []LayerNode, layers uint32) []LayerLoad {
+	loads := make([]LayerLoad, layers)
+	for i := range loads {
+		loads[i] = LayerLoad{Layer: uint32(i) + 1, Weight: sdk.ZeroInt()}
+	}
+	for _, node := range nodes {
+		if node.Layer >= 1 && node.Layer <= layers {
+			loads[node.Layer-1].Nodes++
+			loads[node.Layer-1].Weight = loads[node.Layer-1].Weight.Add(node.Weight)
+		}
+	}
+	return loads
+}
+
+// LeastLoadedLayer returns the layer a new mixnode should join: the one with the fewest nodes,
+// then the lowest weight, then the lowest layer number.
+func LeastLoadedLayer(nodes []LayerNode, layers uint32) uint32 {
+	return leastLoaded(LayerLoads(nodes, layers)).Layer
+}
+
+// BalanceLayers returns the moves leaving every layer with the same number of mixnodes, give or take one,
+// while spreading the weight as evenly as possible. Nodes outside of the layers are always moved, and
+// nodes are only moved out of a layer holding too many of them, lightest first. Rebalancing balanced
+// layers results in no moves.
+func BalanceLayers(nodes []LayerNode, layers uint32) []LayerMove {
+	if layers == 0 || len(nodes) == 0 {
+		return []LayerMove{}
+	}
+
+	sorted := make([]LayerNode, len(nodes))
+	copy(sorted, nodes)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if !sorted[i].Weight.Equal(sorted[j].Weight) {
+			return sorted[i].Weight.GT(sorted[j].Weight)
+		}
+		return sorted[i].ID < sorted[j].ID
+	})
+
+	// every layer keeps its heaviest nodes up to the number all layers can hold,
+	// the remaining ones are then placed one by one, heaviest first, on the least loaded layer
+	capacity := len(nodes) / int(layers)
+	loads := LayerLoads(nil, layers)
+	var unplaced []LayerNode
+	for _, node := range sorted {
+		if node.Layer >= 1 && node.Layer <= layers && loads[node.Layer-1].Nodes < capacity {
+			loads[node.Layer-1].Nodes++
+			loads[node.Layer-1].Weight = loads[node.Layer-1].Weight.Add(node.Weight)
+			continue
+		}
+		unplaced = append(unplaced, node)
+	}
+
+	moves := []LayerMove{}
+	for _, node := range unplaced {
+		load := leastLoaded(loads)
+		// staying put is as good as any other layer with as few nodes, and avoids churn on every rebalance
+		if node.Layer >= 1 && node.Layer <= layers && loads[node.Layer-1].Nodes == load.Nodes {
+			load = &loads[node.Layer-1]
+		}
+		load.Nodes++
+		load.Weight = load.Weight.Add(node.Weight)
+		if load.Layer != node.Layer {
+			moves = append(moves, LayerMove{ID: node.ID, From: node.Layer, To: load.Layer})
+		}
+	}
+	return moves
+}
+
+// ApplyLayerMoves returns a copy of the nodes with the moves applied
+func ApplyLayerMoves(nodes []LayerNode, moves []LayerMove) []LayerNode {
+	targets := make(map[string]uint32, len(moves))
+	for _, move := range moves {
+		targets[move.ID] = move.To
+	}
+	moved := make([]LayerNode, len(nodes))
+	for i, node := range nodes {
+		if layer, ok := targets[node.ID]; ok {
+			node.Layer = layer
+		}
+		moved[i] = node
+	}
+	return moved
+}
+
+func leastLoaded(loads []LayerLoad) *LayerLoad {
+	least := &loads[0]
+	for i := range loads[1:] {
+		load := &loads[i+1]
+		if load.Nodes < least.Nodes || (load.Nodes == least.Nodes && load.Weight.LT(least.Weight)) {
+			least = load
+		}
+	}
+	return least
+}
@@ -15,23 +15,60 @@
 package types
 
 import (
+	"errors"
 	"fmt"
 
+	sdk "github.com/cosmos/cosmos-sdk/types"
 	"github.com/cosmos/cosmos-sdk/x/params"
 )
 
 // Default parameter namespace
 const (
 	DefaultParamspace = ModuleName
-	// TODO: Define your default parameters
+
+	// DefaultBondDenom is the denomination of the coins used for bonding nodes
+	DefaultBondDenom = "nym"
+	// DefaultMaximumMixnodes is the default maximum number of mixnodes in the network
+	DefaultMaximumMixnodes uint32 = 1500
+	// DefaultMaximumGateways is the default maximum number of gateways in the network
+	DefaultMaximumGateways uint32 = 1000
+	// DefaultLayers is the default number of mixnet layers
+	DefaultLayers uint32 = 3
+	// DefaultSystemVersion is the default version nodes are required to run
+	DefaultSystemVersion = "0.9.2"
+	// DefaultReputationThreshold is the default reputation a node needs to be part of the active topology
+	DefaultReputationThreshold int64 = 100
+	// DefaultEpochLength is the default number of blocks between reward distributions
+	DefaultEpochLength int64 = 720
+)
+
+// Default minimum bonds required to register nodes
+var (
+	// DefaultMinimumMixnodeBond is the default minimum amount of coins required to bond a mixnode
+	DefaultMinimumMixnodeBond = sdk.NewInt64Coin(DefaultBondDenom, 100)
+	// DefaultMinimumGatewayBond is the default minimum amount of coins required to bond a gateway
+	DefaultMinimumGatewayBond = sdk.NewInt64Coin(DefaultBondDenom, 100)
+	// DefaultEpochReward is the default amount of coins minted and distributed to mixnodes at the end of each epoch
+	DefaultEpochReward = sdk.NewInt64Coin(DefaultBondDenom, 1000)
 )
 
 // Parameter store keys
 var (
-// TODO: Define your keys for the parameter store
-// KeyParamName          = []byte("ParamName")
+	KeyMinimumMixnodeBond  = []byte("MinimumMixnodeBond")
+	KeyMinimumGatewayBond  = []byte("MinimumGatewayBond")
+	KeyMaximumMixnodes     = []byte("MaximumMixnodes")
+	KeyMaximumGateways     = []byte("MaximumGateways")
+	KeyLayers              = []byte("Layers")
+	KeySystemVersion       = []byte("SystemVersion")
+	KeyReputationThreshold = []byte("ReputationThreshold")
+	KeyEpochLength         = []byte("EpochLength")
+	KeyEpochReward         = []byte("EpochReward")
+	KeyNetworkMonitors     = []byte("NetworkMonitors")
+	KeyRebalanceLayers     = []byte("RebalanceLayers")
 )
 
+var _ params.ParamSet = (*Params)(nil)
+
 // ParamKeyTable for nym module
 func ParamKeyTable() params.KeyTable {
 	return params.NewKeyTable().RegisterParamSet(&Params{})
@@ -39,33 +76,216 @@ func ParamKeyTable() params.KeyTable {
 
 // Params - used for initializing default parameter for nym at genesis
 type Params struct {
-	// TODO: Add your Paramaters to the Paramter struct
-	// KeyParamName string `json:"key_param_name"`
+	MinimumMixnodeBond  sdk.Coin         `json:"minimumMixnodeBond" yaml:"minimumMixnodeBond"`
+	MinimumGatewayBond  sdk.Coin         `json:"minimumGatewayBond" yaml:"minimumGatewayBond"`
+	MaximumMixnodes     uint32           `json:"maximumMixnodes" yaml:"maximumMixnodes"`
+	MaximumGateways     uint32           `json:"maximumGateways" yaml:"maximumGateways"`
+	Layers              uint32           `json:"layers" yaml:"layers"`
+	SystemVersion       string           `json:"systemVersion" yaml:"systemVersion"`
+	ReputationThreshold int64            `json:"reputationThreshold" yaml:"reputationThreshold"`
+	EpochLength         int64            `json:"epochLength" yaml:"epochLength"`
+	EpochReward         sdk.Coin         `json:"epochReward" yaml:"epochReward"`
+	NetworkMonitors     []sdk.AccAddress `json:"networkMonitors" yaml:"networkMonitors"`
+	// RebalanceLayers enables moving mixnodes between layers at the end of every epoch to balance their stake
+	RebalanceLayers bool `json:"rebalanceLayers" yaml:"rebalanceLayers"`
 }
 
 // NewParams creates a new Params object
-func NewParams( /* TODO: Pass in the paramters*/ ) Params {
+func NewParams(minimumMixnodeBond sdk.Coin, minimumGatewayBond sdk.Coin, maximumMixnodes uint32, maximumGateways uint32, layers uint32, systemVersion string, reputationThreshold int64, epochLength int64, epochReward sdk.Coin, networkMonitors []sdk.AccAddress, rebalanceLayers bool) Params {
 	return Params{
-		// TODO: Create your Params Type
+		MinimumMixnodeBond:  minimumMixnodeBond,
+		MinimumGatewayBond:  minimumGatewayBond,
+		MaximumMixnodes:     maximumMixnodes,
+		MaximumGateways:     maximumGateways,
+		Layers:              layers,
+		SystemVersion:       systemVersion,
+		ReputationThreshold: reputationThreshold,
+		EpochLength:         epochLength,
+		EpochReward:         epochReward,
+		NetworkMonitors:     networkMonitors,
+		RebalanceLayers:     rebalanceLayers,
 	}
 }
 
 // String implements the stringer interface for Params
 func (p Params) String() string {
-	return fmt.Sprintf(`
-	// TODO: Return all the params as a string
-	`)
+	return fmt.Sprintf(`Params:
+  Minimum Mixnode Bond: %s
+  Minimum Gateway Bond: %s
+  Maximum Mixnodes:     %d
+  Maximum Gateways:     %d
+  Layers:               %d
+  System Version:       %s
+  Reputation Threshold: %d
+  Epoch Length:         %d
+  Epoch Reward:         %s
+  Network Monitors:     %v
+  Rebalance Layers:     %t`,
+		p.MinimumMixnodeBond, p.MinimumGatewayBond, p.MaximumMixnodes, p.MaximumGateways, p.Layers, p.SystemVersion, p.ReputationThreshold,
+		p.EpochLength, p.EpochReward, p.NetworkMonitors, p.RebalanceLayers)
 }
 
 // ParamSetPairs - Implements params.ParamSet
 func (p *Params) ParamSetPairs() params.ParamSetPairs {
 	return params.ParamSetPairs{
-		// TODO: Pair your key with the param
-		// params.NewParamSetPair(KeyParamName, &p.ParamName),
+		params.NewParamSetPair(KeyMinimumMixnodeBond, &p.MinimumMixnodeBond, validateMinimumBond),
+		params.NewParamSetPair(KeyMinimumGatewayBond, &p.MinimumGatewayBond, validateMinimumBond),
+		params.NewParamSetPair(KeyMaximumMixnodes, &p.MaximumMixnodes, validateMaximumNodes),
+		params.NewParamSetPair(KeyMaximumGateways, &p.MaximumGateways, validateMaximumNodes),
+		params.NewParamSetPair(KeyLayers, &p.Layers, validateLayers),
+		params.NewParamSetPair(KeySystemVersion, &p.SystemVersion, validateSystemVersion),
+		params.NewParamSetPair(KeyReputationThreshold, &p.ReputationThreshold, validateReputationThreshold),
+		params.NewParamSetPair(KeyEpochLength, &p.EpochLength, validateEpochLength),
+		params.NewParamSetPair(KeyEpochReward, &p.EpochReward, validateEpochReward),
+		params.NewParamSetPair(KeyNetworkMonitors, &p.NetworkMonitors, validateNetworkMonitors),
+		params.NewParamSetPair(KeyRebalanceLayers, &p.RebalanceLayers, validateRebalanceLayers),
+	}
+}
+
+// Validate performs basic validation on nym parameters.
+func (p Params) Validate() error {
+	if err := validateMinimumBond(p.MinimumMixnodeBond); err != nil {
+		return err
+	}
+	if err := validateMinimumBond(p.MinimumGatewayBond); err != nil {
+		return err
+	}
+	if err := validateMaximumNodes(p.MaximumMixnodes); err != nil {
+		return err
+	}
+	if err := validateMaximumNodes(p.MaximumGateways); err != nil {
+		return err
+	}
+	if err := validateLayers(p.Layers); err != nil {
+		return err
+	}
+	if err := validateSystemVersion(p.SystemVersion); err != nil {
+		return err
+	}
+	if err := validateReputationThreshold(p.ReputationThreshold); err != nil {
+		return err
 	}
+	if err := validateEpochLength(p.EpochLength); err != nil {
+		return err
+	}
+	if err := validateEpochReward(p.EpochReward); err != nil {
+		return err
+	}
+	if err := validateNetworkMonitors(p.NetworkMonitors); err != nil {
+		return err
+	}
+	return validateRebalanceLayers(p.RebalanceLayers)
+}
+
+// IsNetworkMonitor returns whether the given address is authorized to submit uptime reports
+func (p Params) IsNetworkMonitor(addr sdk.AccAddress) bool {
+	for _, monitor := range p.NetworkMonitors {
+		if monitor.Equals(addr) {
+			return true
+		}
+	}
+	return false
 }
 
 // DefaultParams defines the parameters for this module
 func DefaultParams() Params {
-	return NewParams( /* TODO: Pass in your default Params */ )
+	return NewParams(DefaultMinimumMixnodeBond, DefaultMinimumGatewayBond, DefaultMaximumMixnodes, DefaultMaximumGateways, DefaultLayers, DefaultSystemVersion, DefaultReputationThreshold,
+		DefaultEpochLength, DefaultEpochReward, []sdk.AccAddress{}, false)
+}
+
+func validateMinimumBond(i interface{}) error {
+	v, ok := i.(sdk.Coin)
+	if !ok {
+		return fmt.Errorf("invalid parameter type: %T", i)
+	}
+	if !v.IsValid() {
+		return fmt.Errorf("invalid minimum bond: %s", v)
+	}
+	return nil
+}
+
+func validateMaximumNodes(i interface{}) error {
+	v, ok := i.(uint32)
+	if !ok {
+		return fmt.Errorf("invalid parameter type: %T", i)
+	}
+	if v == 0 {
+		return fmt.Errorf("maximum number of nodes must be positive: %d", v)
+	}
+	return nil
+}
+
+func validateLayers(i interface{}) error {
+	v, ok := i.(uint32)
+	if !ok {
+		return fmt.Errorf("invalid parameter type: %T", i)
+	}
+	if v == 0 {
+		return fmt.Errorf("number of layers must be positive: %d", v)
+	}
+	return nil
+}
+
+func validateSystemVersion(i interface{}) error {
+	v, ok := i.(string)
+	if !ok {
+		return fmt.Errorf("invalid parameter type: %T", i)
+	}
+	if v == "" {
+		return errors.New("system version cannot be blank")
+	}
+	return nil
+}
+
+func validateReputationThreshold(i interface{}) error {
+	v, ok := i.(int64)
+	if !ok {
+		return fmt.Errorf("invalid parameter type: %T", i)
+	}
+	if v < 0 {
+		return fmt.Errorf("reputation threshold cannot be negative: %d", v)
+	}
+	return nil
+}
+
+func validateEpochLength(i interface{}) error {
+	v, ok := i.(int64)
+	if !ok {
+		return fmt.Errorf("invalid parameter type: %T", i)
+	}
+	if v <= 0 {
+		return fmt.Errorf("epoch length must be positive: %d", v)
+	}
+	return nil
+}
+
+func validateEpochReward(i interface{}) error {
+	v, ok := i.(sdk.Coin)
+	if !ok {
+		return fmt.Errorf("invalid parameter type: %T", i)
+	}
+	if !v.IsValid() {
+		return fmt.Errorf("invalid epoch reward: %s", v)
+	}
+	return nil
+}
+
+func validateNetworkMonitors(i interface{}) error {
+	v, ok := i.([]sdk.AccAddress)
+	if !ok {
+		return fmt.Errorf("invalid parameter type: %T", i)
+	}
+	for _, monitor := range v {
+		if monitor.Empty() {
+			return errors.New("network monitor address cannot be empty")
+		}
+	}
+	return nil
+}
+
+func validateRebalanceLayers(i interface{}) error {
+	if _, ok := i.(bool); !ok {
+		return fmt.Errorf("invalid parameter type: %T", i)
+	}
+	return nil
 }
@@ -0,0 +1,66 @@
+// Copyright 2020 Nym Technologies SA
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package types
+
+import (
+	"testing"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParamsValidate(t *testing.T) {
+	assert.NoError(t, DefaultParams().Validate())
+
+	for name, test := range map[string]struct {
+		update func(*Params)
+		valid  bool
+	}{
+		"zero minimum bond":         {func(p *Params) { p.MinimumMixnodeBond = sdk.NewInt64Coin(DefaultBondDenom, 0) }, true},
+		"invalid minimum bond":      {func(p *Params) { p.MinimumGatewayBond = sdk.Coin{Denom: "NYM!", Amount: sdk.NewInt(1)} }, false},
+		"one mixnode":               {func(p *Params) { p.MaximumMixnodes = 1 }, true},
+		"no mixnodes":               {func(p *Params) { p.MaximumMixnodes = 0 }, false},
+		"no gateways":               {func(p *Params) { p.MaximumGateways = 0 }, false},
+		"one layer":                 {func(p *Params) { p.Layers = 1 }, true},
+		"no layers":                 {func(p *Params) { p.Layers = 0 }, false},
+		"blank system version":      {func(p *Params) { p.SystemVersion = "" }, false},
+		"zero reputation threshold": {func(p *Params) { p.ReputationThreshold = 0 }, true},
+		"negative threshold":        {func(p *Params) { p.ReputationThreshold = -1 }, false},
+		"one block epochs":          {func(p *Params) { p.EpochLength = 1 }, true},
+		"empty epochs":              {func(p *Params) { p.EpochLength = 0 }, false},
+		"negative epochs":           {func(p *Params) { p.EpochLength = -1 }, false},
+		"zero epoch reward":         {func(p *Params) { p.EpochReward = sdk.NewInt64Coin(DefaultBondDenom, 0) }, true},
+		"invalid epoch reward":      {func(p *Params) { p.EpochReward = sdk.Coin{Denom: "", Amount: sdk.NewInt(1)} }, false},
+		"network monitor":           {func(p *Params) { p.NetworkMonitors = []sdk.AccAddress{monitor} }, true},
+		"empty network monitor":     {func(p *Params) { p.NetworkMonitors = []sdk.AccAddress{monitor, {}} }, false},
+	} {
+		params := DefaultParams()
+		test.update(&params)
+		if test.valid {
+			assert.NoError(t, params.Validate(), name)
+		} else {
+			assert.Error(t, params.Validate(), name)
+		}
+	}
+}
+
+func TestParamsIsNetworkMonitor(t *testing.T) {
+	params := DefaultParams()
+	assert.False(t, params.IsNetworkMonitor(monitor))
+
+	params.NetworkMonitors = []sdk.AccAddress{monitor}
+	assert.True(t, params.IsNetworkMonitor(monitor))
+	assert.False(t, params.IsNetworkMonitor(creator))
+}
@@ -14,14 +14,120 @@
 
 package types
 
+import (
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+// DefaultQueryLimit is the number of nodes returned by list queries when no limit is provided
+const DefaultQueryLimit = 100
+
+// MaxQueryLimit is the maximum number of nodes returned by a single page of list queries
+const MaxQueryLimit = 1000
+
 // QueryListMixnode ...
 const QueryListMixnode = "list-mixnode"
 
 // QueryGetMixnode ...
 const QueryGetMixnode = "get-mixnode"
 
+// QueryGetMixnodeByIdentity ...
+const QueryGetMixnodeByIdentity = "get-mixnode-by-identity"
+
 // QueryListGateway ...
 const QueryListGateway = "list-gateway"
 
 // QueryGetGateway ...
 const QueryGetGateway = "get-gateway"
+
+// QueryGetGatewayByIdentity ...
+const QueryGetGatewayByIdentity = "get-gateway-by-identity"
+
+// QueryParams ...
+const QueryParams = "params"
+
+// QueryListMixnodeDelegations ...
+const QueryListMixnodeDelegations = "list-mixnode-delegations"
+
+// QueryListDelegatorDelegations ...
+const QueryListDelegatorDelegations = "list-delegator-delegations"
+
+// QueryGetUptime ...
+const QueryGetUptime = "get-uptime"
+
+// QueryGetReputation ...
+const QueryGetReputation = "get-reputation"
+
+// QueryTopology ...
+const QueryTopology = "topology"
+
+// QueryActiveTopology ...
+const QueryActiveTopology = "active-topology"
+
+// QueryLayerRebalance ...
+const QueryLayerRebalance = "layer-rebalance"
+
+// QueryMixnodesParams defines the pagination and filter parameters of the list-mixnode query.
+// Zero valued filters match every mixnode.
+type QueryMixnodesParams struct {
+	Page     int            `json:"page" yaml:"page"`
+	Limit    int            `json:"limit" yaml:"limit"`
+	Layer    int32          `json:"layer" yaml:"layer"`
+	Location string         `json:"location" yaml:"location"`
+	Version  string         `json:"version" yaml:"version"`
+	Creator  sdk.AccAddress `json:"creator" yaml:"creator"`
+}
+
+// NewQueryMixnodesParams creates a new QueryMixnodesParams instance
+func NewQueryMixnodesParams(page, limit int, layer int32, location, version string, creator sdk.AccAddress) QueryMixnodesParams {
+	return QueryMixnodesParams{
+		Page:     page,
+		Limit:    limit,
+		Layer:    layer,
+		Location: location,
+		Version:  version,
+		Creator:  creator,
+	}
+}
+
+// Matches returns whether the mixnode passes all the filters
+func (p QueryMixnodesParams) Matches(mixnode Mixnode) bool {
+	return (p.Layer == 0 || mixnode.Layer == p.Layer) &&
+		(p.Location == "" || mixnode.Location == p.Location) &&
+		(p.Version == "" || mixnode.Version == p.Version) &&
+		(p.Creator.Empty() || mixnode.Creator.Equals(p.Creator))
+}
+
+// IsFiltered returns whether any filter is set
+func (p QueryMixnodesParams) IsFiltered() bool {
+	return p.Layer != 0 || p.Location != "" || p.Version != "" || !p.Creator.Empty()
+}
+
+// QueryGatewaysParams defines the pagination and filter parameters of the list-gateway query.
+// Zero valued filters match every gateway.
+type QueryGatewaysParams struct {
+	Page     int            `json:"page" yaml:"page"`
+	Limit    int            `json:"limit" yaml:"limit"`
+	Location string         `json:"location" yaml:"location"`
+	Creator  sdk.AccAddress `json:"creator" yaml:"creator"`
+}
+
+// NewQueryGatewaysParams creates a new QueryGatewaysParams instance
+func NewQueryGatewaysParams(page, limit int, location string, creator sdk.AccAddress) QueryGatewaysParams {
+	return QueryGatewaysParams{
+		Page:     page,
+		Limit:    limit,
+		Location: location,
+		Creator:  creator,
+	}
+}
+
+// Matches returns whether the gateway passes all the filters
+func (p QueryGatewaysParams) Matches(gateway Gateway) bool {
+	return (p.Location == "" || gateway.Location == p.Location) &&
+		(p.Creator.Empty() || gateway.Creator.Equals(p.Creator))
+}
+
+// IsFiltered returns whether any filter is set
+func (p QueryGatewaysParams) IsFiltered() bool {
+	return p.Location != "" || !p.Creator.Empty()
+}
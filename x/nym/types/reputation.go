@@ -0,0 +1,63 @@
+// Copyright 2020 Nym Technologies SA
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package types
+
+import (
+	"fmt"
+)
+
+// Reputation changes applied for every IP version covered by an accepted uptime report,
+// mirroring the directory's ReportSuccessReputationIncrease and ReportFailureReputationDecrease
+const (
+	ReportSuccessReputationIncrease int32 = 3
+	ReportFailureReputationDecrease int32 = -2
+
+	// ReportSuccessUptimeThreshold is the minimum uptime, in percent, for a report to count as a success
+	ReportSuccessUptimeThreshold int32 = 50
+)
+
+// NodeReputation is the reputation of a node as maintained by the keeper
+type NodeReputation struct {
+	NodeID     string `json:"nodeId" yaml:"nodeId"`
+	Reputation int32  `json:"reputation" yaml:"reputation"`
+}
+
+// String implements the stringer interface for NodeReputation
+func (r NodeReputation) String() string {
+	return fmt.Sprintf(`NodeReputation:
+  Node:       %s
+  Reputation: %d`, r.NodeID, r.Reputation)
+}
+
+// ReputationChange returns the reputation change resulting from the given uptime report
+func ReputationChange(uptime NodeUptime) int32 {
+	return reputationChange(uptime.IPV4Uptime) + reputationChange(uptime.IPV6Uptime)
+}
+
+func reputationChange(uptime int32) int32 {
+	if uptime >= ReportSuccessUptimeThreshold {
+		return ReportSuccessReputationIncrease
+	}
+	return ReportFailureReputationDecrease
+}
+
+// ApplyReputationChange returns the reputation after applying the given change.
+// Like in the directory, a decrease that would make the reputation negative is not applied.
+func ApplyReputationChange(reputation, change int32) int32 {
+	if reputation+change < 0 {
+		return reputation
+	}
+	return reputation + change
+}
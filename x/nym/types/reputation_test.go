@@ -0,0 +1,46 @@
+// Copyright 2020 Nym Technologies SA
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReputationChange(t *testing.T) {
+	for name, test := range map[string]struct {
+		uptime NodeUptime
+		change int32
+	}{
+		"both up":           {NodeUptime{IPV4Uptime: 100, IPV6Uptime: 100}, 2 * ReportSuccessReputationIncrease},
+		"both at threshold": {NodeUptime{IPV4Uptime: 50, IPV6Uptime: 50}, 2 * ReportSuccessReputationIncrease},
+		"ipv6 below":        {NodeUptime{IPV4Uptime: 50, IPV6Uptime: 49}, ReportSuccessReputationIncrease + ReportFailureReputationDecrease},
+		"ipv4 down":         {NodeUptime{IPV4Uptime: 0, IPV6Uptime: 100}, ReportSuccessReputationIncrease + ReportFailureReputationDecrease},
+		"both below":        {NodeUptime{IPV4Uptime: 49, IPV6Uptime: 49}, 2 * ReportFailureReputationDecrease},
+		"both down":         {NodeUptime{IPV4Uptime: 0, IPV6Uptime: 0}, 2 * ReportFailureReputationDecrease},
+	} {
+		assert.Equal(t, test.change, ReputationChange(test.uptime), name)
+	}
+}
+
+func TestApplyReputationChange(t *testing.T) {
+	assert.Equal(t, int32(6), ApplyReputationChange(0, 6))
+	assert.Equal(t, int32(2), ApplyReputationChange(6, -4))
+	assert.Equal(t, int32(0), ApplyReputationChange(4, -4))
+	// decreases below zero are not applied at all rather than flooring the reputation
+	assert.Equal(t, int32(2), ApplyReputationChange(2, -4))
+	assert.Equal(t, int32(0), ApplyReputationChange(0, -4))
+}
@@ -0,0 +1,153 @@
+// Copyright 2020 Nym Technologies SA
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package types
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"regexp"
+	"strconv"
+
+	"github.com/btcsuite/btcutil/base58"
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+)
+
+const (
+	// KeyLength is the length in bytes of node ed25519 identity keys and x25519 sphinx keys
+	KeyLength = 32
+	// MaxLocationLength is the maximum length of the location a node can announce
+	MaxLocationLength = 100
+	// MaxIDLength is the maximum length of a node id
+	MaxIDLength = 64
+)
+
+// idRegex matches the node ids, made of letters, digits and hyphens like the uuids generated by the clients.
+// Separators such as `/` are excluded so ids can't collide with the store key prefixes built from them.
+var idRegex = regexp.MustCompile(`^[0-9a-zA-Z-]+$`)
+
+// semverRegex matches semantic versions as defined by https://semver.org
+var semverRegex = regexp.MustCompile(`^(0|[1-9]\d*)\.(0|[1-9]\d*)\.(0|[1-9]\d*)` +
+	`(?:-((?:0|[1-9]\d*|\d*[a-zA-Z-][0-9a-zA-Z-]*)(?:\.(?:0|[1-9]\d*|\d*[a-zA-Z-][0-9a-zA-Z-]*))*))?` +
+	`(?:\+([0-9a-zA-Z-]+(?:\.[0-9a-zA-Z-]+)*))?$`)
+
+// validateID makes sure the provided node id is non empty, not too long and only made of letters, digits and hyphens.
+func validateID(id string) error {
+	if len(id) > MaxIDLength {
+		return fmt.Errorf("id is longer than %d characters", MaxIDLength)
+	}
+	if !idRegex.MatchString(id) {
+		return fmt.Errorf("id %q must only contain letters, digits and hyphens", id)
+	}
+	return nil
+}
+
+// validateHost makes sure the provided address is of the form `host:port`, where port is a valid port number.
+func validateHost(host string) error {
+	hostname, port, err := net.SplitHostPort(host)
+	if err != nil {
+		return err
+	}
+	if hostname == "" {
+		return errors.New("missing hostname")
+	}
+	portNum, err := strconv.ParseUint(port, 10, 16)
+	if err != nil || portNum == 0 {
+		return fmt.Errorf("invalid port %q", port)
+	}
+	return nil
+}
+
+// validateClientListener makes sure the provided gateway client listener is either a plain `host:port`
+// or an url (such as `ws://host:port`) with a valid host and port.
+func validateClientListener(listener string) error {
+	if parsed, err := url.Parse(listener); err == nil && parsed.Scheme != "" && parsed.Host != "" {
+		return validateHost(parsed.Host)
+	}
+	return validateHost(listener)
+}
+
+// validateKey makes sure the provided key is a base58 encoded 32 byte ed25519 or x25519 key.
+func validateKey(key string) error {
+	if key == "" {
+		return errors.New("missing key")
+	}
+	decoded := base58.Decode(key)
+	if len(decoded) != KeyLength {
+		return fmt.Errorf("key %q is not a base58 encoded %d byte key", key, KeyLength)
+	}
+	return nil
+}
+
+// validateVersion makes sure the provided version is a valid semantic version.
+func validateVersion(version string) error {
+	if !semverRegex.MatchString(version) {
+		return fmt.Errorf("version %q is not a valid semantic version", version)
+	}
+	return nil
+}
+
+// validateLocation makes sure the provided location is not unreasonably long.
+func validateLocation(location string) error {
+	if len(location) > MaxLocationLength {
+		return fmt.Errorf("location is longer than %d characters", MaxLocationLength)
+	}
+	return nil
+}
+
+// validateMixnode performs the stateless validation of mixnode details shared by all mixnode messages.
+// Whether the layer is within the configured number of layers can only be checked by the handler.
+func validateMixnode(pubKey string, sphinxKey string, layer int32, version string, host string, location string) error {
+	if err := validateKey(pubKey); err != nil {
+		return sdkerrors.Wrapf(ErrInvalidKey, "identity key: %s", err)
+	}
+	if err := validateKey(sphinxKey); err != nil {
+		return sdkerrors.Wrapf(ErrInvalidKey, "sphinx key: %s", err)
+	}
+	if layer < 1 {
+		return sdkerrors.Wrapf(ErrInvalidLayer, "layer must be positive, got %d", layer)
+	}
+	if err := validateVersion(version); err != nil {
+		return sdkerrors.Wrap(ErrInvalidVersion, err.Error())
+	}
+	if err := validateHost(host); err != nil {
+		return sdkerrors.Wrap(ErrInvalidHost, err.Error())
+	}
+	if err := validateLocation(location); err != nil {
+		return sdkerrors.Wrap(ErrInvalidLocation, err.Error())
+	}
+	return nil
+}
+
+// validateGateway performs the stateless validation of gateway details shared by all gateway messages.
+func validateGateway(identityKey string, sphinxKey string, clientListener string, mixnetListener string, location string) error {
+	if err := validateKey(identityKey); err != nil {
+		return sdkerrors.Wrapf(ErrInvalidKey, "identity key: %s", err)
+	}
+	if err := validateKey(sphinxKey); err != nil {
+		return sdkerrors.Wrapf(ErrInvalidKey, "sphinx key: %s", err)
+	}
+	if err := validateClientListener(clientListener); err != nil {
+		return sdkerrors.Wrapf(ErrInvalidHost, "client listener: %s", err)
+	}
+	if err := validateHost(mixnetListener); err != nil {
+		return sdkerrors.Wrapf(ErrInvalidHost, "mixnet listener: %s", err)
+	}
+	if err := validateLocation(location); err != nil {
+		return sdkerrors.Wrap(ErrInvalidLocation, err.Error())
+	}
+	return nil
+}
@@ -0,0 +1,97 @@
+// Copyright 2020 Nym Technologies SA
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package types
+
+import (
+	"strings"
+	"testing"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMsgCreateMixnodeValidateBasic(t *testing.T) {
+	valid := func() MsgCreateMixnode {
+		return NewMsgCreateMixnode(creator, identityKey, otherIdentityKey, 1, DefaultSystemVersion, "1.1.1.1:1789", "Neuchatel", DefaultMinimumMixnodeBond)
+	}
+	assert.NoError(t, valid().ValidateBasic())
+
+	for name, test := range map[string]struct {
+		update func(*MsgCreateMixnode)
+		err    *sdkerrors.Error
+	}{
+		"hostname":                {func(m *MsgCreateMixnode) { m.Host = "mixnode.nymtech.net:1789" }, nil},
+		"ipv6 host":               {func(m *MsgCreateMixnode) { m.Host = "[2001:db8::1]:1789" }, nil},
+		"host without port":       {func(m *MsgCreateMixnode) { m.Host = "1.1.1.1" }, ErrInvalidHost},
+		"host without hostname":   {func(m *MsgCreateMixnode) { m.Host = ":1789" }, ErrInvalidHost},
+		"host with port zero":     {func(m *MsgCreateMixnode) { m.Host = "1.1.1.1:0" }, ErrInvalidHost},
+		"host with invalid port":  {func(m *MsgCreateMixnode) { m.Host = "1.1.1.1:65536" }, ErrInvalidHost},
+		"missing identity key":    {func(m *MsgCreateMixnode) { m.PubKey = "" }, ErrInvalidKey},
+		"short identity key":      {func(m *MsgCreateMixnode) { m.PubKey = identityKey[:20] }, ErrInvalidKey},
+		"non base58 identity key": {func(m *MsgCreateMixnode) { m.PubKey = "0OIl" + identityKey[4:] }, ErrInvalidKey},
+		"invalid sphinx key":      {func(m *MsgCreateMixnode) { m.SphinxKey = "key" }, ErrInvalidKey},
+		"prerelease version":      {func(m *MsgCreateMixnode) { m.Version = "0.9.2-rc.1+build" }, nil},
+		"partial version":         {func(m *MsgCreateMixnode) { m.Version = "0.9" }, ErrInvalidVersion},
+		"prefixed version":        {func(m *MsgCreateMixnode) { m.Version = "v0.9.2" }, ErrInvalidVersion},
+		"missing version":         {func(m *MsgCreateMixnode) { m.Version = "" }, ErrInvalidVersion},
+		"missing location":        {func(m *MsgCreateMixnode) { m.Location = "" }, nil},
+		"longest location":        {func(m *MsgCreateMixnode) { m.Location = strings.Repeat("a", MaxLocationLength) }, nil},
+		"too long location":       {func(m *MsgCreateMixnode) { m.Location = strings.Repeat("a", MaxLocationLength+1) }, ErrInvalidLocation},
+		"layer zero":              {func(m *MsgCreateMixnode) { m.Layer = 0 }, ErrInvalidLayer},
+		"missing creator":         {func(m *MsgCreateMixnode) { m.Creator = nil }, sdkerrors.ErrInvalidAddress},
+		"empty bond":              {func(m *MsgCreateMixnode) { m.Bond = sdk.NewInt64Coin(DefaultBondDenom, 0) }, sdkerrors.ErrInvalidCoins},
+	} {
+		msg := valid()
+		test.update(&msg)
+		err := msg.ValidateBasic()
+		if test.err == nil {
+			assert.NoError(t, err, name)
+		} else {
+			assert.True(t, test.err.Is(err), "%s: %v", name, err)
+		}
+	}
+}
+
+func TestMsgCreateGatewayValidateBasic(t *testing.T) {
+	valid := func() MsgCreateGateway {
+		return NewMsgCreateGateway(creator, identityKey, otherIdentityKey, "ws://1.1.1.1:9000", "1.1.1.1:1789", "Neuchatel", DefaultMinimumGatewayBond)
+	}
+	assert.NoError(t, valid().ValidateBasic())
+
+	for name, test := range map[string]struct {
+		update func(*MsgCreateGateway)
+		err    *sdkerrors.Error
+	}{
+		"plain client listener":          {func(m *MsgCreateGateway) { m.ClientListener = "1.1.1.1:9000" }, nil},
+		"secure client listener":         {func(m *MsgCreateGateway) { m.ClientListener = "wss://gateway.nymtech.net:9000" }, nil},
+		"client listener without port":   {func(m *MsgCreateGateway) { m.ClientListener = "ws://1.1.1.1" }, ErrInvalidHost},
+		"mixnet listener without port":   {func(m *MsgCreateGateway) { m.MixnetListener = "1.1.1.1" }, ErrInvalidHost},
+		"mixnet listener with an url":    {func(m *MsgCreateGateway) { m.MixnetListener = "ws://1.1.1.1:1789" }, ErrInvalidHost},
+		"missing identity key":           {func(m *MsgCreateGateway) { m.IdentityKey = "" }, ErrInvalidKey},
+		"too long sphinx key":            {func(m *MsgCreateGateway) { m.SphinxKey = identityKey + "1111" }, ErrInvalidKey},
+		"too long location":              {func(m *MsgCreateGateway) { m.Location = strings.Repeat("a", MaxLocationLength+1) }, ErrInvalidLocation},
+		"bond with invalid denomination": {func(m *MsgCreateGateway) { m.Bond = sdk.Coin{Denom: "NYM!", Amount: sdk.NewInt(100)} }, sdkerrors.ErrInvalidCoins},
+	} {
+		msg := valid()
+		test.update(&msg)
+		err := msg.ValidateBasic()
+		if test.err == nil {
+			assert.NoError(t, err, name)
+		} else {
+			assert.True(t, test.err.Is(err), "%s: %v", name, err)
+		}
+	}
+}